@@ -4,12 +4,15 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 
 	"github.com/0xredeth/Rafale/internal/codegen"
 	"github.com/0xredeth/Rafale/pkg/config"
+	"github.com/0xredeth/Rafale/pkg/decoder"
 )
 
 // Default output directory for generated files.
@@ -42,7 +45,7 @@ func init() {
 //
 // Returns:
 //   - error: nil on success, code generation error on failure
-func runCodegen(_ *cobra.Command, _ []string) error {
+func runCodegen(cmd *cobra.Command, _ []string) error {
 	cfg, err := config.Load()
 	if err != nil {
 		return fmt.Errorf("loading config: %w", err)
@@ -63,28 +66,49 @@ func runCodegen(_ *cobra.Command, _ []string) error {
 	for name, contract := range cfg.Contracts {
 		log.Info().
 			Str("contract", name).
-			Str("abi", contract.ABI).
+			Strs("abi", contract.ABIPaths()).
 			Int("events", len(contract.Events)).
 			Msg("generating bindings")
 
-		// Read ABI file
-		abiPath := contract.ABI
-		if !filepath.IsAbs(abiPath) {
-			// Make relative to current directory
-			cwd, err := os.Getwd()
+		// Read ABI files
+		abiJSONs := make([]string, 0, len(contract.ABIPaths()))
+		for _, abiPath := range contract.ABIPaths() {
+			if abiPath == config.ABIExplorer {
+				cacheDir := cfg.Explorer.CacheDir
+				if cacheDir != "" {
+					cacheDir = filepath.Join(cacheDir, strconv.FormatUint(cfg.ChainID, 10))
+				}
+				abiJSON, err := decoder.FetchABICached(cmd.Context(), cacheDir, cfg.Explorer.APIURL, cfg.Explorer.APIKey, common.HexToAddress(contract.Address))
+				if err != nil {
+					return fmt.Errorf("fetching ABI for %s: %w", name, err)
+				}
+				abiJSONs = append(abiJSONs, abiJSON)
+				continue
+			}
+			if !filepath.IsAbs(abiPath) {
+				// Make relative to current directory
+				cwd, err := os.Getwd()
+				if err != nil {
+					return fmt.Errorf("getting working directory: %w", err)
+				}
+				abiPath = filepath.Join(cwd, abiPath)
+			}
+
+			abiJSON, err := os.ReadFile(abiPath) //nolint:gosec // G304: Path is validated from config
 			if err != nil {
-				return fmt.Errorf("getting working directory: %w", err)
+				return fmt.Errorf("reading ABI for %s: %w", name, err)
 			}
-			abiPath = filepath.Join(cwd, abiPath)
+			abiJSONs = append(abiJSONs, string(abiJSON))
 		}
 
-		abiJSON, err := os.ReadFile(abiPath) //nolint:gosec // G304: Path is validated from config
+		// Facets of a diamond generate as one contract
+		abiJSON, err := decoder.MergeABIs(abiJSONs)
 		if err != nil {
-			return fmt.Errorf("reading ABI for %s: %w", name, err)
+			return fmt.Errorf("merging ABIs for %s: %w", name, err)
 		}
 
 		// Generate code
-		if err := gen.Generate(name, string(abiJSON), contract.Events); err != nil {
+		if err := gen.Generate(name, abiJSON, contract.Events); err != nil {
 			return fmt.Errorf("generating %s: %w", name, err)
 		}
 
@@ -80,6 +80,21 @@ func runReset(_ *cobra.Command, _ []string) error {
 	}
 	defer db.Close() //nolint:errcheck // Error on close is not actionable in defer
 
+	// Register the tables the engine rolls back with reorgs, so they're
+	// cleared with the rest
+	store.RegisterBlockTable(store.BlockCoverage{}.TableName())
+	store.RegisterBlockTable(store.RevertedTx{}.TableName())
+	for _, contract := range cfg.Contracts {
+		if contract.Table != "" {
+			store.RegisterBlockTable(contract.Table)
+		}
+	}
+	for _, rule := range cfg.Rules {
+		if rule.Table != "" {
+			store.RegisterBlockTable(rule.Table)
+		}
+	}
+
 	// Execute reset
 	if err := db.Reset(ctx); err != nil {
 		return fmt.Errorf("resetting data: %w", err)
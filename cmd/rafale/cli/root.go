@@ -10,12 +10,15 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/0xredeth/Rafale/internal/logging"
 	"github.com/0xredeth/Rafale/internal/version"
+	"github.com/0xredeth/Rafale/pkg/config"
+	"github.com/0xredeth/Rafale/pkg/handler"
 )
 
 var (
-	cfgFile string
-	verbose bool
+	cfgFiles []string
+	verbose  bool
 )
 
 // rootCmd is the base command for Rafale CLI.
@@ -44,7 +47,7 @@ func Execute() error {
 func init() {
 	cobra.OnInitialize(initConfig)
 
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is ./rafale.yaml)")
+	rootCmd.PersistentFlags().StringSliceVar(&cfgFiles, "config", nil, "config files or directories, later ones override earlier (default is ./rafale.yaml)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "enable verbose logging")
 
 	// Bind flags to viper
@@ -53,18 +56,21 @@ func init() {
 
 // initConfig reads in config file and ENV variables.
 func initConfig() {
-	if cfgFile != "" {
-		viper.SetConfigFile(cfgFile)
-	} else {
-		viper.SetConfigName("rafale")
-		viper.SetConfigType("yaml")
-		viper.AddConfigPath(".")
-		viper.AddConfigPath("$HOME/.rafale")
-	}
-
 	viper.SetEnvPrefix("RAFALE")
 	viper.AutomaticEnv()
 
+	if len(cfgFiles) > 0 {
+		if err := config.ReadFiles(cfgFiles...); err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading config: %v\n", err)
+		}
+		return
+	}
+
+	viper.SetConfigName("rafale")
+	viper.SetConfigType("yaml")
+	viper.AddConfigPath(".")
+	viper.AddConfigPath("$HOME/.rafale")
+
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
 			fmt.Fprintf(os.Stderr, "Error reading config: %v\n", err)
@@ -72,18 +78,22 @@ func initConfig() {
 	}
 }
 
-// setupLogging configures zerolog based on verbosity.
+// setupLogging configures zerolog based on verbosity. Subsystem levels
+// from config are applied once it is loaded (logging.Configure).
 func setupLogging() {
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
 
-	if verbose {
-		zerolog.SetGlobalLevel(zerolog.DebugLevel)
-	} else {
-		zerolog.SetGlobalLevel(zerolog.InfoLevel)
-	}
-
 	// Pretty console output for development
 	if os.Getenv("RAFALE_ENV") != "production" {
 		log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
 	}
+
+	if verbose {
+		logging.SetDefaultLevel(zerolog.DebugLevel)
+	} else {
+		logging.SetDefaultLevel(zerolog.InfoLevel)
+	}
+
+	// Handlers log as a subsystem (log.levels.handler)
+	handler.SetLogger(logging.New("handler"))
 }
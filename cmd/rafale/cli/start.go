@@ -7,13 +7,16 @@ import (
 	"os/signal"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/0xredeth/Rafale/internal/api"
 	"github.com/0xredeth/Rafale/internal/engine"
+	"github.com/0xredeth/Rafale/internal/logging"
 	"github.com/0xredeth/Rafale/internal/pubsub"
 	"github.com/0xredeth/Rafale/internal/rpc"
 	"github.com/0xredeth/Rafale/internal/store"
@@ -26,6 +29,10 @@ import (
 
 var watchMode bool
 
+// engineStopTimeout bounds how long shutdown waits for the batch being
+// committed; past it the batch is rolled back and re-indexed on restart.
+const engineStopTimeout = 30 * time.Second
+
 // startCmd starts the indexer.
 var startCmd = &cobra.Command{
 	Use:   "start",
@@ -57,6 +64,9 @@ func runStart(_ *cobra.Command, _ []string) error {
 	if err != nil {
 		return fmt.Errorf("loading config: %w", err)
 	}
+	if err := logging.Configure(cfg.Log.Levels); err != nil {
+		return fmt.Errorf("configuring log levels: %w", err)
+	}
 
 	log.Info().
 		Str("name", cfg.Name).
@@ -80,6 +90,8 @@ func runStart(_ *cobra.Command, _ []string) error {
 	// Initialize RPC client
 	rpcCfg := rpc.DefaultConfig()
 	rpcCfg.URL = cfg.RPCURL
+	rpcCfg.MaxConcurrentPerEndpoint = cfg.RPC.MaxConcurrentPerEndpoint
+	rpcCfg.MaxResponseBytes = cfg.RPC.MaxResponseBytes
 	rpcClient, err := rpc.New(ctx, rpcCfg)
 	if err != nil {
 		return fmt.Errorf("creating RPC client: %w", err)
@@ -89,6 +101,13 @@ func runStart(_ *cobra.Command, _ []string) error {
 	// Initialize store
 	storeCfg := store.DefaultConfig()
 	storeCfg.DSN = cfg.Database
+	if cfg.Sync.PrimaryKey != "" {
+		storeCfg.PrimaryKey = cfg.Sync.PrimaryKey
+	}
+	if cfg.Sync.StoreAddressCase != "" {
+		storeCfg.AddressCase = cfg.Sync.StoreAddressCase
+	}
+	storeCfg.CacheSize = cfg.Server.CacheSize
 	db, err := store.New(storeCfg)
 	if err != nil {
 		return fmt.Errorf("creating store: %w", err)
@@ -97,6 +116,10 @@ func runStart(_ *cobra.Command, _ []string) error {
 
 	// Initialize broadcaster for real-time subscriptions
 	broadcaster := pubsub.NewBroadcaster()
+	broadcaster.SetEventBuffer(cfg.Server.SubscriptionBuffer)
+	if cfg.Server.OrderedEvents {
+		broadcaster.EnableOrdering(cfg.Server.OrderTimeout)
+	}
 
 	// Initialize engine
 	eng, err := engine.New(cfg, broadcaster)
@@ -112,8 +135,11 @@ func runStart(_ *cobra.Command, _ []string) error {
 	// Initialize API server
 	apiServer := api.NewServer(cfg, db, rpcClient, broadcaster)
 
-	// Run all services concurrently
-	g, gctx := errgroup.WithContext(ctx)
+	// Run all services concurrently. Services stop once the engine does,
+	// including when a fixed block window has been fully indexed.
+	runCtx, stopServices := context.WithCancel(ctx)
+	defer stopServices()
+	g, gctx := errgroup.WithContext(runCtx)
 
 	// Setup watch mode if enabled
 	if watchMode {
@@ -141,9 +167,21 @@ func runStart(_ *cobra.Command, _ []string) error {
 
 	// Run indexer engine
 	g.Go(func() error {
+		// On shutdown, let the batch being committed finish and persist
+		// the sync cursor, within engineStopTimeout
+		go func() {
+			<-gctx.Done()
+			stopCtx, cancel := context.WithTimeout(context.Background(), engineStopTimeout)
+			defer cancel()
+			if err := eng.Stop(stopCtx); err != nil {
+				log.Error().Err(err).Msg("error stopping engine")
+			}
+		}()
+
 		if err := eng.Run(gctx); err != nil {
 			return fmt.Errorf("engine: %w", err)
 		}
+		stopServices()
 		return nil
 	})
 
@@ -157,12 +195,22 @@ func runStart(_ *cobra.Command, _ []string) error {
 
 	// Run metrics server
 	g.Go(func() error {
-		if err := apiServer.StartMetrics(gctx); err != nil {
+		if err := apiServer.StartMetrics(gctx, eng); err != nil {
 			return fmt.Errorf("metrics server: %w", err)
 		}
 		return nil
 	})
 
+	// Run admin API server (optional)
+	if cfg.Server.AdminPort > 0 {
+		g.Go(func() error {
+			if err := apiServer.StartAdmin(gctx, eng); err != nil {
+				return fmt.Errorf("admin server: %w", err)
+			}
+			return nil
+		})
+	}
+
 	// Wait for all services to complete
 	if err := g.Wait(); err != nil {
 		log.Error().Err(err).Msg("service error")
@@ -197,13 +245,22 @@ func setupWatchMode(eng *engine.Engine) (*watcher.Watcher, error) {
 
 		log.Info().Msg("config file changed, reloading...")
 
-		// Reload configuration
-		newCfg, err := config.Load()
+		// Re-read config files so the change is picked up
+		files := cfgFiles
+		if len(files) == 0 && viper.ConfigFileUsed() != "" {
+			files = []string{viper.ConfigFileUsed()}
+		}
+		newCfg, err := config.Load(files...)
 		if err != nil {
 			log.Error().Err(err).Msg("failed to reload config")
 			return
 		}
 
+		if err := logging.Configure(newCfg.Log.Levels); err != nil {
+			log.Error().Err(err).Msg("failed to reload log levels")
+			return
+		}
+
 		// Reload engine with new config
 		if err := eng.Reload(newCfg); err != nil {
 			log.Error().Err(err).Msg("failed to reload engine")
@@ -0,0 +1,265 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0xredeth/Rafale/internal/engine"
+)
+
+// AdminController is the runtime control surface exposed by the admin API.
+type AdminController interface {
+	Pause()
+	Resume()
+	Status() engine.Status
+	AddContract(name, address, abiPath string, events []string) error
+	RemoveContract(name string) error
+	StartReindex(ctx context.Context, fromBlock, toBlock uint64) (string, error)
+	Jobs() []engine.JobStatus
+	CancelJob(id string) error
+	HandlerMap() map[string]string
+	UnboundHandlers() map[string]string
+}
+
+// handlersResponse is the JSON body of GET /admin/handlers.
+type handlersResponse struct {
+	// Events maps each decoded event ID to its handler ("" = stored only).
+	Events map[string]string `json:"events"`
+
+	// Unbound maps handlers matching no decoded event to a near miss.
+	Unbound map[string]string `json:"unbound"`
+}
+
+// reindexResponse is the JSON body of an accepted POST /admin/reindex.
+type reindexResponse struct {
+	ID   string `json:"id"`
+	From uint64 `json:"from"`
+	To   uint64 `json:"to"`
+}
+
+// addContractRequest is the JSON body for POST /admin/contracts.
+type addContractRequest struct {
+	Name    string   `json:"name"`
+	Address string   `json:"address"`
+	ABI     string   `json:"abi"`
+	Events  []string `json:"events"`
+}
+
+// StartAdmin starts the authenticated admin API server.
+//
+// Parameters:
+//   - ctx (context.Context): context for shutdown
+//   - ctrl (AdminController): engine runtime control
+//
+// Returns:
+//   - error: nil on graceful shutdown, error on failure
+func (s *Server) StartAdmin(ctx context.Context, ctrl AdminController) error {
+	addr := fmt.Sprintf(":%d", s.cfg.Server.AdminPort)
+	adminServer := &http.Server{
+		Addr:         addr,
+		Handler:      adminHandler(ctx, s.cfg.Server.AdminToken, ctrl),
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	}
+
+	log.Info().
+		Int("port", s.cfg.Server.AdminPort).
+		Msg("starting admin server")
+
+	errCh := make(chan error, 1)
+	go func() {
+		if err := adminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errCh <- err
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		log.Info().Msg("shutting down admin server")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return adminServer.Shutdown(shutdownCtx)
+	case err := <-errCh:
+		return fmt.Errorf("admin server error: %w", err)
+	}
+}
+
+// adminHandler routes the admin API behind bearer token authentication.
+//
+// Parameters:
+//   - ctx (context.Context): server lifetime, bounding background jobs
+//   - token (string): admin bearer token
+//   - ctrl (AdminController): engine runtime control
+//
+// Returns:
+//   - http.Handler: the admin routes
+func adminHandler(ctx context.Context, token string, ctrl AdminController) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/admin/status", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeAdminError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		writeAdminJSON(w, http.StatusOK, ctrl.Status())
+	})
+
+	mux.HandleFunc("/admin/pause", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeAdminError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		ctrl.Pause()
+		writeAdminJSON(w, http.StatusOK, ctrl.Status())
+	})
+
+	mux.HandleFunc("/admin/resume", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeAdminError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		ctrl.Resume()
+		writeAdminJSON(w, http.StatusOK, ctrl.Status())
+	})
+
+	mux.HandleFunc("/admin/contracts", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			var req addContractRequest
+			if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+				writeAdminError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+				return
+			}
+			if err := ctrl.AddContract(req.Name, req.Address, req.ABI, req.Events); err != nil {
+				writeAdminError(w, adminErrorStatus(err), err.Error())
+				return
+			}
+			writeAdminJSON(w, http.StatusCreated, ctrl.Status())
+
+		case http.MethodDelete:
+			name := r.URL.Query().Get("name")
+			if name == "" {
+				writeAdminError(w, http.StatusBadRequest, "name query parameter is required")
+				return
+			}
+			if err := ctrl.RemoveContract(name); err != nil {
+				writeAdminError(w, adminErrorStatus(err), err.Error())
+				return
+			}
+			writeAdminJSON(w, http.StatusOK, ctrl.Status())
+
+		default:
+			writeAdminError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	})
+
+	mux.HandleFunc("/admin/reindex", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeAdminError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+
+		from, err := strconv.ParseUint(r.URL.Query().Get("from"), 10, 64)
+		if err != nil {
+			writeAdminError(w, http.StatusBadRequest, "invalid from block")
+			return
+		}
+		to, err := strconv.ParseUint(r.URL.Query().Get("to"), 10, 64)
+		if err != nil {
+			writeAdminError(w, http.StatusBadRequest, "invalid to block")
+			return
+		}
+
+		// Reindexing can take a long time; it runs in the background tied to
+		// the server lifetime rather than the request. The engine validates
+		// the range and claims the reindex atomically.
+		id, err := ctrl.StartReindex(ctx, from, to)
+		if err != nil {
+			writeAdminError(w, adminErrorStatus(err), err.Error())
+			return
+		}
+
+		writeAdminJSON(w, http.StatusAccepted, reindexResponse{ID: id, From: from, To: to})
+	})
+
+	mux.HandleFunc("/admin/handlers", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeAdminError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		writeAdminJSON(w, http.StatusOK, handlersResponse{
+			Events:  ctrl.HandlerMap(),
+			Unbound: ctrl.UnboundHandlers(),
+		})
+	})
+
+	mux.HandleFunc("/admin/jobs", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			writeAdminJSON(w, http.StatusOK, ctrl.Jobs())
+
+		case http.MethodDelete:
+			id := r.URL.Query().Get("id")
+			if id == "" {
+				writeAdminError(w, http.StatusBadRequest, "id query parameter is required")
+				return
+			}
+			if err := ctrl.CancelJob(id); err != nil {
+				writeAdminError(w, adminErrorStatus(err), err.Error())
+				return
+			}
+			writeAdminJSON(w, http.StatusAccepted, map[string]string{"id": id})
+
+		default:
+			writeAdminError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	})
+
+	return requireBearerToken(token, mux)
+}
+
+// requireBearerToken rejects requests without a matching Authorization bearer token.
+func requireBearerToken(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		provided, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="rafale-admin"`)
+			writeAdminError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// adminErrorStatus maps engine control errors to HTTP status codes.
+func adminErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, engine.ErrContractNotFound), errors.Is(err, engine.ErrJobNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, engine.ErrContractExists), errors.Is(err, engine.ErrReindexInProgress):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// writeAdminJSON writes v as a JSON response.
+func writeAdminJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Debug().Err(err).Msg("admin response write failed")
+	}
+}
+
+// writeAdminError writes a JSON error response.
+func writeAdminError(w http.ResponseWriter, status int, msg string) {
+	writeAdminJSON(w, status, map[string]string{"error": msg})
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xredeth/Rafale/internal/engine"
+)
+
+// fakeAdmin is an AdminController recording reindex requests; methods it
+// doesn't override panic through the nil embedded interface.
+type fakeAdmin struct {
+	AdminController
+
+	reindexErr error
+	reindexed  [][2]uint64
+}
+
+func (f *fakeAdmin) StartReindex(_ context.Context, fromBlock, toBlock uint64) (string, error) {
+	if f.reindexErr != nil {
+		return "", f.reindexErr
+	}
+	f.reindexed = append(f.reindexed, [2]uint64{fromBlock, toBlock})
+	return fmt.Sprintf("reindex-%d", len(f.reindexed)), nil
+}
+
+func (f *fakeAdmin) Status() engine.Status {
+	return engine.Status{LastBlock: 1000}
+}
+
+func TestAdminAuth(t *testing.T) {
+	handler := adminHandler(context.Background(), "secret", &fakeAdmin{})
+
+	tests := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{name: "no token", want: http.StatusUnauthorized},
+		{name: "wrong token", header: "Bearer nope", want: http.StatusUnauthorized},
+		{name: "not a bearer token", header: "secret", want: http.StatusUnauthorized},
+		{name: "valid token", header: "Bearer secret", want: http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/admin/status", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			require.Equal(t, tc.want, rec.Code)
+		})
+	}
+}
+
+func TestAdminReindex(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		query      string
+		reindexErr error
+		want       int
+		wantID     string
+		wantRange  [][2]uint64
+	}{
+		{name: "started", method: http.MethodPost, query: "from=100&to=200", want: http.StatusAccepted, wantID: "reindex-1", wantRange: [][2]uint64{{100, 200}}},
+		{name: "wrong method", method: http.MethodGet, query: "from=100&to=200", want: http.StatusMethodNotAllowed},
+		{name: "missing from", method: http.MethodPost, query: "to=200", want: http.StatusBadRequest},
+		{name: "invalid to", method: http.MethodPost, query: "from=100&to=x", want: http.StatusBadRequest},
+		{name: "invalid range", method: http.MethodPost, query: "from=200&to=100", reindexErr: fmt.Errorf("%w: 200-100", engine.ErrInvalidRange), want: http.StatusBadRequest},
+		{name: "already running", method: http.MethodPost, query: "from=100&to=200", reindexErr: engine.ErrReindexInProgress, want: http.StatusConflict},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := &fakeAdmin{reindexErr: tc.reindexErr}
+			handler := adminHandler(context.Background(), "secret", ctrl)
+
+			req := httptest.NewRequest(tc.method, "/admin/reindex?"+tc.query, nil)
+			req.Header.Set("Authorization", "Bearer secret")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.want, rec.Code, rec.Body.String())
+			require.Equal(t, tc.wantRange, ctrl.reindexed)
+			if tc.want == http.StatusAccepted {
+				var resp reindexResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+				require.Equal(t, tc.wantID, resp.ID)
+			}
+		})
+	}
+}
@@ -55,15 +55,16 @@ type EventOrder struct {
 }
 
 type GenericEvent struct {
-	ID          string         `json:"id"`
-	BlockNumber string         `json:"blockNumber"`
-	TxHash      string         `json:"txHash"`
-	TxIndex     int            `json:"txIndex"`
-	LogIndex    int            `json:"logIndex"`
-	Timestamp   time.Time      `json:"timestamp"`
-	Contract    string         `json:"contract"`
-	EventName   string         `json:"eventName"`
-	Data        map[string]any `json:"data"`
+	ID             string         `json:"id"`
+	BlockNumber    string         `json:"blockNumber"`
+	TxHash         string         `json:"txHash"`
+	TxIndex        int            `json:"txIndex"`
+	LogIndex       int            `json:"logIndex"`
+	Timestamp      time.Time      `json:"timestamp"`
+	Contract       string         `json:"contract"`
+	EventName      string         `json:"eventName"`
+	Data           map[string]any `json:"data"`
+	CommittedBlock *string        `json:"committedBlock,omitempty"`
 }
 
 func (GenericEvent) IsEvent()                     {}
@@ -89,6 +90,13 @@ type Query struct {
 type Subscription struct {
 }
 
+type SyncStateChange struct {
+	Type      string    `json:"type"`
+	Block     string    `json:"block"`
+	HeadBlock string    `json:"headBlock"`
+	Time      time.Time `json:"time"`
+}
+
 type SyncStatus struct {
 	Network      string     `json:"network"`
 	ChainID      string     `json:"chainID"`
@@ -6,11 +6,11 @@ package resolver
 
 import (
 	"context"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"math/big"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/0xredeth/Rafale/internal/api/graphql/generated"
@@ -60,7 +60,7 @@ func (r *queryResolver) Block(ctx context.Context, number string) (*model.Block,
 	return &model.Block{
 		Number:     strconv.FormatUint(header.Number.Uint64(), 10),
 		Hash:       header.Hash().Hex(),
-		Timestamp:  time.Unix(int64(header.Time), 0), //nolint:gosec // G115: Timestamp won't overflow
+		Timestamp:  time.Unix(int64(header.Time), 0).UTC(), //nolint:gosec // G115: Timestamp won't overflow
 		ParentHash: header.ParentHash.Hex(),
 	}, nil
 }
@@ -138,20 +138,37 @@ func (r *queryResolver) Events(ctx context.Context, filter *model.EventFilter, o
 	}
 	q.Limit = limit + 1 // fetch one extra to determine hasNextPage
 
-	// Parse cursor
+	// Parse cursor: natural keys page by (tx_hash, log_index), ids by id
+	natural := r.Store.NaturalKeys()
 	if after != nil {
-		id, err := decodeCursor(*after)
-		if err != nil {
-			return nil, fmt.Errorf("invalid after cursor: %w", err)
+		if natural {
+			key, err := store.DecodeEventKeyCursor(*after)
+			if err != nil {
+				return nil, fmt.Errorf("invalid after cursor: %w", err)
+			}
+			q.AfterKey = &key
+		} else {
+			id, err := decodeCursor(*after)
+			if err != nil {
+				return nil, fmt.Errorf("invalid after cursor: %w", err)
+			}
+			q.AfterID = &id
 		}
-		q.AfterID = &id
 	}
 	if before != nil {
-		id, err := decodeCursor(*before)
-		if err != nil {
-			return nil, fmt.Errorf("invalid before cursor: %w", err)
+		if natural {
+			key, err := store.DecodeEventKeyCursor(*before)
+			if err != nil {
+				return nil, fmt.Errorf("invalid before cursor: %w", err)
+			}
+			q.BeforeKey = &key
+		} else {
+			id, err := decodeCursor(*before)
+			if err != nil {
+				return nil, fmt.Errorf("invalid before cursor: %w", err)
+			}
+			q.BeforeID = &id
 		}
-		q.BeforeID = &id
 	}
 
 	// Execute query against generic events table
@@ -170,10 +187,13 @@ func (r *queryResolver) Events(ctx context.Context, filter *model.EventFilter, o
 	// Build edges
 	edges := make([]*model.EventEdge, len(events))
 	for i, e := range events {
-		event := eventToGenericEvent(&e)
+		cursor := encodeCursor(e.ID)
+		if natural {
+			cursor = store.EventKey{TxHash: e.TxHash, LogIndex: e.LogIndex}.Cursor()
+		}
 		edges[i] = &model.EventEdge{
-			Cursor: encodeCursor(e.ID),
-			Node:   event,
+			Cursor: cursor,
+			Node:   eventToGenericEvent(&e),
 		}
 	}
 
@@ -196,15 +216,26 @@ func (r *queryResolver) Events(ctx context.Context, filter *model.EventFilter, o
 	}, nil
 }
 
-// Event is the resolver for the event field.
+// Event is the resolver for the event field. The id is an event id or a
+// natural key "txHash:logIndex" (indexed under sync.primary_key natural).
 func (r *queryResolver) Event(ctx context.Context, id string) (*model.GenericEvent, error) {
-	// Parse ID
-	eventID, err := strconv.ParseUint(id, 10, 64)
-	if err != nil {
-		return nil, fmt.Errorf("invalid event id: %w", err)
+	var (
+		event *store.Event
+		err   error
+	)
+	if strings.Contains(id, ":") {
+		key, keyErr := store.ParseEventKey(id)
+		if keyErr != nil {
+			return nil, fmt.Errorf("invalid event id: %w", keyErr)
+		}
+		event, err = r.Store.GetEventByKey(ctx, key)
+	} else {
+		eventID, idErr := strconv.ParseUint(id, 10, 64)
+		if idErr != nil {
+			return nil, fmt.Errorf("invalid event id: %w", idErr)
+		}
+		event, err = r.Store.GetEventByID(ctx, eventID)
 	}
-
-	event, err := r.Store.GetEventByID(ctx, eventID)
 	if err != nil {
 		return nil, fmt.Errorf("getting event: %w", err)
 	}
@@ -273,6 +304,21 @@ func (r *subscriptionResolver) SyncStatusUpdated(ctx context.Context) (<-chan *m
 	return ch, nil
 }
 
+// SyncStateChanged is the resolver for the syncStateChanged field.
+// Subscribers receive the current sync state, then every transition
+// between backfilling and synced.
+//
+// Parameters:
+//   - ctx (context.Context): context for subscription lifecycle
+//
+// Returns:
+//   - <-chan *model.SyncStateChange: channel streaming state changes
+//   - error: nil on success
+func (r *subscriptionResolver) SyncStateChanged(ctx context.Context) (<-chan *model.SyncStateChange, error) {
+	ch, _ := r.Broadcaster.SubscribeSyncState(ctx)
+	return ch, nil
+}
+
 // Query returns generated.QueryResolver implementation.
 func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
 
@@ -286,16 +332,16 @@ type subscriptionResolver struct{ *Resolver }
 
 // encodeCursor encodes a transfer ID into a cursor string.
 func encodeCursor(id uint64) string {
-	return base64.StdEncoding.EncodeToString([]byte(strconv.FormatUint(id, 10)))
+	return store.EncodeCursor(strconv.FormatUint(id, 10))
 }
 
 // decodeCursor decodes a cursor string into a transfer ID.
 func decodeCursor(cursor string) (uint64, error) {
-	decoded, err := base64.StdEncoding.DecodeString(cursor)
+	parts, err := store.DecodeCursor(cursor, 1)
 	if err != nil {
-		return 0, fmt.Errorf("decoding cursor: %w", err)
+		return 0, err
 	}
-	id, err := strconv.ParseUint(string(decoded), 10, 64)
+	id, err := strconv.ParseUint(parts[0], 10, 64)
 	if err != nil {
 		return 0, fmt.Errorf("parsing cursor id: %w", err)
 	}
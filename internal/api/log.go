@@ -0,0 +1,6 @@
+package api
+
+import "github.com/0xredeth/Rafale/internal/logging"
+
+// log is the api subsystem's logger (level set by log.levels.api).
+var log = logging.New("api")
@@ -15,11 +15,11 @@ import (
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/gorilla/websocket"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
-	"github.com/rs/zerolog/log"
 	"github.com/vektah/gqlparser/v2/ast"
 
 	"github.com/0xredeth/Rafale/internal/api/graphql/generated"
 	"github.com/0xredeth/Rafale/internal/api/graphql/resolver"
+	"github.com/0xredeth/Rafale/internal/engine"
 	"github.com/0xredeth/Rafale/internal/pubsub"
 	"github.com/0xredeth/Rafale/internal/rpc"
 	"github.com/0xredeth/Rafale/internal/store"
@@ -149,16 +149,27 @@ func (s *Server) Start(ctx context.Context) error {
 	}
 }
 
-// StartMetrics starts the metrics server.
+// HealthChecker reports the indexer's health for /healthz and /readyz.
+type HealthChecker interface {
+	Health() engine.HealthStatus
+}
+
+// StartMetrics starts the metrics server, which also serves /healthz and
+// /readyz.
 //
 // Parameters:
 //   - ctx (context.Context): context for shutdown
+//   - health (HealthChecker): engine health (nil = no /healthz or /readyz)
 //
 // Returns:
 //   - error: nil on graceful shutdown, error on failure
-func (s *Server) StartMetrics(ctx context.Context) error {
+func (s *Server) StartMetrics(ctx context.Context, health HealthChecker) error {
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", promhttp.Handler())
+	if health != nil {
+		mux.HandleFunc("/healthz", s.healthzHandler(health, false))
+		mux.HandleFunc("/readyz", s.healthzHandler(health, true))
+	}
 
 	addr := fmt.Sprintf(":%d", s.cfg.Server.MetricsPort)
 	metricsServer := &http.Server{
@@ -189,3 +200,29 @@ func (s *Server) StartMetrics(ctx context.Context) error {
 		return fmt.Errorf("metrics server error: %w", err)
 	}
 }
+
+// healthzHandler serves the engine's health as JSON: 200 when the RPC node
+// and database answer, 503 otherwise. For readiness, sync must also be
+// within server.health_max_lag blocks of the head, so a backfilling node
+// gets no traffic but isn't restarted by a liveness probe.
+//
+// Parameters:
+//   - health (HealthChecker): engine health
+//   - ready (bool): also check the sync lag (/readyz)
+//
+// Returns:
+//   - http.HandlerFunc: the /healthz or /readyz handler
+func (s *Server) healthzHandler(health HealthChecker, ready bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		status := health.Health()
+		ok := status.Connected()
+		if ready {
+			ok = status.Healthy(s.cfg.Server.HealthMaxLag)
+		}
+		code := http.StatusOK
+		if !ok {
+			code = http.StatusServiceUnavailable
+		}
+		writeAdminJSON(w, code, status)
+	}
+}
@@ -0,0 +1,23 @@
+// Package checkpoint mirrors the engine's sync cursor to external storage,
+// so it can be read without a database round-trip.
+package checkpoint
+
+import "context"
+
+// Store holds sync cursors by key. The database stays authoritative: a
+// Store is a best-effort mirror, reconciled against it at startup.
+type Store interface {
+	// Load returns the block stored under key.
+	//
+	// Returns:
+	//   - uint64: the stored block
+	//   - bool: false if nothing is stored under key
+	//   - error: nil on success, error on failure
+	Load(ctx context.Context, key string) (uint64, bool, error)
+
+	// Save stores block under key.
+	Save(ctx context.Context, key string, block uint64) error
+
+	// Close releases the store's resources.
+	Close() error
+}
@@ -0,0 +1,114 @@
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis is a Store backed by Redis string keys, through a go-redis client
+// that pools connections and re-dials after failures.
+type Redis struct {
+	client *redis.Client
+}
+
+// NewRedis parses a Redis URL (redis://[user:password@]host[:port][/db],
+// or rediss:// for TLS; go-redis options such as dial_timeout may be set
+// as query parameters). The connection is opened on first use.
+//
+// Parameters:
+//   - rawURL (string): Redis URL
+//
+// Returns:
+//   - *Redis: the store
+//   - error: nil on success, error for an invalid URL
+func NewRedis(rawURL string) (*Redis, error) {
+	opts, err := redisOptions(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return &Redis{client: redis.NewClient(opts)}, nil
+}
+
+// redisOptions parses a Redis URL into client options.
+//
+// Parameters:
+//   - rawURL (string): Redis URL
+//
+// Returns:
+//   - *redis.Options: the client options
+//   - error: nil on success, error for an invalid URL
+func redisOptions(rawURL string) (*redis.Options, error) {
+	// go-redis defaults a missing host to localhost; require one instead
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing redis URL: %w", err)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("redis URL has no host")
+	}
+
+	opts, err := redis.ParseURL(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing redis URL: %w", err)
+	}
+	if opts.Password == "" && opts.Username != "" {
+		// redis://secret@host: the lone field is the password
+		opts.Username, opts.Password = "", opts.Username
+	}
+	return opts, nil
+}
+
+// Load returns the block stored under key.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - key (string): cursor key
+//
+// Returns:
+//   - uint64: the stored block
+//   - bool: false if the key doesn't exist
+//   - error: nil on success, connection, Redis or parse error on failure
+func (r *Redis) Load(ctx context.Context, key string) (uint64, bool, error) {
+	reply, err := r.client.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("loading checkpoint %s: %w", key, err)
+	}
+
+	block, err := strconv.ParseUint(reply, 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing checkpoint %s: %w", key, err)
+	}
+	return block, true, nil
+}
+
+// Save stores block under key.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - key (string): cursor key
+//   - block (uint64): block to store
+//
+// Returns:
+//   - error: nil on success, connection or Redis error on failure
+func (r *Redis) Save(ctx context.Context, key string, block uint64) error {
+	if err := r.client.Set(ctx, key, strconv.FormatUint(block, 10), 0).Err(); err != nil {
+		return fmt.Errorf("saving checkpoint %s: %w", key, err)
+	}
+	return nil
+}
+
+// Close closes the client and its connections.
+//
+// Returns:
+//   - error: nil on success, close error on failure
+func (r *Redis) Close() error {
+	return r.client.Close()
+}
@@ -118,7 +118,7 @@ func (g *Generator) Generate(contractName, abiJSON string, events []string) erro
 	// Collect event info
 	var eventInfos []EventInfo
 	for eventName, event := range parsedABI.Events {
-		if !eventFilter[eventName] {
+		if len(events) > 0 && !eventFilter[eventName] {
 			continue
 		}
 
@@ -0,0 +1,81 @@
+package engine
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Reasons an automatic analyze runs.
+const (
+	analyzeAtHead = "head"
+	analyzeRows   = "rows"
+)
+
+var autoAnalyzeRuns = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "rafale_auto_analyze_total",
+		Help: "Total number of automatic ANALYZE runs on the event tables",
+	},
+	[]string{"trigger"},
+)
+
+// analyzeDue records a processed batch and reports whether statistics
+// should be refreshed: the first time sync reaches the head, and after
+// threshold logs. Nothing is due while an analyze is running; the counts
+// carry over to the next batch. A returned trigger claims the run, which
+// the caller must release through runAnalyze.
+//
+// Parameters:
+//   - logCount (int): logs in the batch
+//   - atHead (bool): whether the batch reached the chain head
+//   - threshold (int64): logs between analyzes (0 = only at the head)
+//
+// Returns:
+//   - string: analyzeAtHead or analyzeRows if due, "" otherwise
+func (e *Engine) analyzeDue(logCount int, atHead bool, threshold int64) string {
+	rows := e.analyzeLogs.Add(int64(logCount))
+
+	trigger := ""
+	switch {
+	case atHead && !e.analyzedAtHead.Load():
+		trigger = analyzeAtHead
+	case threshold > 0 && rows >= threshold:
+		trigger = analyzeRows
+	default:
+		return ""
+	}
+
+	if !e.analyzing.CompareAndSwap(false, true) {
+		return ""
+	}
+	if trigger == analyzeAtHead {
+		e.analyzedAtHead.Store(true)
+	}
+	e.analyzeLogs.Store(0)
+	return trigger
+}
+
+// runAnalyze refreshes the event tables' statistics and releases the run
+// claimed by analyzeDue. Failures are logged: stale statistics only slow
+// queries down.
+//
+// Parameters:
+//   - ctx (context.Context): context for cancellation
+//   - trigger (string): why the analyze runs
+func (e *Engine) runAnalyze(ctx context.Context, trigger string) {
+	defer e.analyzing.Store(false)
+
+	start := time.Now()
+	if err := e.store.AnalyzeTables(ctx); err != nil {
+		log.Warn().Err(err).Str("trigger", trigger).Msg("automatic analyze failed")
+		return
+	}
+	autoAnalyzeRuns.WithLabelValues(trigger).Inc()
+	log.Info().
+		Str("trigger", trigger).
+		Dur("duration", time.Since(start)).
+		Msg("refreshed event table statistics")
+}
@@ -0,0 +1,103 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// maxBlockHashAttempts bounds the fetches of a batch whose logs keep
+// disagreeing with the canonical block hashes.
+const maxBlockHashAttempts = 3
+
+var blockHashMismatches = promauto.NewCounter(
+	prometheus.CounterOpts{
+		Name: "rafale_block_hash_mismatches_total",
+		Help: "Total number of fetched logs rejected for a non-canonical block hash",
+	},
+)
+
+// fetchCanonicalLogs fetches logs for a sync batch like fetchSyncLogs and
+// verifies them against the canonical block hashes (sync.verify_block_hashes).
+// A batch with logs from an orphaned block is discarded and re-fetched.
+//
+// Parameters:
+//   - ctx (context.Context): context for cancellation
+//   - fromBlock (uint64): first block of the batch
+//   - toBlock (uint64): last block of the batch
+//
+// Returns:
+//   - []types.Log: verified logs ordered by block and log index
+//   - func(): advances schedule cursors
+//   - error: nil on success, RPC error or persistent mismatch on failure
+func (e *Engine) fetchCanonicalLogs(ctx context.Context, fromBlock, toBlock uint64) ([]types.Log, func(), error) {
+	for attempt := 1; ; attempt++ {
+		logs, commit, err := e.fetchSyncLogs(ctx, fromBlock, toBlock)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		stale, err := nonCanonicalLogs(ctx, logs, e.canonicalHash)
+		if err != nil {
+			return nil, nil, err
+		}
+		if stale == 0 {
+			return logs, commit, nil
+		}
+
+		blockHashMismatches.Add(float64(stale))
+		log.Warn().
+			Uint64("from", fromBlock).
+			Uint64("to", toBlock).
+			Int("logs", stale).
+			Int("attempt", attempt).
+			Msg("fetched logs from a non-canonical block, re-fetching")
+
+		if attempt == maxBlockHashAttempts {
+			return nil, nil, fmt.Errorf("logs for blocks %d-%d still not canonical after %d attempts", fromBlock, toBlock, attempt)
+		}
+	}
+}
+
+// nonCanonicalLogs counts the logs whose block hash differs from the
+// canonical hash of their block. Each block is looked up once.
+//
+// Parameters:
+//   - ctx (context.Context): context for cancellation
+//   - logs ([]types.Log): fetched logs
+//   - fetch (hashFetcher): canonical hash lookup
+//
+// Returns:
+//   - int: number of logs from non-canonical blocks
+//   - error: nil on success, RPC error on failure
+func nonCanonicalLogs(ctx context.Context, logs []types.Log, fetch hashFetcher) (int, error) {
+	byBlock := make(map[uint64][]common.Hash)
+	for _, l := range logs {
+		byBlock[l.BlockNumber] = append(byBlock[l.BlockNumber], l.BlockHash)
+	}
+
+	blocks := make([]uint64, 0, len(byBlock))
+	for number := range byBlock {
+		blocks = append(blocks, number)
+	}
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i] < blocks[j] })
+
+	var stale int
+	for _, number := range blocks {
+		canonical, err := fetch(ctx, number)
+		if err != nil {
+			return 0, fmt.Errorf("getting block %d hash: %w", number, err)
+		}
+		for _, hash := range byBlock[number] {
+			if hash != canonical {
+				stale++
+			}
+		}
+	}
+	return stale, nil
+}
@@ -0,0 +1,94 @@
+package engine
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/0xredeth/Rafale/internal/api/graphql/model"
+	"github.com/0xredeth/Rafale/pkg/handler"
+)
+
+// pendingBroadcasts holds the events of a batch until its transaction
+// commits (sync.broadcast_after_commit), so subscribers never see an event
+// the store doesn't have yet, nor one that was rolled back.
+type pendingBroadcasts struct {
+	mu     sync.Mutex
+	events []*model.GenericEvent
+}
+
+// pendingBroadcastsKey is the context key of a batch's pendingBroadcasts.
+type pendingBroadcastsKey struct{}
+
+// deferBroadcasts returns a context under which event broadcasts are held
+// until publishCommitted, or ctx unchanged (and nil) when broadcasts go out
+// immediately.
+//
+// Parameters:
+//   - ctx (context.Context): batch context
+//
+// Returns:
+//   - context.Context: context to process the batch with
+//   - *pendingBroadcasts: held broadcasts, nil if not deferred
+func (e *Engine) deferBroadcasts(ctx context.Context) (context.Context, *pendingBroadcasts) {
+	e.mu.RLock()
+	afterCommit := e.cfg.Sync.BroadcastAfterCommit
+	e.mu.RUnlock()
+
+	if e.broadcaster == nil || !afterCommit {
+		return ctx, nil
+	}
+	pending := &pendingBroadcasts{}
+	return context.WithValue(ctx, pendingBroadcastsKey{}, pending), pending
+}
+
+// broadcastEvent publishes an event, or holds it if the batch defers its
+// broadcasts.
+func (e *Engine) broadcastEvent(ctx context.Context, event *model.GenericEvent) {
+	if pending, ok := ctx.Value(pendingBroadcastsKey{}).(*pendingBroadcasts); ok {
+		pending.add(event)
+		return
+	}
+	e.broadcaster.BroadcastEvent(event)
+}
+
+// bindRuleBroadcasts makes broadcasting rules run for a handler context
+// hold their events with the batch's (see broadcastRule). Handlers only
+// receive a handler.Context, so the binding is kept on the engine until
+// runHandler returns.
+func (e *Engine) bindRuleBroadcasts(ctx context.Context, handlerCtx *handler.Context) {
+	if pending, ok := ctx.Value(pendingBroadcastsKey{}).(*pendingBroadcasts); ok {
+		e.ruleBroadcasts.Store(handlerCtx, pending)
+	}
+}
+
+// add holds an event. Safe for concurrent use by handler workers.
+func (p *pendingBroadcasts) add(event *model.GenericEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.events = append(p.events, event)
+}
+
+// publishCommitted publishes the held events of a committed batch, tagged
+// with the sync cursor committed with them. No-op for a nil pending.
+//
+// Parameters:
+//   - pending (*pendingBroadcasts): held broadcasts
+//   - committedBlock (uint64): last block of the committed batch
+func (e *Engine) publishCommitted(pending *pendingBroadcasts, committedBlock uint64) {
+	if pending == nil {
+		return
+	}
+
+	cursor := strconv.FormatUint(committedBlock, 10)
+	pending.mu.Lock()
+	events := pending.events
+	pending.events = nil
+	pending.mu.Unlock()
+
+	for _, event := range events {
+		event.CommittedBlock = &cursor
+		e.broadcaster.BroadcastEvent(event)
+	}
+}
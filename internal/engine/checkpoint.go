@@ -0,0 +1,267 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// checkpointTimeout bounds each checkpoint mirror request, so a slow
+// mirror can't hold up startup or the mirror writer.
+const checkpointTimeout = 2 * time.Second
+
+// errCursorMoved discards a batch fetched from a mirrored start block that
+// the database check moved; the next tick fetches from the database's.
+var errCursorMoved = errors.New("sync cursor moved by the startup check")
+
+// errCursorCheck halts sync when the database check of a mirrored start
+// block fails.
+var errCursorCheck = errors.New("checking mirrored sync cursor")
+
+var checkpointMirrorErrors = promauto.NewCounter(
+	prometheus.CounterOpts{
+		Name: "rafale_checkpoint_mirror_errors_total",
+		Help: "Total number of failed reads or writes of the sync cursor mirror (sync.checkpoint_redis_url)",
+	},
+)
+
+// cursorCheck is the background check of a mirrored start block against
+// the database.
+type cursorCheck struct {
+	done chan struct{} // closed once checked
+	err  error         // set before done is closed
+}
+
+// checkpointKey returns the mirror key of an indexer's sync cursor.
+func checkpointKey(name string) string {
+	return "rafale:" + name + ":last_block"
+}
+
+// loadCheckpoint reads the mirrored sync cursor, so sync can start without
+// waiting for the database.
+//
+// Parameters:
+//   - ctx (context.Context): context for cancellation
+//
+// Returns:
+//   - uint64: mirrored last indexed block
+//   - bool: false if no mirror is configured, it holds no cursor or can't be read
+func (e *Engine) loadCheckpoint(ctx context.Context) (uint64, bool) {
+	if e.checkpoints == nil {
+		return 0, false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, checkpointTimeout)
+	defer cancel()
+
+	block, ok, err := e.checkpoints.Load(ctx, checkpointKey(e.cfg.Name))
+	if err != nil {
+		checkpointMirrorErrors.Inc()
+		log.Warn().Err(err).Msg("reading mirrored sync cursor")
+		return 0, false
+	}
+	return block, ok
+}
+
+// startCursorCheck starts checking a mirrored start block against the
+// database in the background (see reconcileCheckpoint). Batches are
+// fetched meanwhile, but none is committed until the check is done.
+//
+// Parameters:
+//   - ctx (context.Context): context for cancellation
+//   - mirrored (uint64): start block read from the mirror
+//
+// Returns:
+//   - func(): waits for the check to finish
+func (e *Engine) startCursorCheck(ctx context.Context, mirrored uint64) func() {
+	check := &cursorCheck{done: make(chan struct{})}
+	e.cursorCheck = check
+
+	go func() {
+		defer close(check.done)
+		check.err = e.reconcileCheckpoint(ctx, mirrored)
+	}()
+
+	return func() { <-check.done }
+}
+
+// reconcileCheckpoint resolves the start block from the database and
+// checks the mirrored one against it (see adoptStartBlock).
+//
+// Parameters:
+//   - ctx (context.Context): context for cancellation
+//   - mirrored (uint64): start block read from the mirror
+//
+// Returns:
+//   - error: nil once checked, error if the start block can't be determined
+func (e *Engine) reconcileCheckpoint(ctx context.Context, mirrored uint64) error {
+	dbBlock, err := e.determineStartBlock(ctx)
+	if err != nil {
+		return fmt.Errorf("determining start block: %w", err)
+	}
+	return e.adoptStartBlock(ctx, mirrored, dbBlock)
+}
+
+// adoptStartBlock checks a mirrored start block against the database's.
+// The database is authoritative: on a mismatch (e.g. a mirror left ahead
+// by a rollback) sync is moved to the database's block and the mirror is
+// overwritten.
+//
+// Parameters:
+//   - ctx (context.Context): context for cancellation
+//   - mirrored (uint64): start block read from the mirror
+//   - dbBlock (uint64): start block resolved from the database
+//
+// Returns:
+//   - error: nil on success, schedule initialization error on failure
+func (e *Engine) adoptStartBlock(ctx context.Context, mirrored, dbBlock uint64) error {
+	if dbBlock == mirrored {
+		log.Info().Uint64("block", dbBlock).Msg("mirrored sync cursor matches the database")
+		return nil
+	}
+
+	log.Warn().
+		Uint64("mirrored", mirrored).
+		Uint64("database", dbBlock).
+		Msg("mirrored sync cursor differs from the database, using the database")
+
+	if err := e.initSchedules(ctx, dbBlock); err != nil {
+		return fmt.Errorf("initializing contract schedules: %w", err)
+	}
+	e.mu.Lock()
+	e.lastBlock = dbBlock
+	e.mu.Unlock()
+	e.mirrorCheckpoint(dbBlock)
+	return nil
+}
+
+// cursorChecked reports whether no startup check of a mirrored start block
+// is pending. Until it is done, the check owns reorg handling.
+func (e *Engine) cursorChecked() bool {
+	if e.cursorCheck == nil {
+		return true
+	}
+	select {
+	case <-e.cursorCheck.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// awaitCursorCheck waits for the startup check of a mirrored start block
+// before a batch following lastBlock is committed.
+//
+// Parameters:
+//   - ctx (context.Context): context for cancellation
+//   - lastBlock (uint64): block the batch was fetched after
+//
+// Returns:
+//   - error: nil to commit, errCursorMoved if the check moved the cursor, errCursorCheck if it failed
+func (e *Engine) awaitCursorCheck(ctx context.Context, lastBlock uint64) error {
+	check := e.cursorCheck
+	if check == nil {
+		return nil
+	}
+
+	select {
+	case <-check.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if check.err != nil {
+		return fmt.Errorf("%w: %w", errCursorCheck, check.err)
+	}
+
+	e.mu.RLock()
+	moved := e.lastBlock != lastBlock
+	e.mu.RUnlock()
+	if moved {
+		return errCursorMoved
+	}
+	return nil
+}
+
+// startCheckpointMirror starts the writer mirroring the sync cursor, if a
+// mirror is configured. Writes happen off the sync path and coalesce: only
+// the latest cursor is written, so a slow or unreachable mirror never
+// holds up a batch.
+//
+// Returns:
+//   - func(): stops the writer once the pending cursor is written
+func (e *Engine) startCheckpointMirror() func() {
+	if e.checkpoints == nil {
+		return func() {}
+	}
+
+	key := checkpointKey(e.cfg.Name)
+	writes := make(chan uint64, 1)
+	e.checkpointWrites = writes
+	quit := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case block := <-writes:
+				e.saveCheckpoint(key, block)
+			case <-quit:
+				select {
+				case block := <-writes:
+					e.saveCheckpoint(key, block)
+				default:
+				}
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(quit)
+		<-done
+	}
+}
+
+// mirrorCheckpoint queues the sync cursor for the mirror writer, replacing
+// a cursor not written yet. It never blocks.
+//
+// Parameters:
+//   - block (uint64): last committed block
+func (e *Engine) mirrorCheckpoint(block uint64) {
+	if e.checkpointWrites == nil {
+		return
+	}
+
+	for {
+		select {
+		case e.checkpointWrites <- block:
+			return
+		default:
+		}
+		select {
+		case <-e.checkpointWrites:
+		default:
+		}
+	}
+}
+
+// saveCheckpoint writes the sync cursor to the mirror. Failures are logged
+// and counted: the mirror is best-effort.
+//
+// Parameters:
+//   - key (string): mirror key of the cursor
+//   - block (uint64): last committed block
+func (e *Engine) saveCheckpoint(key string, block uint64) {
+	ctx, cancel := context.WithTimeout(context.Background(), checkpointTimeout)
+	defer cancel()
+
+	if err := e.checkpoints.Save(ctx, key, block); err != nil {
+		checkpointMirrorErrors.Inc()
+		log.Warn().Err(err).Uint64("block", block).Msg("mirroring sync cursor")
+	}
+}
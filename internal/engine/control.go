@@ -0,0 +1,417 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+
+	"github.com/0xredeth/Rafale/internal/store"
+	"github.com/0xredeth/Rafale/pkg/config"
+)
+
+// Runtime control errors.
+var (
+	// ErrContractExists is returned when adding a contract name that is already registered.
+	ErrContractExists = errors.New("contract already registered")
+
+	// ErrContractNotFound is returned when removing a contract that is not registered.
+	ErrContractNotFound = errors.New("contract not found")
+
+	// ErrInvalidRange is returned when a reindex range is empty or beyond the indexed head.
+	ErrInvalidRange = errors.New("invalid block range")
+
+	// ErrReindexInProgress is returned when a reindex is requested while another is running.
+	ErrReindexInProgress = errors.New("reindex already in progress")
+)
+
+// Status is a point-in-time snapshot of the engine state.
+type Status struct {
+	Network    string   `json:"network"`
+	ChainID    uint64   `json:"chainId"`
+	LastBlock  uint64   `json:"lastBlock"`
+	Paused     bool     `json:"paused"`
+	Reindexing bool     `json:"reindexing"`
+	Contracts  []string `json:"contracts"`
+}
+
+// Pause stops the sync loop from fetching new blocks until Resume is called.
+// A batch already in flight completes normally.
+func (e *Engine) Pause() {
+	if !e.paused.Swap(true) {
+		log.Info().Msg("sync engine paused")
+	}
+}
+
+// Resume restarts a paused sync loop.
+func (e *Engine) Resume() {
+	if e.paused.Swap(false) {
+		log.Info().Msg("sync engine resumed")
+	}
+}
+
+// IsPaused reports whether the sync loop is paused.
+//
+// Returns:
+//   - bool: true if paused
+func (e *Engine) IsPaused() bool {
+	return e.paused.Load()
+}
+
+// Status returns a snapshot of the engine state.
+//
+// Returns:
+//   - Status: current engine status
+func (e *Engine) Status() Status {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	contracts := make([]string, 0, len(e.cfg.Contracts))
+	for name := range e.cfg.Contracts {
+		contracts = append(contracts, name)
+	}
+	sort.Strings(contracts)
+
+	return Status{
+		Network:    e.cfg.Network,
+		ChainID:    e.cfg.ChainID,
+		LastBlock:  e.lastBlock,
+		Paused:     e.paused.Load(),
+		Reindexing: e.reindexing.Load(),
+		Contracts:  contracts,
+	}
+}
+
+// AddContract registers a new contract at runtime. Indexing starts from the
+// next synced block; use Reindex to backfill history.
+//
+// Parameters:
+//   - name (string): contract name
+//   - address (string): contract address
+//   - abiPath (string): path to the ABI JSON file
+//   - events ([]string): event names to index
+//
+// Returns:
+//   - error: nil on success, registration error on failure
+func (e *Engine) AddContract(name, address, abiPath string, events []string) error {
+	abiJSON, err := readContractABI(name, address, abiPath, events)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, ok := e.cfg.Contracts[name]; ok {
+		return fmt.Errorf("%s: %w", name, ErrContractExists)
+	}
+
+	return e.registerRuntimeContract(name, address, abiPath, abiJSON, events, e.lastBlock+1)
+}
+
+// AddContractFrom registers a contract discovered while indexing, such as
+// a pair deployed by a factory, and backfills it from fromBlock. Handlers
+// reach it through handler.Context.AddContract.
+//
+// The contract gets a catch-up schedule with its cursor just before
+// fromBlock: the next batch fetches its logs from fromBlock up to the
+// batch end, covering the blocks of the current batch that were fetched
+// before it was known, after which it rejoins the per-tick fetch. Adding
+// the same name and address again is a no-op, so a batch retried after a
+// rollback can re-run the handler.
+//
+// Contracts added at runtime are not persisted: they are dropped by a
+// config reload and on restart, and are only found again if the blocks
+// that announced them are re-processed.
+//
+// Parameters:
+//   - name (string): contract name
+//   - address (string): contract address
+//   - abiPath (string): path to the ABI JSON file
+//   - events ([]string): event names to index
+//   - fromBlock (uint64): first block to index, usually its creation block
+//
+// Returns:
+//   - error: nil on success, ErrContractExists if the name is taken by another address, registration error on failure
+func (e *Engine) AddContractFrom(name, address, abiPath string, events []string, fromBlock uint64) error {
+	abiJSON, err := readContractABI(name, address, abiPath, events)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if existing, ok := e.cfg.Contracts[name]; ok {
+		if common.HexToAddress(existing.Address) == common.HexToAddress(address) {
+			return nil
+		}
+		return fmt.Errorf("%s: %w", name, ErrContractExists)
+	}
+
+	if err := e.registerRuntimeContract(name, address, abiPath, abiJSON, events, fromBlock); err != nil {
+		return err
+	}
+
+	if e.schedules == nil {
+		e.schedules = make(map[string]*contractSchedule)
+	}
+	cursor := uint64(0)
+	if fromBlock > 0 {
+		cursor = fromBlock - 1
+	}
+	e.schedules[name] = &contractSchedule{
+		addr:   common.HexToAddress(address),
+		cursor: cursor,
+	}
+
+	return nil
+}
+
+// readContractABI checks the arguments of a runtime contract registration
+// and reads its ABI.
+//
+// Parameters:
+//   - name (string): contract name
+//   - address (string): contract address
+//   - abiPath (string): path to the ABI JSON file
+//   - events ([]string): event names to index
+//
+// Returns:
+//   - []byte: ABI JSON
+//   - error: nil on success, validation or read error on failure
+func readContractABI(name, address, abiPath string, events []string) ([]byte, error) {
+	if name == "" || address == "" || abiPath == "" || len(events) == 0 {
+		return nil, fmt.Errorf("name, address, abi and at least one event are required")
+	}
+	if !common.IsHexAddress(address) {
+		return nil, fmt.Errorf("invalid address %q", address)
+	}
+
+	abiJSON, err := os.ReadFile(abiPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading ABI for %s: %w", name, err)
+	}
+	return abiJSON, nil
+}
+
+// registerRuntimeContract adds a contract to the decoder and the config.
+// While sync is bounded by end blocks, the contract stops at the same end
+// block, so it doesn't turn a fixed window into an unbounded run.
+// Must be called with e.mu held.
+//
+// Parameters:
+//   - name (string): contract name
+//   - address (string): contract address
+//   - abiPath (string): path to the ABI JSON file
+//   - abiJSON ([]byte): ABI JSON
+//   - events ([]string): event names to index
+//   - startBlock (uint64): first block indexed for the contract
+//
+// Returns:
+//   - error: nil on success, registration error on failure
+func (e *Engine) registerRuntimeContract(name, address, abiPath string, abiJSON []byte, events []string, startBlock uint64) error {
+	addr := common.HexToAddress(address)
+	if err := registerContract(e.decoder, name, addr, []string{string(abiJSON)}, events, nil); err != nil {
+		return fmt.Errorf("registering contract %s: %w", name, err)
+	}
+
+	endBlock, _ := syncEndBlock(e.cfg)
+
+	if e.cfg.Contracts == nil {
+		e.cfg.Contracts = make(map[string]config.ContractConfig)
+	}
+	e.cfg.Contracts[name] = config.ContractConfig{
+		ABI:        abiPath,
+		Address:    addr.Hex(),
+		StartBlock: startBlock,
+		EndBlock:   endBlock,
+		Events:     events,
+	}
+
+	log.Info().
+		Str("contract", name).
+		Str("address", addr.Hex()).
+		Int("events", len(events)).
+		Uint64("startBlock", startBlock).
+		Msg("added contract at runtime")
+
+	return nil
+}
+
+// RemoveContract unregisters a contract at runtime. Already indexed data is kept.
+//
+// Parameters:
+//   - name (string): contract name
+//
+// Returns:
+//   - error: nil on success, ErrContractNotFound if not registered
+func (e *Engine) RemoveContract(name string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, ok := e.cfg.Contracts[name]; !ok {
+		return fmt.Errorf("%s: %w", name, ErrContractNotFound)
+	}
+
+	e.decoder.RemoveContract(name)
+	delete(e.cfg.Contracts, name)
+	delete(e.schedules, name)
+
+	log.Info().Str("contract", name).Msg("removed contract at runtime")
+
+	return nil
+}
+
+// Reindex deletes and re-processes an already indexed block range using the
+// currently registered contracts. Each batch is deleted and re-inserted in a
+// single transaction, so a failed or cancelled reindex never leaves a gap.
+// The range must not extend past the last indexed block; only one reindex
+// may run at a time. Progress is tracked as a job (see Jobs, CancelJob).
+//
+// Parameters:
+//   - ctx (context.Context): context for cancellation
+//   - fromBlock (uint64): first block to reindex (inclusive)
+//   - toBlock (uint64): last block to reindex (inclusive)
+//
+// Returns:
+//   - error: nil on success, ErrInvalidRange, ErrReindexInProgress, or reindex error on failure
+func (e *Engine) Reindex(ctx context.Context, fromBlock, toBlock uint64) error {
+	ctx, jobID, err := e.startReindex(ctx, fromBlock, toBlock)
+	if err != nil {
+		return err
+	}
+	return e.runReindex(ctx, jobID, fromBlock, toBlock)
+}
+
+// StartReindex starts Reindex in the background once the range is valid
+// and no other reindex runs, and returns its job ID; failures are recorded
+// on the job (see Jobs).
+//
+// Parameters:
+//   - ctx (context.Context): context bounding the reindex
+//   - fromBlock (uint64): first block to reindex (inclusive)
+//   - toBlock (uint64): last block to reindex (inclusive)
+//
+// Returns:
+//   - string: the job ID
+//   - error: nil once started, ErrInvalidRange or ErrReindexInProgress
+func (e *Engine) StartReindex(ctx context.Context, fromBlock, toBlock uint64) (string, error) {
+	jobCtx, jobID, err := e.startReindex(ctx, fromBlock, toBlock)
+	if err != nil {
+		return "", err
+	}
+
+	go func() {
+		if err := e.runReindex(jobCtx, jobID, fromBlock, toBlock); err != nil {
+			log.Error().Err(err).Str("job", jobID).Msg("reindex failed")
+		}
+	}()
+	return jobID, nil
+}
+
+// startReindex validates a reindex range and claims the reindex job.
+func (e *Engine) startReindex(ctx context.Context, fromBlock, toBlock uint64) (context.Context, string, error) {
+	e.mu.RLock()
+	lastBlock := e.lastBlock
+	e.mu.RUnlock()
+
+	if fromBlock > toBlock || toBlock > lastBlock {
+		return nil, "", fmt.Errorf("%w: %d-%d (last indexed block %d)", ErrInvalidRange, fromBlock, toBlock, lastBlock)
+	}
+	return e.startExclusiveJob(ctx, JobTypeReindex, fromBlock, toBlock)
+}
+
+// runReindex runs a reindex job claimed by startReindex, then finishes the
+// job and releases the reindexing slot. Block coverage and reverted
+// transactions are rewritten too, since the range delete clears them with
+// the rows.
+func (e *Engine) runReindex(ctx context.Context, jobID string, fromBlock, toBlock uint64) (err error) {
+	defer e.reindexing.Store(false)
+	defer func() { e.finishJob(jobID, err) }()
+
+	e.mu.RLock()
+	batchSize := e.cfg.Sync.BatchSize
+	coverage := e.cfg.Sync.BlockCoverage
+	trackReverts := e.cfg.Sync.TrackReverts
+	e.mu.RUnlock()
+
+	if batchSize == 0 {
+		batchSize = 1
+	}
+
+	log.Info().
+		Str("job", jobID).
+		Uint64("from", fromBlock).
+		Uint64("to", toBlock).
+		Msg("reindex started")
+
+	for start := fromBlock; start <= toBlock; start += batchSize {
+		end := start + batchSize - 1
+		if end > toBlock || end < start {
+			end = toBlock
+		}
+
+		logs, err := e.fetchBlockRangeLogs(ctx, start, end)
+		if err != nil {
+			return fmt.Errorf("reindexing blocks %d-%d: %w", start, end, err)
+		}
+		logs = e.dropEndedLogs(e.dropDeferredLogs(logs))
+
+		var reverts []store.RevertedTx
+		if trackReverts {
+			if reverts, err = e.scanReverts(ctx, start, end); err != nil {
+				return fmt.Errorf("reindexing blocks %d-%d: scanning reverted transactions: %w", start, end, err)
+			}
+		}
+
+		var deleted int64
+		batchCtx, pending := e.deferBroadcasts(ctx)
+		err = e.store.Transaction(ctx, func(tx *gorm.DB) error {
+			var err error
+			if deleted, err = store.DeleteBlockRangeTx(tx, start, end); err != nil {
+				return err
+			}
+			if err := e.processLogs(batchCtx, tx, logs); err != nil {
+				return err
+			}
+			if err := store.CreateRevertedTxsTx(tx, reverts); err != nil {
+				return err
+			}
+			if coverage {
+				return store.UpsertBlockCoverageTx(tx, start, end, logCounts(logs))
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("reindexing blocks %d-%d: %w", start, end, err)
+		}
+		store.PurgeCachedBlocks(start, end)
+		e.publishCommitted(pending, end)
+
+		e.updateJobProgress(jobID, end)
+
+		log.Debug().
+			Uint64("from", start).
+			Uint64("to", end).
+			Int64("deleted", deleted).
+			Int("logs", len(logs)).
+			Msg("reindexed blocks")
+
+		if end == toBlock {
+			break
+		}
+	}
+
+	log.Info().
+		Str("job", jobID).
+		Uint64("from", fromBlock).
+		Uint64("to", toBlock).
+		Msg("reindex complete")
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package engine
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/0xredeth/Rafale/pkg/config"
+)
+
+// Marker fields added to event data replaced by an overflow policy.
+const (
+	dataFieldTruncated    = "_truncated"
+	dataFieldOriginalSize = "_originalSize"
+	dataFieldSHA256       = "_sha256"
+)
+
+// limitEventData applies the overflow policy to serialized event data
+// larger than maxBytes.
+//
+// Parameters:
+//   - data ([]byte): serialized event data (JSON object)
+//   - maxBytes (int): size limit (0 = unlimited)
+//   - policy (string): config.DataPolicy* value (empty = truncate)
+//
+// Returns:
+//   - []byte: data to store (unchanged if within the limit)
+//   - bool: true if the event should be skipped
+//   - error: nil on success, error on failure
+func limitEventData(data []byte, maxBytes int, policy string) ([]byte, bool, error) {
+	if maxBytes <= 0 || len(data) <= maxBytes {
+		return data, false, nil
+	}
+
+	switch policy {
+	case config.DataPolicySkip:
+		return nil, true, nil
+	case config.DataPolicyHash:
+		out, err := hashEventData(data, maxBytes)
+		if err != nil {
+			return nil, false, err
+		}
+		return out, false, nil
+	default:
+		out, err := truncateEventData(data, maxBytes)
+		if err != nil {
+			return nil, false, err
+		}
+		return out, false, nil
+	}
+}
+
+// hashEventData replaces event data with its SHA-256 and original size.
+// Like truncateEventData, it drops the original size when maxBytes can't
+// hold both markers, and returns an empty object when it can't hold the
+// hash either.
+func hashEventData(data []byte, maxBytes int) ([]byte, error) {
+	sum := sha256.Sum256(data)
+	marker := map[string]any{
+		dataFieldSHA256:       hex.EncodeToString(sum[:]),
+		dataFieldOriginalSize: len(data),
+	}
+	out, err := json.Marshal(marker)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling data hash: %w", err)
+	}
+	if len(out) <= maxBytes {
+		return out, nil
+	}
+
+	delete(marker, dataFieldOriginalSize)
+	if out, err = json.Marshal(marker); err != nil {
+		return nil, fmt.Errorf("marshaling data hash: %w", err)
+	}
+	if len(out) <= maxBytes {
+		return out, nil
+	}
+	return []byte("{}"), nil
+}
+
+// truncateEventData keeps the top-level fields that fit within maxBytes, in
+// key order, and flags the result as truncated. The output stays valid JSON
+// so it can be stored in the JSONB column. When maxBytes can't hold both
+// markers, the original size is dropped; when it can't hold the flag
+// either, the result is an empty object.
+func truncateEventData(data []byte, maxBytes int) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("parsing event data: %w", err)
+	}
+
+	kept := map[string]any{
+		dataFieldTruncated:    true,
+		dataFieldOriginalSize: len(data),
+	}
+	base, err := json.Marshal(kept)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling truncated data: %w", err)
+	}
+	if len(base) > maxBytes {
+		delete(kept, dataFieldOriginalSize)
+		if base, err = json.Marshal(kept); err != nil {
+			return nil, fmt.Errorf("marshaling truncated data: %w", err)
+		}
+		if len(base) > maxBytes {
+			return []byte("{}"), nil
+		}
+	}
+	size := len(base)
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		// "key":value plus a separating comma
+		fieldSize := len(k) + 4 + len(fields[k])
+		if size+fieldSize > maxBytes {
+			continue
+		}
+		kept[k] = fields[k]
+		size += fieldSize
+	}
+
+	out, err := json.Marshal(kept)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling truncated data: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,87 @@
+package engine
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	databaseSize = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "rafale_database_size_bytes",
+			Help: "Disk space used by the database, when sync.max_database_bytes is set",
+		},
+	)
+
+	diskGuardPaused = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "rafale_disk_guard_paused",
+			Help: "1 while syncing is paused because the database reached sync.max_database_bytes",
+		},
+	)
+)
+
+// guardDatabaseSize checks the database size every interval until ctx is
+// cancelled, pausing the sync loop while it is at or above limit. Inserts
+// into a full disk fail and can take the database down; a pause lets an
+// operator reclaim space and syncing continue where it stopped.
+//
+// Parameters:
+//   - ctx (context.Context): context for cancellation
+//   - limit (int64): size in bytes at which syncing pauses
+//   - interval (time.Duration): time between checks
+func (e *Engine) guardDatabaseSize(ctx context.Context, limit int64, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		size, err := e.store.DatabaseSize(ctx)
+		if err != nil {
+			log.Warn().Err(err).Msg("checking database size")
+		} else {
+			e.applyDiskGuard(size, limit)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// applyDiskGuard pauses or resumes syncing for a database size. Only a
+// pause set by the guard is lifted: a manual pause stays until Resume, and
+// a manual Resume while over the limit is undone at the next check.
+//
+// Parameters:
+//   - size (int64): current database size in bytes
+//   - limit (int64): size in bytes at which syncing pauses
+func (e *Engine) applyDiskGuard(size, limit int64) {
+	databaseSize.Set(float64(size))
+
+	if size >= limit {
+		diskGuardPaused.Set(1)
+		if !e.paused.Load() {
+			e.Pause()
+			e.diskPaused.Store(true)
+			log.Error().
+				Int64("size", size).
+				Int64("maxDatabaseBytes", limit).
+				Msg("ALERT: database reached max_database_bytes, pausing sync until space is reclaimed")
+		}
+		return
+	}
+
+	diskGuardPaused.Set(0)
+	if e.diskPaused.Swap(false) {
+		log.Info().
+			Int64("size", size).
+			Int64("maxDatabaseBytes", limit).
+			Msg("database below max_database_bytes, resuming sync")
+		e.Resume()
+	}
+}
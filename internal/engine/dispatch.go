@@ -0,0 +1,219 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"gorm.io/gorm"
+
+	"github.com/0xredeth/Rafale/pkg/config"
+	"github.com/0xredeth/Rafale/pkg/decoder"
+)
+
+// PartitionKeyFunc returns the key that orders handler execution when
+// sync.handler_workers > 1: events with the same key run on the same
+// worker in log order; different keys run in parallel.
+type PartitionKeyFunc func(event *decoder.DecodedEvent) string
+
+// SetHandlerPartitionKey overrides the partition key configured by
+// sync.handler_partition (nil restores it).
+//
+// Parameters:
+//   - fn (PartitionKeyFunc): key function
+func (e *Engine) SetHandlerPartitionKey(fn PartitionKeyFunc) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.partitionKey = fn
+}
+
+// contractPartitionKey partitions by emitting contract.
+func contractPartitionKey(event *decoder.DecodedEvent) string {
+	return strings.ToLower(event.Log.Address.Hex())
+}
+
+// addressPartitionKey partitions by the first indexed argument (e.g.
+// Transfer.from), falling back to the contract for events without one.
+// Later indexed arguments (Transfer.to) don't affect the key, so events
+// sharing only those may run out of order.
+func addressPartitionKey(event *decoder.DecodedEvent) string {
+	if len(event.Log.Topics) < 2 {
+		return contractPartitionKey(event)
+	}
+	return event.Log.Topics[1].Hex()
+}
+
+// partitionKeyFor returns the built-in key function for a
+// sync.handler_partition mode.
+func partitionKeyFor(mode string) PartitionKeyFunc {
+	if mode == config.HandlerPartitionAddress {
+		return addressPartitionKey
+	}
+	return contractPartitionKey
+}
+
+var handlerQueueDepth = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "rafale_handler_queue_depth",
+		Help: "Number of events waiting for a handler worker, by event",
+	},
+	[]string{"event"},
+)
+
+// handlerPool runs typed handler tasks on workers. Tasks with the same key
+// run on the same worker in submission order; class (the event ID) is used
+// for queue depth metrics and fair scheduling.
+type handlerPool interface {
+	submit(key, class string, task func(worker int) error)
+	wait() error
+}
+
+// newHandlerPool starts the pool for a sync.handler_scheduling mode.
+//
+// Parameters:
+//   - n (int): number of workers
+//   - mode (string): scheduling mode
+//   - maxShare (float64): fair mode's per-event share of the workers (0 = no limit)
+//
+// Returns:
+//   - handlerPool: the started pool
+func newHandlerPool(n int, mode string, maxShare float64) handlerPool {
+	if mode == config.HandlerSchedulingFair {
+		return newFairPool(n, maxShare)
+	}
+	return newKeyedPool(n)
+}
+
+// keyedPool runs tasks on a fixed set of workers, routing every task with
+// the same key to the same worker so they execute in submission order.
+// After the first failure remaining tasks are skipped.
+type keyedPool struct {
+	queues []chan keyedTask
+	wg     sync.WaitGroup
+	failed atomic.Bool
+	errMu  sync.Mutex
+	err    error
+}
+
+// keyedTask is a queued task with its class.
+type keyedTask struct {
+	class string
+	run   func(worker int) error
+}
+
+// newKeyedPool starts a pool with n workers.
+func newKeyedPool(n int) *keyedPool {
+	p := &keyedPool{queues: make([]chan keyedTask, n)}
+	for i := range p.queues {
+		p.queues[i] = make(chan keyedTask, 64)
+		p.wg.Add(1)
+		go p.work(i)
+	}
+	return p
+}
+
+// work drains one worker queue.
+func (p *keyedPool) work(worker int) {
+	defer p.wg.Done()
+	for task := range p.queues[worker] {
+		handlerQueueDepth.WithLabelValues(task.class).Dec()
+		if p.failed.Load() {
+			continue
+		}
+		if err := task.run(worker); err != nil {
+			p.errMu.Lock()
+			if p.err == nil {
+				p.err = err
+			}
+			p.errMu.Unlock()
+			p.failed.Store(true)
+		}
+	}
+}
+
+// submit queues a task on the worker owning key.
+func (p *keyedPool) submit(key, class string, task func(worker int) error) {
+	handlerQueueDepth.WithLabelValues(class).Inc()
+	p.queues[workerFor(key, len(p.queues))] <- keyedTask{class: class, run: task}
+}
+
+// workerFor returns the worker owning a partition key.
+func workerFor(key string, workers int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(workers)) //nolint:gosec // G115: worker count is small
+}
+
+// wait stops accepting tasks, waits for queued ones and returns the first error.
+func (p *keyedPool) wait() error {
+	for _, q := range p.queues {
+		close(q)
+	}
+	p.wg.Wait()
+	return p.err
+}
+
+// processLogsParallel stores events in the batch transaction and runs typed
+// handlers on a keyed worker pool. Handlers write through the batch
+// transaction too, so their writes commit or roll back with the batch. A
+// transaction runs one statement at a time: statements from the workers
+// and from event storage are serialized (see txLock), and handlers run in
+// parallel between them. Handlers used with workers must not open nested
+// transactions (savepoints) or keep rows open across statements.
+//
+// Parameters:
+//   - ctx (context.Context): batch context
+//   - tx (*gorm.DB): batch transaction for events, handlers and the sync cursor
+//   - logs ([]types.Log): logs in block/log order
+//   - workers (int): number of handler workers
+//
+// Returns:
+//   - error: nil on success, the first processing or handler error otherwise
+func (e *Engine) processLogsParallel(ctx context.Context, tx *gorm.DB, logs []types.Log, workers int) error {
+	e.mu.RLock()
+	keyFn := e.partitionKey
+	mode := e.cfg.Sync.HandlerPartition
+	scheduling := e.cfg.Sync.HandlerScheduling
+	maxShare := e.cfg.Sync.HandlerMaxShare
+	e.mu.RUnlock()
+	if keyFn == nil {
+		keyFn = partitionKeyFor(mode)
+	}
+
+	var txMu sync.Mutex
+	storeTx, storeLock := lockedSession(tx, &txMu)
+	defer storeLock.releaseAll()
+
+	pool := newHandlerPool(workers, scheduling, maxShare)
+	for _, logEntry := range logs {
+		handlerCtx, err := e.prepareLog(ctx, storeTx, logEntry)
+		if err != nil {
+			_ = pool.wait()
+			return fmt.Errorf("processing log at block %d: %w", logEntry.BlockNumber, err)
+		}
+		if handlerCtx == nil {
+			continue
+		}
+
+		block := logEntry.BlockNumber
+		pool.submit(keyFn(handlerCtx.Event), handlerCtx.Event.EventID, func(int) error {
+			handlerTx, lock := lockedSession(tx, &txMu)
+			defer lock.releaseAll()
+
+			handlerCtx.DB = handlerTx
+			if err := e.runHandler(handlerCtx); err != nil {
+				return fmt.Errorf("processing log at block %d: %w", block, err)
+			}
+			return nil
+		})
+	}
+
+	return pool.wait()
+}
@@ -0,0 +1,124 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"gorm.io/gorm"
+
+	"github.com/0xredeth/Rafale/pkg/config"
+)
+
+// errEndBlockReached stops the sync loop once every contract has been
+// indexed up to its end block.
+var errEndBlockReached = errors.New("end block reached")
+
+// syncEndBlock returns the block sync stops at: the highest contract
+// end_block, when every contract sets one. A contract without an end
+// block follows the head, and so does sync. Contracts added at runtime
+// count too: they inherit the end block of a bounded run.
+//
+// Parameters:
+//   - cfg (*config.Config): indexer configuration
+//
+// Returns:
+//   - uint64: last block to index
+//   - bool: true if sync stops at an end block
+func syncEndBlock(cfg *config.Config) (uint64, bool) {
+	if len(cfg.Contracts) == 0 {
+		return 0, false
+	}
+
+	var end uint64
+	for _, contract := range cfg.Contracts {
+		if contract.EndBlock == 0 {
+			return 0, false
+		}
+		end = max(end, contract.EndBlock)
+	}
+	return end, true
+}
+
+// schedulesPending reports whether a scheduled contract, such as one added
+// at runtime, still has a deferred range up to endBlock to fetch.
+//
+// Parameters:
+//   - endBlock (uint64): last block of the fixed window
+//
+// Returns:
+//   - bool: true if a schedule cursor is short of endBlock
+func (e *Engine) schedulesPending(endBlock uint64) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	for _, sched := range e.schedules {
+		if sched.cursor < endBlock {
+			return true
+		}
+	}
+	return false
+}
+
+// catchUpSchedules fetches and stores the deferred ranges of scheduled
+// contracts once the last batch of a fixed window is committed: no batch
+// follows to carry them, and the blocks of the window are already indexed
+// for every other contract.
+//
+// Parameters:
+//   - ctx (context.Context): context for cancellation
+//   - endBlock (uint64): last block of the fixed window
+//
+// Returns:
+//   - error: nil on success, fetch or store error on failure
+func (e *Engine) catchUpSchedules(ctx context.Context, endBlock uint64) error {
+	logs, commit, err := e.fetchSyncLogs(ctx, endBlock+1, endBlock)
+	if err != nil {
+		return fmt.Errorf("catching up scheduled contracts: %w", err)
+	}
+	logs = e.dropEndedLogs(logs)
+
+	if len(logs) > 0 {
+		commitCtx, release := e.commitContext(ctx)
+		defer release()
+
+		batchCtx, pending := e.deferBroadcasts(commitCtx)
+		if err := e.store.Transaction(commitCtx, func(tx *gorm.DB) error {
+			return e.processLogs(batchCtx, tx, logs)
+		}); err != nil {
+			return fmt.Errorf("catching up scheduled contracts: %w", err)
+		}
+		e.publishCommitted(pending, endBlock)
+	}
+
+	commit()
+	return nil
+}
+
+// dropEndedLogs removes logs past their contract's end block. Those are
+// fetched only because another contract is still being indexed.
+func (e *Engine) dropEndedLogs(logs []types.Log) []types.Log {
+	e.mu.RLock()
+	ends := make(map[common.Address]uint64)
+	for _, contract := range e.cfg.Contracts {
+		if contract.EndBlock > 0 {
+			ends[common.HexToAddress(contract.Address)] = contract.EndBlock
+		}
+	}
+	e.mu.RUnlock()
+
+	if len(ends) == 0 {
+		return logs
+	}
+
+	kept := logs[:0]
+	for _, l := range logs {
+		if end, ok := ends[l.Address]; ok && l.BlockNumber > end {
+			continue
+		}
+		kept = append(kept, l)
+	}
+	return kept
+}
@@ -4,21 +4,29 @@ package engine
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
-	"github.com/rs/zerolog/log"
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
 
 	"github.com/0xredeth/Rafale/internal/api/graphql/model"
+	"github.com/0xredeth/Rafale/internal/checkpoint"
 	"github.com/0xredeth/Rafale/internal/pubsub"
 	"github.com/0xredeth/Rafale/internal/rpc"
 	"github.com/0xredeth/Rafale/internal/store"
@@ -43,25 +51,172 @@ var (
 		},
 	)
 
+	secondsBehindTip = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "rafale_seconds_behind_tip",
+			Help: "Seconds between now and the timestamp of the last indexed block",
+		},
+	)
+
 	currentBlock = promauto.NewGauge(
 		prometheus.GaugeOpts{
 			Name: "rafale_current_block",
 			Help: "Current indexed block number",
 		},
 	)
+
+	batchBlocks = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "rafale_batch_blocks",
+			Help:    "Number of blocks per committed batch",
+			Buckets: prometheus.ExponentialBuckets(1, 4, 8), // 1 to 16384
+		},
+	)
+
+	batchLogs = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "rafale_batch_logs",
+			Help:    "Number of logs per committed batch",
+			Buckets: []float64{0, 1, 10, 100, 1000, 10000, 100000},
+		},
+	)
+
+	batchDuration = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "rafale_batch_duration_seconds",
+			Help:    "Time to fetch, process and commit a batch",
+			Buckets: prometheus.DefBuckets,
+		},
+	)
+
+	lastBatchTimestamp = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "rafale_last_successful_batch_timestamp_seconds",
+			Help: "Unix time of the last committed batch",
+		},
+	)
+
+	syncStalls = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "rafale_sync_stalls_total",
+			Help: "Total number of sync loop stalls detected by the watchdog",
+		},
+	)
+
+	reorgsDetected = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "rafale_reorgs_detected_total",
+			Help: "Total number of chain reorganizations detected",
+		},
+	)
+
+	logsTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "rafale_logs_total",
+			Help: "Total number of logs received for processing",
+		},
+	)
+
+	logsDecoded = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "rafale_logs_decoded_total",
+			Help: "Total number of logs decoded and stored",
+		},
+	)
+
+	logsSkipped = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rafale_logs_skipped_total",
+			Help: "Total number of logs skipped, by reason",
+		},
+		[]string{"reason"},
+	)
+
+	oversizedEvents = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rafale_oversized_events_total",
+			Help: "Total number of events whose data exceeded sync.max_data_bytes",
+		},
+		[]string{"policy"},
+	)
 )
 
+// syncStore is the part of *store.Store the engine uses, so tests can run
+// the engine against an in-memory fake.
+type syncStore interface {
+	Transaction(ctx context.Context, fn func(*gorm.DB) error) error
+	DB() *gorm.DB
+	Ping(ctx context.Context) error
+	Close() error
+	GetSyncStatus(ctx context.Context, contract string) (*store.SyncStatus, error)
+	UpsertSyncStatus(ctx context.Context, status store.SyncStatus) error
+	ListBlockHashes(ctx context.Context, fromBlock, toBlock uint64) (map[uint64]string, error)
+	GetMaxBlockNumber(ctx context.Context, tableName string) (uint64, error)
+	GetMaxContractBlockNumber(ctx context.Context, contractName string) (uint64, error)
+	StreamAllEvents(ctx context.Context, fromBlock, toBlock uint64, fn func(store.UnifiedEvent) error) error
+	DatabaseSize(ctx context.Context) (int64, error)
+	AnalyzeTables(ctx context.Context) error
+}
+
 // Engine orchestrates the sync loop.
 type Engine struct {
 	cfg         *config.Config
-	rpc         *rpc.Client
-	store       *store.Store
+	rpc         rpc.EthClient
+	store       syncStore
 	decoder     *decoder.Decoder
 	handlers    *handler.Registry
 	broadcaster *pubsub.Broadcaster
 
+	// mu guards cfg, decoder registrations and lastBlock against
+	// concurrent runtime control (admin API, hot-reload).
+	mu sync.RWMutex
+
 	// State
-	lastBlock uint64
+	lastBlock      uint64
+	recentBlocks   []blockRef // processed block hashes within the reorg window
+	paused         atomic.Bool
+	diskPaused     atomic.Bool // paused by the database size guard
+	reindexing     atomic.Bool
+	schedules      map[string]*contractSchedule // contracts with their own poll interval
+	lastProgress   atomic.Int64                 // unix nanos of the last successful tick
+	lastBlockAt    atomic.Int64                 // unix timestamp of lastBlock's header (0 = unknown)
+	transforms     []Transform                  // applied to each event after decoding
+	partitionKey   PartitionKeyFunc             // overrides sync.handler_partition when set
+	dedup          []string                     // events columns of the dedup key (nil = plain inserts)
+	schemas        map[string]eventSchema       // expected event data shapes by event ID
+	syncState      atomic.Value                 // last published sync state (SyncStateSynced or SyncStateBackfilling)
+	ruleBroadcasts sync.Map                     // *handler.Context -> *pendingBroadcasts of its batch while its handler runs
+	ruleBases      map[string]handler.Func      // handler each rule chain wraps, by event ID (nil = none)
+	ruleTables     []string                     // block tables registered for rule tables
+
+	// Automatic ANALYZE (sync.auto_analyze)
+	analyzeLogs    atomic.Int64 // logs processed since the last analyze
+	analyzedAtHead atomic.Bool
+	analyzing      atomic.Bool
+
+	// Sync cursor mirror (sync.checkpoint_redis_url)
+	checkpoints      checkpoint.Store
+	checkpointWrites chan uint64  // latest cursor not mirrored yet
+	cursorCheck      *cursorCheck // startup check of a mirrored start block
+
+	// In-process OnEvent callbacks
+	listenersMu sync.RWMutex
+	listeners   map[uint64]*eventListener
+	listenerSeq uint64
+
+	// Installed log filter (sync.filter_mode)
+	filterMu sync.Mutex
+	filter   *logFilter
+
+	// Long-running jobs (reindex)
+	jobsMu sync.Mutex
+	jobs   map[string]*job
+	jobSeq uint64
+
+	// Graceful shutdown (Stop)
+	running      atomic.Bool
+	shutdownOnce sync.Once
+	shutdownSt   *shutdownState
 }
 
 // New creates a new engine instance.
@@ -77,6 +232,10 @@ func New(cfg *config.Config, broadcaster *pubsub.Broadcaster) (*Engine, error) {
 	// Initialize RPC client
 	rpcCfg := rpc.DefaultConfig()
 	rpcCfg.URL = cfg.RPCURL
+	rpcCfg.WSURL = cfg.WSURL
+	rpcCfg.MaxConcurrentPerEndpoint = cfg.RPC.MaxConcurrentPerEndpoint
+	rpcCfg.MaxResponseBytes = cfg.RPC.MaxResponseBytes
+	rpcCfg.VerifyLogRanges = cfg.Sync.VerifyLogRanges
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -86,15 +245,23 @@ func New(cfg *config.Config, broadcaster *pubsub.Broadcaster) (*Engine, error) {
 		return nil, fmt.Errorf("creating RPC client: %w", err)
 	}
 
-	// Verify chain ID
-	if rpcClient.ChainID().Uint64() != cfg.ChainID {
+	// Verify or detect chain ID
+	chainID, err := resolveChainID(cfg, rpcClient.ChainID().Uint64())
+	if err != nil {
 		rpcClient.Close()
-		return nil, fmt.Errorf("chain ID mismatch: expected %d, got %d", cfg.ChainID, rpcClient.ChainID().Uint64())
+		return nil, err
 	}
+	cfg.ChainID = chainID
 
 	// Initialize store
 	storeCfg := store.DefaultConfig()
 	storeCfg.DSN = cfg.Database
+	if cfg.Sync.PrimaryKey != "" {
+		storeCfg.PrimaryKey = cfg.Sync.PrimaryKey
+	}
+	if cfg.Sync.StoreAddressCase != "" {
+		storeCfg.AddressCase = cfg.Sync.StoreAddressCase
+	}
 
 	db, err := store.New(storeCfg)
 	if err != nil {
@@ -106,6 +273,9 @@ func New(cfg *config.Config, broadcaster *pubsub.Broadcaster) (*Engine, error) {
 	if err := db.Migrate(
 		&store.Event{},
 		&store.Transfer{},
+		&store.SyncStatus{},
+		&store.BlockCoverage{},
+		&store.RevertedTx{},
 	); err != nil {
 		_ = db.Close()
 		rpcClient.Close()
@@ -113,53 +283,158 @@ func New(cfg *config.Config, broadcaster *pubsub.Broadcaster) (*Engine, error) {
 	}
 	log.Info().Msg("database migrations complete")
 
+	// Key event tables by the configured primary key (rebuilding a key may
+	// outlast the startup timeout on large tables)
+	for _, table := range []string{"events", "transfers"} {
+		if err := db.EnsurePrimaryKey(context.Background(), table); err != nil {
+			_ = db.Close()
+			rpcClient.Close()
+			return nil, err
+		}
+	}
+
+	// Transfers are upserted on their natural key so re-processed blocks
+	// don't duplicate them
+	if err := db.EnsureNaturalKeyIndex(context.Background(), "transfers"); err != nil {
+		_ = db.Close()
+		rpcClient.Close()
+		return nil, err
+	}
+
+	// Rows stored in another address case (before sync.store_address_case
+	// existed, or under the other case) wouldn't match address filters
+	if err := db.NormalizeAddressCase(context.Background(), "transfers", store.TransferAddressColumns...); err != nil {
+		_ = db.Close()
+		rpcClient.Close()
+		return nil, err
+	}
+	if err := db.NormalizeAddressCase(context.Background(), "events", store.EventAddressColumns...); err != nil {
+		_ = db.Close()
+		rpcClient.Close()
+		return nil, err
+	}
+
+	// Handler workers share the batch transaction one statement at a time
+	if err := registerTxLockCallbacks(db.DB()); err != nil {
+		_ = db.Close()
+		rpcClient.Close()
+		return nil, err
+	}
+
+	// Coverage and reverted transaction rows roll back with reorgs like indexed data
+	store.RegisterBlockTable(store.BlockCoverage{}.TableName())
+	store.RegisterBlockTable(store.RevertedTx{}.TableName())
+
 	// Setup TimescaleDB optimizations (hypertable + compression + retention)
 	tsCfg := store.DefaultTimescaleConfig()
 
-	// Setup hypertable for generic events table
-	if err := db.SetupTimescaleDB(context.Background(), "events", "timestamp", tsCfg); err != nil {
+	// Setup hypertable for generic events table (store.New already warns
+	// when the extension is missing)
+	if err := db.SetupTimescaleDB(context.Background(), "events", "timestamp", tsCfg); err != nil && !errors.Is(err, store.ErrTimescaleDBUnavailable) {
 		log.Warn().Err(err).Msg("TimescaleDB setup for events table warning (non-fatal)")
 	}
 
 	// Setup hypertable for typed transfers table
-	if err := db.SetupTimescaleDB(context.Background(), "transfers", "timestamp", tsCfg); err != nil {
+	if err := db.SetupTimescaleDB(context.Background(), "transfers", "timestamp", tsCfg); err != nil && !errors.Is(err, store.ErrTimescaleDBUnavailable) {
 		log.Warn().Err(err).Msg("TimescaleDB setup for transfers table warning (non-fatal)")
 	}
 
+	// Register token decimals and route contracts with their own table to it
+	for name, contract := range cfg.Contracts {
+		if contract.Decimals != nil {
+			store.RegisterDecimals(contract.Address, *contract.Decimals)
+		}
+		if contract.Table == "" {
+			continue
+		}
+		if err := db.MigrateTransferTable(ctx, contract.Table); err != nil {
+			_ = db.Close()
+			rpcClient.Close()
+			return nil, fmt.Errorf("contract %s: %w", name, err)
+		}
+		if err := db.EnsurePrimaryKey(context.Background(), contract.Table); err != nil {
+			_ = db.Close()
+			rpcClient.Close()
+			return nil, fmt.Errorf("contract %s: %w", name, err)
+		}
+		if err := db.EnsureNaturalKeyIndex(context.Background(), contract.Table); err != nil {
+			_ = db.Close()
+			rpcClient.Close()
+			return nil, fmt.Errorf("contract %s: %w", name, err)
+		}
+		if err := db.NormalizeAddressCase(context.Background(), contract.Table, store.TransferAddressColumns...); err != nil {
+			_ = db.Close()
+			rpcClient.Close()
+			return nil, fmt.Errorf("contract %s: %w", name, err)
+		}
+		store.RegisterTransferTable(name, contract.Table)
+		if err := db.SetupTimescaleDB(ctx, contract.Table, "timestamp", tsCfg); err != nil && !errors.Is(err, store.ErrTimescaleDBUnavailable) {
+			log.Warn().Err(err).Str("table", contract.Table).Msg("TimescaleDB setup for transfer table warning (non-fatal)")
+		}
+	}
+
+	// Deduplicate events on the configured key
+	dedupColumns := dedupColumnsFor(cfg.Sync.DedupKey)
+	if err := db.EnsureEventDedupIndex(ctx, dedupColumns); err != nil {
+		_ = db.Close()
+		rpcClient.Close()
+		return nil, err
+	}
+
 	// Initialize decoder
 	dec := decoder.New()
 
 	// Register contracts from config
 	for name, contract := range cfg.Contracts {
-		abiJSON, err := os.ReadFile(contract.ABI)
+		events, err := registerConfiguredContract(ctx, dec, cfg, name, contract)
 		if err != nil {
 			_ = db.Close()
 			rpcClient.Close()
-			return nil, fmt.Errorf("reading ABI for %s: %w", name, err)
-		}
-
-		addr := common.HexToAddress(contract.Address)
-		if err := dec.RegisterContract(name, addr, string(abiJSON), contract.Events); err != nil {
-			_ = db.Close()
-			rpcClient.Close()
-			return nil, fmt.Errorf("registering contract %s: %w", name, err)
+			return nil, err
 		}
 
 		log.Info().
 			Str("contract", name).
 			Str("address", contract.Address).
-			Int("events", len(contract.Events)).
+			Strs("events", events).
 			Msg("registered contract")
 	}
 
-	return &Engine{
+	logCollisions(dec)
+
+	schemas, err := buildEventSchemas(cfg.EventSchemas, dec)
+	if err != nil {
+		_ = db.Close()
+		rpcClient.Close()
+		return nil, err
+	}
+
+	e := &Engine{
 		cfg:         cfg,
 		rpc:         rpcClient,
 		store:       db,
 		decoder:     dec,
 		handlers:    handler.Global(),
 		broadcaster: broadcaster,
-	}, nil
+		dedup:       dedupColumns,
+		schemas:     schemas,
+	}
+
+	if err := e.registerRules(ctx, cfg.Rules); err != nil {
+		_ = db.Close()
+		rpcClient.Close()
+		return nil, fmt.Errorf("registering rules: %w", err)
+	}
+
+	if cfg.Sync.CheckpointRedisURL != "" {
+		if e.checkpoints, err = checkpoint.NewRedis(cfg.Sync.CheckpointRedisURL); err != nil {
+			_ = db.Close()
+			rpcClient.Close()
+			return nil, fmt.Errorf("configuring checkpoint mirror: %w", err)
+		}
+	}
+
+	return e, nil
 }
 
 // Run starts the sync loop.
@@ -175,90 +450,321 @@ func (e *Engine) Run(ctx context.Context) error {
 		Uint64("chainID", e.cfg.ChainID).
 		Msg("starting sync engine")
 
-	// Determine start block
-	startBlock, err := e.determineStartBlock(ctx)
-	if err != nil {
-		return fmt.Errorf("determining start block: %w", err)
+	e.running.Store(true)
+	defer func() {
+		s := e.shutdown()
+		s.doneOnce.Do(func() { close(s.done) })
+	}()
+
+	if err := startupDelay(ctx, e.cfg.Sync.StartupJitter); err != nil {
+		return nil // cancelled while waiting
+	}
+
+	// Fail fast if the node is still syncing or stalled
+	if !e.cfg.Sync.SkipWarmUp {
+		if err := e.warmUp(ctx); err != nil {
+			return fmt.Errorf("checking RPC node: %w", err)
+		}
+	}
+
+	defer e.startCheckpointMirror()()
+
+	// Determine start block: a mirrored cursor starts sync at once and is
+	// checked against the database in the background
+	startBlock, mirrored := e.loadCheckpoint(ctx)
+	if !mirrored {
+		var err error
+		if startBlock, err = e.determineStartBlock(ctx); err != nil {
+			return fmt.Errorf("determining start block: %w", err)
+		}
+		e.mirrorCheckpoint(startBlock)
 	}
 
+	e.mu.Lock()
 	e.lastBlock = startBlock
-	log.Info().Uint64("startBlock", startBlock).Msg("resuming from block")
+	e.mu.Unlock()
+	log.Info().Uint64("startBlock", startBlock).Bool("mirrored", mirrored).Msg("resuming from block")
+
+	if err := e.initSchedules(ctx, startBlock); err != nil {
+		return fmt.Errorf("initializing contract schedules: %w", err)
+	}
+	if mirrored {
+		defer e.startCursorCheck(ctx, startBlock)()
+	}
+
+	if limit := e.cfg.Sync.MaxDatabaseBytes; limit > 0 {
+		go e.guardDatabaseSize(ctx, limit, e.cfg.Sync.DiskCheckInterval)
+	}
 
-	// Start sync loop
+	// Start sync loop, supervised by the watchdog if enabled
+	if timeout := e.cfg.Sync.StallTimeout; timeout > 0 {
+		return e.superviseSyncLoop(ctx, timeout)
+	}
+	return e.syncLoop(ctx)
+}
+
+// resubscribeBackoff is the delay before resubscribing to new heads after
+// the subscription dropped, doubled for each failed attempt up to
+// maxResubscribeBackoff. A variable so tests can shorten it.
+var resubscribeBackoff = time.Second
+
+// maxResubscribeBackoff caps the delay between resubscription attempts.
+const maxResubscribeBackoff = time.Minute
+
+// syncLoop syncs new blocks until the context is cancelled. With a
+// WebSocket URL, new heads trigger each iteration; otherwise, or while a
+// dropped subscription is retried with backoff, the head is polled every
+// poll interval.
+//
+// Parameters:
+//   - ctx (context.Context): context for cancellation
+//
+// Returns:
+//   - error: nil on shutdown, error if sync must halt
+func (e *Engine) syncLoop(ctx context.Context) error {
 	ticker := time.NewTicker(e.cfg.PollInterval)
 	defer ticker.Stop()
 
+	heads := e.subscribeHeads(ctx)
+	stopping := e.shutdown().stopping
+
+	// Polling stands in while a dropped subscription is retried
+	var resubscribe <-chan time.Time
+	backoff := resubscribeBackoff
+
 	for {
 		select {
 		case <-ctx.Done():
 			log.Info().Msg("sync engine shutting down")
 			return nil
 
+		case <-stopping:
+			log.Info().Msg("sync engine stopped")
+			return nil
+
+		case _, ok := <-heads:
+			if !ok {
+				log.Warn().Dur("retryIn", backoff).Msg("newHeads subscription ended, polling until resubscribed")
+				heads = nil
+				resubscribe = time.After(backoff)
+				continue
+			}
+			// A live subscription is progress, even if the head didn't move
+			e.markProgress()
+
+		case <-resubscribe:
+			if heads = e.subscribeHeads(ctx); heads == nil {
+				backoff = min(backoff*2, maxResubscribeBackoff)
+				resubscribe = time.After(backoff)
+			} else {
+				backoff = resubscribeBackoff
+				resubscribe = nil
+			}
+			continue
+
 		case <-ticker.C:
-			if err := e.syncOnce(ctx); err != nil {
-				log.Error().Err(err).Msg("sync error")
-				// Continue on error - circuit breaker will handle RPC issues
+			if heads != nil {
+				// New heads drive sync while subscribed; a quiet chain
+				// sends none, which is not a stall
+				e.markProgress()
+				continue
 			}
 		}
+
+		if e.paused.Load() {
+			e.markProgress()
+			continue
+		}
+		if err := e.syncOnce(ctx); err != nil {
+			if errors.Is(err, errEndBlockReached) {
+				log.Info().Msg("all contracts reached their end block, stopping sync")
+				return nil
+			}
+			if errors.Is(err, ErrReorgTooDeep) || errors.Is(err, errCursorCheck) {
+				return fmt.Errorf("halting sync: %w", err)
+			}
+			log.Error().Err(err).Msg("sync error")
+			// Continue on error - circuit breaker will handle RPC issues
+			continue
+		}
+		e.markProgress()
 	}
 }
 
+// subscribeHeads subscribes to new heads when the RPC client supports it
+// and a WebSocket URL is configured.
+//
+// Parameters:
+//   - ctx (context.Context): subscription lifetime
+//
+// Returns:
+//   - <-chan *types.Header: new heads, nil to poll instead
+func (e *Engine) subscribeHeads(ctx context.Context) <-chan *types.Header {
+	sub, ok := e.rpc.(rpc.HeadSubscriber)
+	if !ok {
+		return nil
+	}
+
+	heads, err := sub.Subscribe(ctx)
+	if err != nil {
+		if !errors.Is(err, rpc.ErrNoWebSocket) {
+			log.Warn().Err(err).Msg("subscribing to new heads failed, polling instead")
+		}
+		return nil
+	}
+
+	log.Info().Msg("following new heads over WebSocket")
+	return heads
+}
+
 // syncOnce performs a single sync iteration.
 func (e *Engine) syncOnce(ctx context.Context) error {
+	// Roll back if the last indexed block is no longer canonical
+	if e.cursorChecked() {
+		if err := e.checkReorg(ctx); err != nil {
+			return fmt.Errorf("checking reorg: %w", err)
+		}
+	}
+
 	// Get current chain head
 	headBlock, err := e.rpc.BlockNumber(ctx)
 	if err != nil {
 		return fmt.Errorf("getting block number: %w", err)
 	}
 
+	e.mu.RLock()
+	lastBlock := e.lastBlock
+	batchSize := e.cfg.Sync.BatchSize
+	autoAnalyze, analyzeThreshold := e.cfg.Sync.AutoAnalyze, e.cfg.Sync.AutoAnalyzeRows
+	endBlock, bounded := syncEndBlock(e.cfg)
+	confirmations := e.cfg.Sync.Confirmations
+	e.mu.RUnlock()
+
+	// Blocks short of sync.confirmations are not indexed yet
+	headBlock = confirmedHead(headBlock, confirmations)
+
+	// A fixed window is done once every contract reached its end block,
+	// including the deferred ranges of scheduled contracts
+	if bounded && lastBlock >= endBlock {
+		if err := e.awaitCursorCheck(ctx, lastBlock); err != nil {
+			if errors.Is(err, errCursorMoved) {
+				return nil
+			}
+			return err
+		}
+		if e.schedulesPending(endBlock) {
+			return e.catchUpSchedules(ctx, endBlock)
+		}
+		return errEndBlockReached
+	}
+
 	// Update sync lag metric
-	lag := int64(headBlock) - int64(e.lastBlock) //nolint:gosec // G115: Block numbers won't overflow int64
+	lag := int64(headBlock) - int64(lastBlock) //nolint:gosec // G115: Block numbers won't overflow int64
 	if lag < 0 {
 		lag = 0
 	}
 	syncLag.Set(float64(lag))
+	secondsBehindTip.Set(e.secondsBehind(time.Now(), uint64(lag)))
+
+	// Sync stops at the end block of a fixed window
+	target := headBlock
+	if bounded && endBlock < target {
+		target = endBlock
+	}
 
-	// Nothing to sync
-	if e.lastBlock >= headBlock {
+	ranges := syncRanges(lastBlock, target, batchSize, e.fetchConcurrency())
+	if len(ranges) == 0 {
+		// Nothing to sync
+		e.trackSyncState(lastBlock, headBlock)
 		return nil
 	}
 
-	// Calculate batch range
-	fromBlock := e.lastBlock + 1
-	toBlock := fromBlock + e.cfg.Sync.BatchSize - 1
-	if toBlock > headBlock {
-		toBlock = headBlock
+	// Batches are fetched concurrently but committed in block order, so
+	// the sync cursor never skips an uncommitted batch
+	fetchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	for i, result := range e.fetchBatches(fetchCtx, ranges, headBlock) {
+		// On shutdown, the batches committed so far are kept and the rest
+		// are fetched again on restart
+		if e.stopRequested(ctx) {
+			return nil
+		}
+		if i == 0 {
+			if err := e.awaitCursorCheck(ctx, lastBlock); err != nil {
+				if errors.Is(err, errCursorMoved) {
+					return nil // fetched again from the database's cursor
+				}
+				return err
+			}
+		}
+		if err := e.commitBatch(ctx, <-result, headBlock, autoAnalyze, analyzeThreshold); err != nil {
+			return err
+		}
 	}
 
-	log.Debug().
-		Uint64("from", fromBlock).
-		Uint64("to", toBlock).
-		Uint64("head", headBlock).
-		Msg("syncing blocks")
+	return nil
+}
+
+// commitBatch stores a fetched batch, advances the sync cursor and
+// publishes the new state.
+//
+// Parameters:
+//   - ctx (context.Context): context for cancellation
+//   - batch (*fetchedBatch): fetched batch, possibly holding a fetch error
+//   - headBlock (uint64): effective chain head
+//   - autoAnalyze (bool): sync.auto_analyze
+//   - analyzeThreshold (int64): sync.auto_analyze_rows
+//
+// Returns:
+//   - error: nil on success, fetch or store error on failure
+func (e *Engine) commitBatch(ctx context.Context, batch *fetchedBatch, headBlock uint64, autoAnalyze bool, analyzeThreshold int64) error {
+	if batch.err != nil {
+		return batch.err
+	}
+	fromBlock, toBlock, header := batch.from, batch.to, batch.header
 
-	// Fetch and process logs
-	if err := e.processBlockRange(ctx, fromBlock, toBlock); err != nil {
+	logCount, err := e.storeBatch(ctx, batch)
+	if err != nil {
 		return fmt.Errorf("processing blocks %d-%d: %w", fromBlock, toBlock, err)
 	}
+	batchDuration.Observe(time.Since(batch.started).Seconds())
+	batchBlocks.Observe(float64(toBlock - fromBlock + 1))
+	batchLogs.Observe(float64(logCount))
+	lastBatchTimestamp.Set(float64(time.Now().Unix()))
 
 	// Broadcast blocks to subscribers (if broadcaster is configured)
 	if e.broadcaster != nil {
-		// Broadcast the latest processed block
-		header, err := e.rpc.HeaderByNumber(ctx, new(big.Int).SetUint64(toBlock))
-		if err == nil && header != nil {
-			e.broadcaster.BroadcastBlock(&model.Block{
-				Number:     strconv.FormatUint(header.Number.Uint64(), 10),
-				Hash:       header.Hash().Hex(),
-				Timestamp:  time.Unix(int64(header.Time), 0), //nolint:gosec // G115: Timestamp won't overflow
-				ParentHash: header.ParentHash.Hex(),
-			})
-		}
+		e.broadcaster.ReleaseEvents(toBlock)
+		e.broadcaster.BroadcastBlock(&model.Block{
+			Number:     strconv.FormatUint(header.Number.Uint64(), 10),
+			Hash:       header.Hash().Hex(),
+			Timestamp:  time.Unix(int64(header.Time), 0).UTC(), //nolint:gosec // G115: Timestamp won't overflow
+			ParentHash: header.ParentHash.Hex(),
+		})
 	}
 
 	// Update state
+	e.mu.Lock()
 	e.lastBlock = toBlock
+	for _, ref := range batch.window {
+		e.recordBlockHash(ref.Number, ref.Hash)
+	}
+	e.recordBlockHash(toBlock, header.Hash())
+	e.mu.Unlock()
+	e.lastBlockAt.Store(int64(header.Time)) //nolint:gosec // G115: Timestamp won't overflow
 	currentBlock.Set(float64(toBlock))
 	blocksIndexed.Add(float64(toBlock - fromBlock + 1))
+	e.mirrorCheckpoint(toBlock)
+	secondsBehindTip.Set(e.secondsBehind(time.Now(), headBlock-toBlock))
+
+	// Refresh planner statistics after a backfill, off the sync path
+	if autoAnalyze {
+		if trigger := e.analyzeDue(logCount, toBlock >= headBlock, analyzeThreshold); trigger != "" {
+			go e.runAnalyze(ctx, trigger)
+		}
+	}
+
+	e.trackSyncState(toBlock, headBlock)
 
 	// Broadcast sync status to subscribers (if broadcaster is configured)
 	if e.broadcaster != nil {
@@ -280,70 +786,346 @@ func (e *Engine) syncOnce(ctx context.Context) error {
 	return nil
 }
 
-// processBlockRange fetches and processes logs for a block range.
-func (e *Engine) processBlockRange(ctx context.Context, fromBlock, toBlock uint64) error {
+// confirmedHead returns the highest block with at least confirmations
+// blocks built on it.
+//
+// Parameters:
+//   - headBlock (uint64): chain head
+//   - confirmations (uint64): required confirmations (0 = the head itself)
+//
+// Returns:
+//   - uint64: effective head for indexing, 0 if no block is confirmed yet
+func confirmedHead(headBlock, confirmations uint64) uint64 {
+	if headBlock < confirmations {
+		return 0
+	}
+	return headBlock - confirmations
+}
+
+// batchRange returns the next batch after lastBlock, at most batchSize
+// blocks and up to headBlock.
+//
+// Parameters:
+//   - lastBlock (uint64): last indexed block
+//   - headBlock (uint64): effective head (see confirmedHead)
+//   - batchSize (uint64): maximum blocks per batch
+//
+// Returns:
+//   - uint64: first block of the batch
+//   - uint64: last block of the batch
+//   - bool: false if there is nothing to sync
+func batchRange(lastBlock, headBlock, batchSize uint64) (uint64, uint64, bool) {
+	if lastBlock >= headBlock {
+		return 0, 0, false
+	}
+
+	fromBlock := lastBlock + 1
+	toBlock := fromBlock + batchSize - 1
+	if toBlock > headBlock {
+		toBlock = headBlock
+	}
+	return fromBlock, toBlock, true
+}
+
+// secondsBehind estimates how far indexing trails real time: now minus the
+// last indexed block's timestamp, or the block lag times the network block
+// time until a batch has been indexed.
+//
+// Parameters:
+//   - now (time.Time): current time
+//   - lagBlocks (uint64): blocks between the indexed block and the head
+//
+// Returns:
+//   - float64: seconds behind the chain tip
+func (e *Engine) secondsBehind(now time.Time, lagBlocks uint64) float64 {
+	if at := e.lastBlockAt.Load(); at > 0 {
+		return max(now.Sub(time.Unix(at, 0)).Seconds(), 0)
+	}
+
+	preset, ok := e.cfg.NetworkPreset()
+	if !ok {
+		return 0
+	}
+	return float64(lagBlocks) * preset.BlockTime.Seconds()
+}
+
+// fetchBatch fetches a batch's last header, its logs, the hashes of its
+// blocks within the reorg window and, with sync.track_reverts, its
+// reverted transactions. Errors are returned in the batch.
+//
+// Parameters:
+//   - ctx (context.Context): context for cancellation
+//   - fromBlock (uint64): first block of the batch
+//   - toBlock (uint64): last block of the batch
+//   - headBlock (uint64): effective chain head
+//
+// Returns:
+//   - *fetchedBatch: the fetched batch
+func (e *Engine) fetchBatch(ctx context.Context, fromBlock, toBlock, headBlock uint64) *fetchedBatch {
+	batch := &fetchedBatch{from: fromBlock, to: toBlock, started: time.Now()}
+
+	e.mu.RLock()
+	verifyHashes := e.cfg.Sync.VerifyBlockHashes
+	trackReverts := e.cfg.Sync.TrackReverts
+	bloomFilter := e.cfg.Sync.BloomFilter
+	e.mu.RUnlock()
+
+	log.Debug().
+		Uint64("from", fromBlock).
+		Uint64("to", toBlock).
+		Msg("syncing blocks")
+
+	// Fetch the batch's last block for the sync cursor, reorg tracking and broadcasting
+	header, err := e.rpc.HeaderByNumber(ctx, new(big.Int).SetUint64(toBlock))
+	if err != nil {
+		batch.err = fmt.Errorf("getting block %d header: %w", toBlock, err)
+		return batch
+	}
+	batch.header = header
+
+	// Failed transactions emit no logs: find them in the blocks themselves
+	if trackReverts {
+		if batch.reverts, err = e.scanReverts(ctx, fromBlock, toBlock); err != nil {
+			batch.err = fmt.Errorf("processing blocks %d-%d: scanning reverted transactions: %w", fromBlock, toBlock, err)
+			return batch
+		}
+	}
+
+	// The header's bloom can rule out a single block without a getLogs call
+	if bloomFilter && fromBlock == toBlock && e.bloomExcludes(header) {
+		log.Debug().Uint64("block", toBlock).Msg("logs bloom excludes block, skipping getLogs")
+		batch.commit = func() {}
+		return batch
+	}
+
+	fetch := e.fetchSyncLogs
+	if verifyHashes {
+		fetch = e.fetchCanonicalLogs
+	}
+	logs, commit, err := fetch(ctx, fromBlock, toBlock)
+	if err != nil {
+		batch.err = fmt.Errorf("processing blocks %d-%d: %w", fromBlock, toBlock, err)
+		return batch
+	}
+
+	// Every block that can still be reorged needs its hash for the common
+	// ancestor search, not only the batch's last one
+	if batch.window, err = e.windowHashes(ctx, fromBlock, toBlock, headBlock); err != nil {
+		batch.err = fmt.Errorf("processing blocks %d-%d: %w", fromBlock, toBlock, err)
+		return batch
+	}
+
+	batch.logs = e.dropEndedLogs(logs)
+	batch.commit = commit
+	return batch
+}
+
+// bloomExcludes reports whether a block's logsBloom rules out every log
+// the sync fetch would request. Contracts on their own schedule fetch
+// older ranges and filter mode consumes filter changes, so neither is
+// ever skipped.
+//
+// Parameters:
+//   - header (*types.Header): header of the block
+//
+// Returns:
+//   - bool: true if getLogs can be skipped for the block
+func (e *Engine) bloomExcludes(header *types.Header) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if len(e.schedules) > 0 || e.cfg.Sync.FilterMode {
+		return false
+	}
+	addresses := e.decoder.GetAddresses()
+	topics := [][]common.Hash{e.decoder.GetEventSignatures()}
+	return !rpc.BlockMayContain(header, addresses, topics)
+}
+
+// storeBatch processes a fetched batch's logs and advances the stored
+// sync cursor in one transaction, and returns the number of logs. Batches
+// without logs, reverts or coverage only advance the cursor.
+func (e *Engine) storeBatch(ctx context.Context, batch *fetchedBatch) (int, error) {
+	e.mu.RLock()
+	coverage := e.cfg.Sync.BlockCoverage
+	e.mu.RUnlock()
+
+	logs, reverts := batch.logs, batch.reverts
+
+	// Empty batches only need a transaction to record their coverage
+	if len(logs) > 0 || coverage || len(reverts) > 0 {
+		// A started commit finishes even if the engine is shutting down
+		commitCtx, release := e.commitContext(ctx)
+		defer release()
+
+		batchCtx, pending := e.deferBroadcasts(commitCtx)
+		if err := e.store.Transaction(commitCtx, func(tx *gorm.DB) error {
+			if err := e.processLogs(batchCtx, tx, logs); err != nil {
+				return err
+			}
+			if err := store.CreateRevertedTxsTx(tx, reverts); err != nil {
+				return err
+			}
+			if coverage {
+				if err := store.UpsertBlockCoverageTx(tx, batch.from, batch.to, logCounts(logs)); err != nil {
+					return err
+				}
+			}
+			return store.UpsertSyncStatusTx(tx, store.SyncStatus{
+				Contract:      store.SyncStatusAll,
+				LastBlock:     batch.to,
+				LastBlockHash: batch.header.Hash().Hex(),
+			})
+		}); err != nil {
+			return 0, err
+		}
+		e.publishCommitted(pending, batch.to)
+	} else {
+		// Advance the cursor past event-free blocks, so a restart doesn't
+		// rescan them
+		commitCtx, release := e.commitContext(ctx)
+		defer release()
+
+		if err := e.store.UpsertSyncStatus(commitCtx, store.SyncStatus{
+			Contract:      store.SyncStatusAll,
+			LastBlock:     batch.to,
+			LastBlockHash: batch.header.Hash().Hex(),
+		}); err != nil {
+			return 0, err
+		}
+	}
+
+	batch.commit()
+	for _, revert := range reverts {
+		revertedTxsTotal.WithLabelValues(revert.ContractName).Inc()
+	}
+	return len(logs), nil
+}
+
+// logCounts returns the number of logs per block.
+func logCounts(logs []types.Log) map[uint64]int {
+	counts := make(map[uint64]int)
+	for _, l := range logs {
+		counts[l.BlockNumber]++
+	}
+	return counts
+}
+
+// fetchBlockRangeLogs fetches logs for the registered contracts in a block range.
+func (e *Engine) fetchBlockRangeLogs(ctx context.Context, fromBlock, toBlock uint64) ([]types.Log, error) {
 	// Build filter query
+	e.mu.RLock()
 	addresses := e.decoder.GetAddresses()
 	topics := [][]common.Hash{e.decoder.GetEventSignatures()}
+	e.mu.RUnlock()
 
 	// Fetch logs with binary split on range errors
 	logs, err := e.rpc.FetchLogs(ctx, addresses, topics, fromBlock, toBlock)
 	if err != nil {
-		return fmt.Errorf("fetching logs: %w", err)
+		return nil, fmt.Errorf("fetching logs: %w", err)
 	}
 
-	if len(logs) == 0 {
-		return nil
+	if len(logs) > 0 {
+		log.Debug().
+			Uint64("from", fromBlock).
+			Uint64("to", toBlock).
+			Int("logs", len(logs)).
+			Msg("fetched logs")
 	}
 
-	log.Debug().
-		Uint64("from", fromBlock).
-		Uint64("to", toBlock).
-		Int("logs", len(logs)).
-		Msg("fetched logs")
-
-	// Process logs in a transaction
-	return e.store.Transaction(ctx, func(tx *gorm.DB) error {
-		for _, logEntry := range logs {
-			if err := e.processLog(ctx, tx, logEntry); err != nil {
-				return fmt.Errorf("processing log at block %d: %w", logEntry.BlockNumber, err)
-			}
+	return logs, nil
+}
+
+// processLogs processes fetched logs within a transaction.
+func (e *Engine) processLogs(ctx context.Context, tx *gorm.DB, logs []types.Log) error {
+	e.mu.RLock()
+	workers := e.cfg.Sync.HandlerWorkers
+	e.mu.RUnlock()
+
+	if workers > 1 {
+		return e.processLogsParallel(ctx, tx, logs, workers)
+	}
+
+	for _, logEntry := range logs {
+		if err := e.processLog(ctx, tx, logEntry); err != nil {
+			return fmt.Errorf("processing log at block %d: %w", logEntry.BlockNumber, err)
 		}
-		return nil
-	})
+	}
+	return nil
 }
 
 // processLog decodes and handles a single log entry.
 // All decoded events are auto-stored in the generic events table.
 // Typed handlers are optional and run only if registered.
 func (e *Engine) processLog(ctx context.Context, tx *gorm.DB, logEntry types.Log) error {
+	handlerCtx, err := e.prepareLog(ctx, tx, logEntry)
+	if err != nil || handlerCtx == nil {
+		return err
+	}
+	return e.runHandler(handlerCtx)
+}
+
+// prepareLog decodes, transforms, stores and broadcasts a log entry, and
+// returns the context for its typed handler.
+//
+// Returns:
+//   - *handler.Context: handler context, nil if the log was skipped or has no handler
+//   - error: nil on success, error on failure
+func (e *Engine) prepareLog(ctx context.Context, tx *gorm.DB, logEntry types.Log) (*handler.Context, error) {
+	logsTotal.Inc()
+
 	// Decode the event
+	e.mu.RLock()
 	event, err := e.decoder.Decode(logEntry)
+	e.mu.RUnlock()
 	if err != nil {
+		logsSkipped.WithLabelValues(decodeSkipReason(err)).Inc()
 		log.Warn().
 			Err(err).
 			Str("txHash", logEntry.TxHash.Hex()).
 			Uint64("block", logEntry.BlockNumber).
 			Msg("failed to decode log")
-		return nil // Skip unknown events
+		return nil, nil // Skip unknown events
+	}
+
+	if err := e.applyTransforms(ctx, event); err != nil {
+		return nil, err
+	}
+
+	// Check the data against its declared schema before it reaches consumers
+	if skip, err := e.checkEventSchema(event); err != nil || skip {
+		if skip {
+			logsSkipped.WithLabelValues("schema_violation").Inc()
+		}
+		return nil, err
 	}
 
 	// Get block info for context
 	header, err := e.rpc.HeaderByNumber(ctx, new(big.Int).SetUint64(logEntry.BlockNumber))
 	if err != nil {
-		return fmt.Errorf("getting block header: %w", err)
+		return nil, fmt.Errorf("getting block header: %w", err)
 	}
 
-	blockTime := time.Unix(int64(header.Time), 0) //nolint:gosec // G115: Timestamp won't overflow
+	blockTime := time.Unix(int64(header.Time), 0).UTC() //nolint:gosec // G115: Timestamp won't overflow
+
+	// Serialize event data, applying the size limit
+	dataJSON, skip, err := e.encodeEventData(logEntry, event)
+	if err != nil {
+		return nil, err
+	}
+	if skip {
+		logsSkipped.WithLabelValues("data_limit").Inc()
+		return nil, nil
+	}
 
 	// Auto-store event in generic events table (always)
-	if err := e.storeGenericEvent(tx, logEntry, event, blockTime); err != nil {
-		return fmt.Errorf("storing generic event: %w", err)
+	if err := e.storeGenericEvent(tx, logEntry, event, dataJSON, blockTime); err != nil {
+		return nil, fmt.Errorf("storing generic event: %w", err)
 	}
 
 	// Broadcast event to subscribers (if broadcaster is configured)
 	if e.broadcaster != nil {
-		e.broadcaster.BroadcastEvent(&model.GenericEvent{
+		e.broadcastEvent(ctx, &model.GenericEvent{
 			ID:          "0", // ID not available until tx commits
 			BlockNumber: strconv.FormatUint(logEntry.BlockNumber, 10),
 			TxHash:      logEntry.TxHash.Hex(),
@@ -355,6 +1137,7 @@ func (e *Engine) processLog(ctx context.Context, tx *gorm.DB, logEntry types.Log
 			Data:        convertEventData(event.Data),
 		})
 	}
+	e.notifyListeners(event)
 
 	// Build handler context for optional typed handlers
 	handlerCtx := &handler.Context{
@@ -365,37 +1148,108 @@ func (e *Engine) processLog(ctx context.Context, tx *gorm.DB, logEntry types.Log
 			Time:       blockTime,
 			ParentHash: header.ParentHash.Hex(),
 		},
-		Log:   logEntry,
-		Event: event,
+		Log:               logEntry,
+		Event:             event,
+		ChecksumAddresses: e.cfg.Sync.StoreAddressCase == config.AddressCaseChecksum,
+		Contracts:         e,
 	}
 
-	// Execute typed handler if registered (optional - for performance optimization)
-	if e.handlers.HasHandler(event.EventID) {
-		if err := e.handlers.Handle(handlerCtx); err != nil {
-			return fmt.Errorf("handling event %s: %w", event.EventID, err)
-		}
+	logsDecoded.Inc()
+
+	// Typed handlers are optional (for performance optimization)
+	if !e.handlers.HasHandler(event.EventID) {
+		return nil, nil
 	}
+	e.bindRuleBroadcasts(ctx, handlerCtx)
+	return handlerCtx, nil
+}
 
+// runHandler executes the typed handler registered for an event.
+func (e *Engine) runHandler(handlerCtx *handler.Context) error {
+	defer e.ruleBroadcasts.Delete(handlerCtx)
+	if err := e.handlers.Handle(handlerCtx); err != nil {
+		return fmt.Errorf("handling event %s: %w", handlerCtx.Event.EventID, err)
+	}
 	return nil
 }
 
-// storeGenericEvent saves a decoded event to the generic events table.
+// decodeSkipReason maps a decode error to the rafale_logs_skipped_total reason label.
+func decodeSkipReason(err error) string {
+	switch {
+	case errors.Is(err, decoder.ErrUnknownEvent):
+		return "unknown_event"
+	case errors.Is(err, decoder.ErrNoTopics):
+		return "no_topics"
+	case errors.Is(err, decoder.ErrUnpack):
+		return "unpack_error"
+	default:
+		return "decode_error"
+	}
+}
+
+// encodeEventData serializes decoded event data (see storedEventData)
+// and applies the sync.max_data_bytes policy.
 //
 // Parameters:
-//   - tx (*gorm.DB): database transaction
 //   - logEntry (types.Log): raw Ethereum log
 //   - event (*decoder.DecodedEvent): decoded event data
-//   - blockTime (time.Time): block timestamp
 //
 // Returns:
+//   - []byte: JSON data to store
+//   - bool: true if the event should be skipped
 //   - error: nil on success, error on failure
-func (e *Engine) storeGenericEvent(tx *gorm.DB, logEntry types.Log, event *decoder.DecodedEvent, blockTime time.Time) error {
-	// Serialize event data to JSON
-	dataJSON, err := json.Marshal(event.Data)
+func (e *Engine) encodeEventData(logEntry types.Log, event *decoder.DecodedEvent) ([]byte, bool, error) {
+	dataJSON, err := json.Marshal(storedEventData(event.Data))
 	if err != nil {
-		return fmt.Errorf("marshaling event data: %w", err)
+		return nil, false, fmt.Errorf("marshaling event data: %w", err)
+	}
+
+	limit := e.cfg.Sync.MaxDataBytes
+	if limit <= 0 || len(dataJSON) <= limit {
+		return dataJSON, false, nil
 	}
 
+	policy := e.cfg.Sync.DataOverflowPolicy
+	if policy == "" {
+		policy = config.DataPolicyTruncate
+	}
+	oversizedEvents.WithLabelValues(policy).Inc()
+	log.Warn().
+		Str("txHash", logEntry.TxHash.Hex()).
+		Uint("logIndex", logEntry.Index).
+		Str("event", event.EventID).
+		Int("size", len(dataJSON)).
+		Int("limit", limit).
+		Str("policy", policy).
+		Msg("event data exceeds max_data_bytes")
+
+	dataJSON, skip, err := limitEventData(dataJSON, limit, policy)
+	if err != nil {
+		return nil, false, fmt.Errorf("limiting event data: %w", err)
+	}
+	return dataJSON, skip, nil
+}
+
+// formatAddress formats an address for storage per sync.store_address_case.
+func (e *Engine) formatAddress(addr common.Address) string {
+	if e.cfg.Sync.StoreAddressCase == config.AddressCaseChecksum {
+		return addr.Hex()
+	}
+	return strings.ToLower(addr.Hex())
+}
+
+// storeGenericEvent saves a decoded event to the generic events table.
+//
+// Parameters:
+//   - tx (*gorm.DB): database transaction
+//   - logEntry (types.Log): raw Ethereum log
+//   - event (*decoder.DecodedEvent): decoded event data
+//   - dataJSON ([]byte): serialized event data
+//   - blockTime (time.Time): block timestamp
+//
+// Returns:
+//   - error: nil on success, error on failure
+func (e *Engine) storeGenericEvent(tx *gorm.DB, logEntry types.Log, event *decoder.DecodedEvent, dataJSON []byte, blockTime time.Time) error {
 	genericEvent := &store.Event{
 		BaseEvent: store.BaseEvent{
 			BlockNumber: logEntry.BlockNumber,
@@ -405,39 +1259,97 @@ func (e *Engine) storeGenericEvent(tx *gorm.DB, logEntry types.Log, event *decod
 			Timestamp:   blockTime,
 		},
 		ContractName: event.ContractName,
-		ContractAddr: logEntry.Address.Hex(),
+		ContractAddr: e.formatAddress(logEntry.Address),
 		EventName:    event.EventName,
 		EventSig:     logEntry.Topics[0].Hex(),
+		BlockHash:    logEntry.BlockHash.Hex(),
 		Data:         datatypes.JSON(dataJSON),
 	}
 
-	if err := tx.Create(genericEvent).Error; err != nil {
+	if e.cfg.Sync.StoreRawLog {
+		rawTopics, err := json.Marshal(logEntry.Topics)
+		if err != nil {
+			return fmt.Errorf("marshaling raw topics: %w", err)
+		}
+		genericEvent.RawTopics = datatypes.JSON(rawTopics)
+		genericEvent.RawData = logEntry.Data
+	}
+
+	if err := store.CreateEventTx(tx, genericEvent, e.dedup); err != nil {
 		return fmt.Errorf("inserting generic event: %w", err)
 	}
 
 	return nil
 }
 
-// determineStartBlock finds the starting block for sync.
-// Uses MAX(block_number) from generic events table per Rafale design.
+// dedupColumnsFor returns the events columns identifying a log under a
+// sync.dedup_key mode.
+func dedupColumnsFor(key string) []string {
+	switch key {
+	case config.DedupKeyBlockHash:
+		return []string{"block_hash", "log_index"}
+	case config.DedupKeyPosition:
+		return []string{"block_number", "tx_index", "log_index"}
+	default:
+		return []string{"tx_hash", "log_index"}
+	}
+}
+
+// determineStartBlock finds the starting block for sync, after checking the
+// stored sync cursor is still canonical (see verifyResumeBlock). Sync
+// resumes from the sync_statuses cursor; MAX(block_number) from the generic
+// events table is only used for databases indexed before the cursor existed.
 func (e *Engine) determineStartBlock(ctx context.Context) (uint64, error) {
-	// Query MAX(block_number) from generic events table (source of truth)
-	maxBlock, err := e.store.GetMaxBlockNumber(ctx, "events")
+	// Roll back a reorg that happened while the engine was down
+	if err := e.verifyResumeBlock(ctx); err != nil {
+		return 0, fmt.Errorf("verifying resume block: %w", err)
+	}
+
+	cursor, err := e.store.GetSyncStatus(ctx, store.SyncStatusAll)
 	if err != nil {
-		return 0, fmt.Errorf("getting max block: %w", err)
+		return 0, fmt.Errorf("getting sync cursor: %w", err)
 	}
 
-	// If we have indexed data, resume from there
-	if maxBlock > 0 {
+	var maxBlock uint64
+	if cursor == nil {
+		if maxBlock, err = e.store.GetMaxBlockNumber(ctx, "events"); err != nil {
+			return 0, fmt.Errorf("getting max block: %w", err)
+		}
+	}
+
+	return resumeBlock(cursor, maxBlock, e.cfg.Contracts), nil
+}
+
+// resumeBlock picks the last indexed block to resume after (syncOnce adds
+// 1): the sync cursor, else the highest indexed event block, else the
+// lowest configured start_block.
+//
+// Parameters:
+//   - cursor (*store.SyncStatus): engine-wide sync cursor, nil if never written
+//   - maxIndexed (uint64): MAX(block_number) of the events table (0 = empty)
+//   - contracts (map[string]config.ContractConfig): configured contracts
+//
+// Returns:
+//   - uint64: block to resume after
+func resumeBlock(cursor *store.SyncStatus, maxIndexed uint64, contracts map[string]config.ContractConfig) uint64 {
+	if cursor != nil {
 		log.Info().
-			Uint64("maxIndexedBlock", maxBlock).
+			Uint64("lastBlock", cursor.LastBlock).
+			Msg("found sync cursor, resuming")
+		return cursor.LastBlock
+	}
+
+	// Databases indexed before the cursor existed
+	if maxIndexed > 0 {
+		log.Info().
+			Uint64("maxIndexedBlock", maxIndexed).
 			Msg("found existing indexed data in events table, resuming")
-		return maxBlock, nil // Start from last indexed block (syncOnce will add 1)
+		return maxIndexed
 	}
 
 	// Otherwise use minimum configured start_block
 	minConfiguredStart := ^uint64(0)
-	for _, contract := range e.cfg.Contracts {
+	for _, contract := range contracts {
 		if contract.StartBlock < minConfiguredStart {
 			minConfiguredStart = contract.StartBlock
 		}
@@ -447,9 +1359,12 @@ func (e *Engine) determineStartBlock(ctx context.Context) (uint64, error) {
 		minConfiguredStart = 0
 	}
 
-	return minConfiguredStart, nil
+	return minConfiguredStart
 }
 
+// reloadABITimeout bounds reading the ABIs of a reloaded configuration.
+const reloadABITimeout = 30 * time.Second
+
 // Reload reloads the engine configuration and re-registers contracts.
 // Used for hot-reload during development.
 //
@@ -461,28 +1376,49 @@ func (e *Engine) determineStartBlock(ctx context.Context) (uint64, error) {
 func (e *Engine) Reload(newCfg *config.Config) error {
 	log.Info().Msg("reloading engine configuration")
 
+	// Resolve ABIs, possibly from the explorer, before taking the lock, so
+	// a slow fetch doesn't hold up sync
+	ctx, cancel := context.WithTimeout(context.Background(), reloadABITimeout)
+	defer cancel()
+
+	abis := make(map[string][]string, len(newCfg.Contracts))
+	for name, contract := range newCfg.Contracts {
+		abiJSONs, err := readContractABIs(ctx, newCfg, name, contract)
+		if err != nil {
+			return err
+		}
+		abis[name] = abiJSONs
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
 	// Clear existing decoder state
 	e.decoder.Clear()
 
 	// Re-register contracts from new config
 	for name, contract := range newCfg.Contracts {
-		abiJSON, err := os.ReadFile(contract.ABI)
+		events, err := registerContractABIs(e.decoder, name, contract, abis[name])
 		if err != nil {
-			return fmt.Errorf("reading ABI for %s: %w", name, err)
-		}
-
-		addr := common.HexToAddress(contract.Address)
-		if err := e.decoder.RegisterContract(name, addr, string(abiJSON), contract.Events); err != nil {
-			return fmt.Errorf("registering contract %s: %w", name, err)
+			return err
 		}
 
 		log.Info().
 			Str("contract", name).
 			Str("address", contract.Address).
-			Int("events", len(contract.Events)).
+			Strs("events", events).
 			Msg("re-registered contract")
 	}
 
+	logCollisions(e.decoder)
+
+	// Rules are rebuilt against the new ABIs
+	if err := e.registerRules(ctx, newCfg.Rules); err != nil {
+		return fmt.Errorf("registering rules: %w", err)
+	}
+
+	e.reloadSchedules(newCfg)
+
 	// Update config reference
 	e.cfg = newCfg
 
@@ -493,11 +1429,179 @@ func (e *Engine) Reload(newCfg *config.Config) error {
 	return nil
 }
 
+// resolveChainID checks the RPC chain ID against the configured expectation.
+// Without one, the detected chain ID is adopted.
+//
+// Parameters:
+//   - cfg (*config.Config): configuration
+//   - detected (uint64): chain ID reported by the RPC (eth_chainId)
+//
+// Returns:
+//   - uint64: chain ID to use
+//   - error: nil on success, error on a disallowed mismatch or if the RPC reports 0
+func resolveChainID(cfg *config.Config, detected uint64) (uint64, error) {
+	if detected == 0 {
+		return 0, fmt.Errorf("RPC reported chain ID 0")
+	}
+
+	if cfg.ExpectedChainID != 0 {
+		if detected == cfg.ExpectedChainID {
+			log.Info().Uint64("expectedChainID", cfg.ExpectedChainID).Uint64("detectedChainID", detected).Msg("RPC chain ID matches expected chain ID")
+			return detected, nil
+		}
+		if !cfg.Sync.AllowChainIDMismatch {
+			log.Error().
+				Uint64("expectedChainID", cfg.ExpectedChainID).
+				Uint64("detectedChainID", detected).
+				Str("network", cfg.Network).
+				Msg("RPC chain ID differs from expected chain ID (set sync.allow_chain_id_mismatch to proceed)")
+			return 0, fmt.Errorf("chain ID mismatch: expected %d, got %d", cfg.ExpectedChainID, detected)
+		}
+		log.Warn().
+			Uint64("expectedChainID", cfg.ExpectedChainID).
+			Uint64("detectedChainID", detected).
+			Str("network", cfg.Network).
+			Msg("ALERT: RPC chain ID differs from expected chain ID; continuing with detected chain ID because allow_chain_id_mismatch is set")
+		return detected, nil
+	}
+
+	if cfg.ChainID != 0 && cfg.ChainID != detected {
+		log.Warn().
+			Uint64("presetChainID", cfg.ChainID).
+			Uint64("detectedChainID", detected).
+			Str("network", cfg.Network).
+			Msg("RPC chain ID differs from network preset; using detected chain ID (set chain_id to enforce)")
+	} else {
+		log.Info().Uint64("chainID", detected).Msg("detected chain ID from RPC")
+	}
+
+	return detected, nil
+}
+
+// registerContract registers a contract with the decoder, merging its
+// ABIs, and logs name conflicts as warnings instead of failing.
+func registerContract(dec *decoder.Decoder, name string, addr common.Address, abiJSONs []string, events []string, aliases map[string]string) error {
+	err := dec.RegisterContractABIs(name, addr, abiJSONs, events, aliases)
+
+	var conflict *decoder.NameConflictWarning
+	if errors.As(err, &conflict) {
+		log.Warn().
+			Str("contract", name).
+			Str("existing", conflict.Existing.Hex()).
+			Str("address", conflict.Address.Hex()).
+			Msg("contract name already registered to a different address")
+		return nil
+	}
+
+	return err
+}
+
+// registerConfiguredContract reads a configured contract's ABIs and
+// registers them (see registerContractABIs).
+//
+// Returns:
+//   - []string: the registered event names
+//   - error: nil on success, read or registration error on failure
+func registerConfiguredContract(ctx context.Context, dec *decoder.Decoder, cfg *config.Config, name string, contract config.ContractConfig) ([]string, error) {
+	abiJSONs, err := readContractABIs(ctx, cfg, name, contract)
+	if err != nil {
+		return nil, err
+	}
+	return registerContractABIs(dec, name, contract, abiJSONs)
+}
+
+// readContractABIs reads a configured contract's ABIs, fetching them from
+// the explorer for abi: etherscan.
+//
+// Returns:
+//   - []string: the ABI JSONs, in configured order
+//   - error: nil on success, read or fetch error on failure
+func readContractABIs(ctx context.Context, cfg *config.Config, name string, contract config.ContractConfig) ([]string, error) {
+	paths := contract.ABIPaths()
+	abiJSONs := make([]string, 0, len(paths))
+	for _, path := range paths {
+		abiJSON, err := readABI(ctx, cfg, contract.Address, path)
+		if err != nil {
+			return nil, fmt.Errorf("reading ABI %s for %s: %w", path, name, err)
+		}
+		abiJSONs = append(abiJSONs, abiJSON)
+	}
+	return abiJSONs, nil
+}
+
+// registerContractABIs registers a configured contract's ABIs. With
+// index_all_events, the event list is resolved from the ABIs, and aliases
+// (unchecked by config validation) must name ABI events.
+//
+// Returns:
+//   - []string: the registered event names
+//   - error: nil on success, registration error on failure
+func registerContractABIs(dec *decoder.Decoder, name string, contract config.ContractConfig, abiJSONs []string) ([]string, error) {
+	addr := common.HexToAddress(contract.Address)
+	if err := registerContract(dec, name, addr, abiJSONs, contract.Events, contract.EventAliases); err != nil {
+		return nil, fmt.Errorf("registering contract %s: %w", name, err)
+	}
+
+	events := dec.ContractEvents(name)
+	if contract.IndexAllEvents {
+		for abiName, alias := range contract.EventAliases {
+			if !slices.Contains(events, alias) {
+				return nil, fmt.Errorf("registering contract %s: event_aliases: %s is not an ABI event", name, abiName)
+			}
+		}
+	}
+	return events, nil
+}
+
+// readABI reads an ABI file, or fetches the contract's verified ABI from
+// the explorer when the path is config.ABIExplorer. Fetched ABIs are
+// cached per chain ID under explorer.cache_dir.
+//
+// Parameters:
+//   - ctx (context.Context): context for cancellation
+//   - cfg (*config.Config): configuration holding the explorer settings
+//   - address (string): contract address
+//   - path (string): ABI file path or config.ABIExplorer
+//
+// Returns:
+//   - string: the ABI JSON
+//   - error: nil on success, read or fetch error on failure
+func readABI(ctx context.Context, cfg *config.Config, address, path string) (string, error) {
+	if path != config.ABIExplorer {
+		abiJSON, err := os.ReadFile(path) //nolint:gosec // G304: Path is validated from config
+		return string(abiJSON), err
+	}
+
+	cacheDir := cfg.Explorer.CacheDir
+	if cacheDir != "" {
+		cacheDir = filepath.Join(cacheDir, strconv.FormatUint(cfg.ChainID, 10))
+	}
+	return decoder.FetchABICached(ctx, cacheDir, cfg.Explorer.APIURL, cfg.Explorer.APIKey, common.HexToAddress(address))
+}
+
+// logCollisions reports event signatures shared by multiple contracts.
+func logCollisions(dec *decoder.Decoder) {
+	for _, c := range dec.Collisions() {
+		log.Info().
+			Str("event", c.EventName).
+			Str("signature", c.Signature.Hex()).
+			Strs("contracts", c.Contracts).
+			Msg("event signature shared by multiple contracts (decoded by address)")
+	}
+}
+
 // Close shuts down the engine.
 //
 // Returns:
 //   - error: nil on success, close error on failure
 func (e *Engine) Close() error {
+	e.uninstallLogFilter()
+	e.closeListeners()
+	if e.checkpoints != nil {
+		if err := e.checkpoints.Close(); err != nil {
+			log.Warn().Err(err).Msg("closing checkpoint mirror")
+		}
+	}
 	e.rpc.Close()
 	return e.store.Close()
 }
@@ -507,20 +1611,105 @@ func (e *Engine) Close() error {
 func convertEventData(data map[string]interface{}) map[string]any {
 	result := make(map[string]any, len(data))
 	for k, v := range data {
-		switch val := v.(type) {
-		case common.Address:
-			result[k] = val.Hex()
-		case *big.Int:
-			if val != nil {
-				result[k] = val.String()
-			} else {
-				result[k] = "0"
-			}
-		case []byte:
-			result[k] = common.Bytes2Hex(val)
-		default:
-			result[k] = v
-		}
+		result[k] = convertEventValue(v)
+	}
+	return result
+}
+
+// storedEventData returns decoded event data in the form stored in
+// events.data: each value in its own JSON encoding, except that array
+// elements (uint256[], address[], bytes32[], ...) are strings, so large
+// numbers keep their precision. Scalars keep the encoding rows have
+// always been stored with.
+func storedEventData(data map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		result[k] = storedEventValue(v)
 	}
 	return result
 }
+
+// storedEventValue converts the arrays within a decoded value for
+// storedEventData, recursing into tuples.
+func storedEventValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		return storedEventData(val)
+	case []byte:
+		return val
+	}
+
+	rv := reflect.ValueOf(v)
+	if (rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array) || rv.Type().Elem().Kind() == reflect.Uint8 {
+		return v
+	}
+	items := make([]interface{}, rv.Len())
+	for i := range items {
+		items[i] = storedArrayElement(rv.Index(i).Interface())
+	}
+	return items
+}
+
+// storedArrayElement converts an array element to its string form:
+// numbers in decimal, addresses lowercased as JSON encodes them, and
+// bytes as 0x-prefixed hex.
+func storedArrayElement(v interface{}) interface{} {
+	switch val := v.(type) {
+	case *big.Int:
+		if val != nil {
+			return val.String()
+		}
+		return "0"
+	case common.Address:
+		return strings.ToLower(val.Hex())
+	case []byte:
+		return hexutil.Encode(val)
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Array && rv.Type().Elem().Kind() == reflect.Uint8 {
+		raw := make([]byte, rv.Len())
+		reflect.Copy(reflect.ValueOf(raw), rv)
+		return hexutil.Encode(raw)
+	}
+	return storedEventValue(v)
+}
+
+// convertEventValue converts a single decoded value to its JSON-friendly form.
+// Slices and arrays (e.g. uint256[], address[]) are converted element-wise so
+// they serialize as JSON arrays of string values, and tuples (decoded as
+// nested maps) field by field. Fixed-size byte arrays (bytes1..bytes32) are
+// hex-encoded like dynamic bytes.
+func convertEventValue(v interface{}) any {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		return convertEventData(val)
+	case common.Address:
+		return val.Hex()
+	case *big.Int:
+		if val != nil {
+			return val.String()
+		}
+		return "0"
+	case []byte:
+		return common.Bytes2Hex(val)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			raw := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(raw), rv)
+			return common.Bytes2Hex(raw)
+		}
+
+		items := make([]any, rv.Len())
+		for i := range items {
+			items[i] = convertEventValue(rv.Index(i).Interface())
+		}
+		return items
+	default:
+		return v
+	}
+}
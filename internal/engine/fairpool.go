@@ -0,0 +1,161 @@
+package engine
+
+import (
+	"sync"
+)
+
+// fairPool is a handlerPool that keeps the per-key worker affinity and
+// ordering of keyedPool but lets each worker pick its next task
+// round-robin across classes instead of in submission order, so a flood
+// of one event type can't hold back the others queued on the same worker.
+// With a class cap, at most that many workers run one class at a time.
+type fairPool struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	queues  []*fairQueue
+	running map[string]int // tasks running per class, across workers
+	limit   int            // max running tasks per class (0 = no limit)
+	closed  bool
+	failed  bool
+	err     error
+	wg      sync.WaitGroup
+}
+
+// fairQueue holds one worker's pending tasks. Tasks are queued per key,
+// and a key waits in the ready list of its head task's class.
+type fairQueue struct {
+	keys    map[string][]keyedTask
+	ready   map[string][]string // class -> keys whose head task has that class
+	classes []string            // round-robin order (first seen first)
+	next    int                 // index into classes of the next class to serve
+	pending int
+}
+
+// newFairPool starts a fair pool with n workers.
+//
+// Parameters:
+//   - n (int): number of workers
+//   - maxShare (float64): fraction of the workers one class may occupy (0 = no limit)
+//
+// Returns:
+//   - *fairPool: the started pool
+func newFairPool(n int, maxShare float64) *fairPool {
+	p := &fairPool{
+		queues:  make([]*fairQueue, n),
+		running: make(map[string]int),
+		limit:   classLimit(n, maxShare),
+	}
+	p.cond = sync.NewCond(&p.mu)
+	for i := range p.queues {
+		p.queues[i] = &fairQueue{keys: make(map[string][]keyedTask), ready: make(map[string][]string)}
+		p.wg.Add(1)
+		go p.work(i)
+	}
+	return p
+}
+
+// classLimit converts a worker share to a per-class running limit, never
+// below one worker (0 = no limit).
+func classLimit(workers int, maxShare float64) int {
+	if maxShare <= 0 || maxShare >= 1 {
+		return 0
+	}
+	return max(int(float64(workers)*maxShare), 1)
+}
+
+// submit queues a task on the worker owning key.
+func (p *fairPool) submit(key, class string, task func(worker int) error) {
+	handlerQueueDepth.WithLabelValues(class).Inc()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	q := p.queues[workerFor(key, len(p.queues))]
+	if _, seen := q.ready[class]; !seen {
+		q.ready[class] = nil
+		q.classes = append(q.classes, class)
+	}
+	if len(q.keys[key]) == 0 {
+		q.ready[class] = append(q.ready[class], key)
+	}
+	q.keys[key] = append(q.keys[key], keyedTask{class: class, run: task})
+	q.pending++
+	p.cond.Broadcast()
+}
+
+// take removes the next task for a worker: the head task of the first
+// ready key of the next class, in round-robin order, that is under the
+// class limit. Must be called with p.mu held.
+func (p *fairPool) take(worker int) (keyedTask, bool) {
+	q := p.queues[worker]
+	for i := range q.classes {
+		idx := (q.next + i) % len(q.classes)
+		class := q.classes[idx]
+		keys := q.ready[class]
+		if len(keys) == 0 || (p.limit > 0 && p.running[class] >= p.limit) {
+			continue
+		}
+
+		key := keys[0]
+		q.ready[class] = keys[1:]
+		tasks := q.keys[key]
+		task := tasks[0]
+		if rest := tasks[1:]; len(rest) > 0 {
+			// The worker runs tasks one at a time, so the key's next task
+			// can't start before this one finishes
+			q.keys[key] = rest
+			q.ready[rest[0].class] = append(q.ready[rest[0].class], key)
+		} else {
+			delete(q.keys, key)
+		}
+		q.pending--
+		q.next = idx + 1
+		return task, true
+	}
+	return keyedTask{}, false
+}
+
+// work runs one worker's tasks until the pool is closed and its queue is empty.
+func (p *fairPool) work(worker int) {
+	defer p.wg.Done()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for {
+		task, ok := p.take(worker)
+		if !ok {
+			if p.closed && p.queues[worker].pending == 0 {
+				return
+			}
+			p.cond.Wait()
+			continue
+		}
+
+		handlerQueueDepth.WithLabelValues(task.class).Dec()
+		if p.failed {
+			continue
+		}
+
+		p.running[task.class]++
+		p.mu.Unlock()
+		err := task.run(worker)
+		p.mu.Lock()
+		p.running[task.class]--
+
+		if err != nil && !p.failed {
+			p.failed, p.err = true, err
+		}
+		p.cond.Broadcast()
+	}
+}
+
+// wait stops accepting tasks, waits for queued ones and returns the first error.
+func (p *fairPool) wait() error {
+	p.mu.Lock()
+	p.closed = true
+	p.cond.Broadcast()
+	p.mu.Unlock()
+
+	p.wg.Wait()
+	return p.err
+}
@@ -0,0 +1,225 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/0xredeth/Rafale/internal/rpc"
+)
+
+var logFilterInstalls = promauto.NewCounter(
+	prometheus.CounterOpts{
+		Name: "rafale_log_filter_installs_total",
+		Help: "Total number of log filters installed (sync.filter_mode), including re-installs after expiry",
+	},
+)
+
+// logFilter is the state of the log filter followed in sync.filter_mode.
+// A filter only reports logs of blocks mined after it was installed, so
+// blocks up to the head seen at installation are fetched with getLogs.
+type logFilter struct {
+	id       string
+	key      string      // addresses and topics the filter matches
+	backfill uint64      // last block fetched with getLogs rather than the filter
+	next     uint64      // first block of the next batch
+	pending  []types.Log // filter logs of blocks not yet in a batch
+}
+
+// fetchFilterLogs fetches logs for a sync batch in filter mode. The part
+// of the range up to the filter's backfill block is fetched with getLogs,
+// the rest comes from polling the filter. The filter is (re)installed when
+// missing, dropped by the node, matching other contracts, or when the
+// batch doesn't follow the previous one (after a rollback). The returned
+// commit function consumes the batch's filter logs; until it runs, the
+// same range can be fetched again.
+//
+// Parameters:
+//   - ctx (context.Context): context for cancellation
+//   - fc (rpc.FilterClient): filter-capable client
+//   - fromBlock (uint64): first block of the batch
+//   - toBlock (uint64): last block of the batch
+//
+// Returns:
+//   - []types.Log: logs ordered by block and log index
+//   - func(): consumes the batch's filter logs
+//   - error: nil on success, RPC error on failure
+func (e *Engine) fetchFilterLogs(ctx context.Context, fc rpc.FilterClient, fromBlock, toBlock uint64) ([]types.Log, func(), error) {
+	e.mu.RLock()
+	addresses := e.decoder.GetAddresses()
+	topics := [][]common.Hash{e.decoder.GetEventSignatures()}
+	e.mu.RUnlock()
+	key := filterKey(addresses, topics)
+
+	e.filterMu.Lock()
+	defer e.filterMu.Unlock()
+
+	if f := e.filter; f != nil && (f.key != key || f.next != fromBlock) {
+		e.dropLogFilter(ctx, fc)
+	}
+
+	var changes []types.Log
+	for attempt := 0; ; attempt++ {
+		if e.filter == nil {
+			if err := e.installLogFilter(ctx, fc, addresses, topics, key, fromBlock); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		var err error
+		changes, err = fc.LogFilterChanges(ctx, e.filter.id)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, rpc.ErrFilterNotFound) || attempt > 0 {
+			return nil, nil, fmt.Errorf("polling log filter: %w", err)
+		}
+
+		// Re-installing sets the backfill block past any gap
+		log.Warn().Str("filter", e.filter.id).Msg("log filter expired, re-installing")
+		e.filter = nil
+	}
+
+	f := e.filter
+	f.pending = appendFilterLogs(f.pending, changes, f.backfill)
+
+	var logs []types.Log
+	if fromBlock <= f.backfill {
+		fetched, err := e.fetchBlockRangeLogs(ctx, fromBlock, min(toBlock, f.backfill))
+		if err != nil {
+			return nil, nil, err
+		}
+		logs = fetched
+	}
+
+	var rest []types.Log
+	for _, l := range f.pending {
+		if l.BlockNumber > toBlock {
+			rest = append(rest, l)
+		} else if l.BlockNumber >= fromBlock {
+			logs = append(logs, l)
+		}
+	}
+	sort.SliceStable(logs, func(i, j int) bool {
+		if logs[i].BlockNumber != logs[j].BlockNumber {
+			return logs[i].BlockNumber < logs[j].BlockNumber
+		}
+		return logs[i].Index < logs[j].Index
+	})
+
+	commit := func() {
+		e.filterMu.Lock()
+		defer e.filterMu.Unlock()
+		if e.filter == f {
+			f.pending = rest
+			f.next = toBlock + 1
+		}
+	}
+	return logs, commit, nil
+}
+
+// installLogFilter installs a filter and records the head seen after
+// installation as its backfill block: later blocks are reported by the
+// filter, earlier ones must be fetched with getLogs. Must be called with
+// e.filterMu held.
+func (e *Engine) installLogFilter(ctx context.Context, fc rpc.FilterClient, addresses []common.Address, topics [][]common.Hash, key string, next uint64) error {
+	id, err := fc.NewLogFilter(ctx, addresses, topics)
+	if err != nil {
+		return fmt.Errorf("installing log filter: %w", err)
+	}
+
+	head, err := e.rpc.BlockNumber(ctx)
+	if err != nil {
+		if uerr := fc.UninstallFilter(ctx, id); uerr != nil {
+			log.Debug().Err(uerr).Str("filter", id).Msg("uninstalling log filter")
+		}
+		return fmt.Errorf("getting block number for log filter: %w", err)
+	}
+
+	e.filter = &logFilter{id: id, key: key, backfill: head, next: next}
+	logFilterInstalls.Inc()
+	log.Info().
+		Str("filter", id).
+		Uint64("backfillTo", head).
+		Msg("installed log filter")
+	return nil
+}
+
+// dropLogFilter uninstalls the current filter, best effort. Must be called
+// with e.filterMu held.
+func (e *Engine) dropLogFilter(ctx context.Context, fc rpc.FilterClient) {
+	if e.filter == nil {
+		return
+	}
+	if err := fc.UninstallFilter(ctx, e.filter.id); err != nil {
+		log.Debug().Err(err).Str("filter", e.filter.id).Msg("uninstalling log filter")
+	}
+	e.filter = nil
+}
+
+// uninstallLogFilter removes the installed filter on shutdown so it
+// doesn't linger on the node until it expires.
+func (e *Engine) uninstallLogFilter() {
+	fc, ok := e.rpc.(rpc.FilterClient)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	e.filterMu.Lock()
+	defer e.filterMu.Unlock()
+	e.dropLogFilter(ctx, fc)
+}
+
+// appendFilterLogs appends polled filter logs to pending, skipping logs of
+// blocks already covered by the getLogs backfill. A log removed by a reorg
+// also drops its earlier copy still pending, so a log held back past the
+// batch isn't indexed from a block that is no longer canonical; a removed
+// log already indexed is rolled back by the reorg check.
+func appendFilterLogs(pending, changes []types.Log, backfill uint64) []types.Log {
+	for _, l := range changes {
+		if l.Removed {
+			pending = slices.DeleteFunc(pending, func(p types.Log) bool {
+				return p.BlockHash == l.BlockHash && p.TxHash == l.TxHash && p.Index == l.Index
+			})
+			continue
+		}
+		if l.BlockNumber <= backfill {
+			continue
+		}
+		pending = append(pending, l)
+	}
+	return pending
+}
+
+// filterKey identifies the addresses and topics a filter matches,
+// independently of their order.
+func filterKey(addresses []common.Address, topics [][]common.Hash) string {
+	parts := make([]string, 0, len(addresses))
+	for _, addr := range addresses {
+		parts = append(parts, addr.Hex())
+	}
+	sort.Strings(parts)
+	key := strings.Join(parts, ",")
+
+	for _, set := range topics {
+		parts = parts[:0]
+		for _, topic := range set {
+			parts = append(parts, topic.Hex())
+		}
+		sort.Strings(parts)
+		key += "|" + strings.Join(parts, ",")
+	}
+	return key
+}
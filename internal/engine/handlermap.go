@@ -0,0 +1,94 @@
+package engine
+
+import (
+	"strings"
+
+	"github.com/0xredeth/Rafale/pkg/handler"
+)
+
+// HandlerMap returns the effective dispatch of decoded events: every event
+// ID the decoder produces, mapped to the ID of the typed handler it runs,
+// or "" when it is only stored in the generic events table. An event runs
+// the handler of its exact ID, else its contract wildcard ("usdc:*"), else
+// "*"; an event without a handler here never reaches one.
+//
+// Returns:
+//   - map[string]string: event ID -> handler ID ("" = no handler)
+func (e *Engine) HandlerMap() map[string]string {
+	e.mu.RLock()
+	ids := e.decoder.EventIDs()
+	e.mu.RUnlock()
+
+	bindings := make(map[string]string, len(ids))
+	for _, id := range ids {
+		bindings[id], _ = e.handlers.Match(id)
+	}
+	return bindings
+}
+
+// UnboundHandlers returns the handlers registered under an event ID the
+// decoder never produces, so they never run. Each is mapped to the decoded
+// event ID it matches case-insensitively, the usual cause (config contract
+// names are lowercased), or "" if none.
+//
+// names are lowercased), or "" if none. A contract wildcard is unbound
+// when no decoded event belongs to its contract; "*" always matches.
+//
+// Returns:
+//   - map[string]string: handler ID -> near-miss event ID ("" if none)
+func (e *Engine) UnboundHandlers() map[string]string {
+	e.mu.RLock()
+	ids := e.decoder.EventIDs()
+	e.mu.RUnlock()
+
+	decoded := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		decoded[id] = true
+	}
+
+	unbound := make(map[string]string)
+	for _, handlerID := range e.handlers.ListHandlers() {
+		if handlerID == handler.Wildcard || decoded[handlerID] {
+			continue
+		}
+		if contract, ok := strings.CutSuffix(handlerID, ":"+handler.Wildcard); ok {
+			if nearMiss, bound := contractEvent(ids, contract); !bound {
+				unbound[handlerID] = nearMiss
+			}
+			continue
+		}
+
+		unbound[handlerID] = ""
+		for _, id := range ids {
+			if strings.EqualFold(id, handlerID) {
+				unbound[handlerID] = id
+				break
+			}
+		}
+	}
+	return unbound
+}
+
+// contractEvent looks for a decoded event of a contract, for a contract
+// wildcard handler.
+//
+// Parameters:
+//   - ids ([]string): decoded event IDs
+//   - contract (string): contract name of the wildcard
+//
+// Returns:
+//   - string: an event of the contract matched case-insensitively ("" if none)
+//   - bool: true if an event of the contract is decoded
+func contractEvent(ids []string, contract string) (string, bool) {
+	nearMiss := ""
+	for _, id := range ids {
+		idContract, _, _ := strings.Cut(id, ":")
+		if idContract == contract {
+			return "", true
+		}
+		if nearMiss == "" && strings.EqualFold(idContract, contract) {
+			nearMiss = id
+		}
+	}
+	return nearMiss, false
+}
@@ -0,0 +1,74 @@
+package engine
+
+import (
+	"context"
+	"time"
+)
+
+// healthTimeout bounds the RPC and database pings of a health check.
+const healthTimeout = 5 * time.Second
+
+// HealthStatus is the engine's health, as reported by /healthz and /readyz.
+type HealthStatus struct {
+	LastBlock    uint64 `json:"lastBlock"`
+	HeadBlock    uint64 `json:"headBlock"`
+	SyncLag      uint64 `json:"syncLag"` // blocks behind the confirmed head (see sync.confirmations)
+	RPCConnected bool   `json:"rpcConnected"`
+	DBConnected  bool   `json:"dbConnected"`
+}
+
+// Connected reports whether the RPC node and the database answer. Sync lag
+// is left out: a node backfilling far behind the head is still healthy.
+//
+// Returns:
+//   - bool: true if both answer
+func (h HealthStatus) Connected() bool {
+	return h.RPCConnected && h.DBConnected
+}
+
+// Healthy reports whether the engine is connected and within maxLag
+// blocks of the head, i.e. ready to serve current data.
+//
+// Parameters:
+//   - maxLag (uint64): highest acceptable SyncLag
+//
+// Returns:
+//   - bool: true if healthy
+func (h HealthStatus) Healthy(maxLag uint64) bool {
+	return h.Connected() && h.SyncLag <= maxLag
+}
+
+// Health checks the RPC node (eth_blockNumber) and the database (SELECT 1)
+// and reports how far sync is behind the head. Without an RPC answer the
+// head is unknown, and so is the lag (reported as 0).
+//
+// Returns:
+//   - HealthStatus: current health
+func (e *Engine) Health() HealthStatus {
+	ctx, cancel := context.WithTimeout(context.Background(), healthTimeout)
+	defer cancel()
+
+	e.mu.RLock()
+	status := HealthStatus{LastBlock: e.lastBlock}
+	confirmations := e.cfg.Sync.Confirmations
+	e.mu.RUnlock()
+
+	head, err := e.rpc.BlockNumber(ctx)
+	if err != nil {
+		log.Debug().Err(err).Msg("health check: RPC unreachable")
+	} else {
+		status.RPCConnected = true
+		status.HeadBlock = head
+		if confirmed := confirmedHead(head, confirmations); confirmed > status.LastBlock {
+			status.SyncLag = confirmed - status.LastBlock
+		}
+	}
+
+	if err := e.store.Ping(ctx); err != nil {
+		log.Debug().Err(err).Msg("health check: database unreachable")
+	} else {
+		status.DBConnected = true
+	}
+
+	return status
+}
@@ -0,0 +1,200 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Job types.
+const (
+	JobTypeReindex            = "reindex"
+	JobTypeRedecode           = "redecode"
+	JobTypeBackfillTimestamps = "backfill_timestamps"
+)
+
+// Job states.
+const (
+	JobStateRunning   = "running"
+	JobStateCompleted = "completed"
+	JobStateFailed    = "failed"
+	JobStateCancelled = "cancelled"
+)
+
+// maxFinishedJobs is the number of finished jobs kept for inspection.
+const maxFinishedJobs = 20
+
+// ErrJobNotFound is returned when cancelling an unknown or finished job.
+var ErrJobNotFound = errors.New("job not found or not running")
+
+// JobStatus describes a long-running engine operation.
+type JobStatus struct {
+	ID           string     `json:"id"`
+	Type         string     `json:"type"`
+	State        string     `json:"state"`
+	FromBlock    uint64     `json:"fromBlock"`
+	ToBlock      uint64     `json:"toBlock"`
+	CurrentBlock uint64     `json:"currentBlock"` // last completed block
+	StartedAt    time.Time  `json:"startedAt"`
+	FinishedAt   *time.Time `json:"finishedAt,omitempty"`
+	Error        string     `json:"error,omitempty"`
+}
+
+// job is a tracked operation and its cancel function.
+type job struct {
+	seq    uint64
+	status JobStatus
+	cancel context.CancelFunc
+}
+
+// Jobs returns running and recently finished jobs, newest first.
+//
+// Returns:
+//   - []JobStatus: job snapshots
+func (e *Engine) Jobs() []JobStatus {
+	e.jobsMu.Lock()
+	defer e.jobsMu.Unlock()
+
+	ordered := make([]*job, 0, len(e.jobs))
+	for _, j := range e.jobs {
+		ordered = append(ordered, j)
+	}
+	sort.Slice(ordered, func(i, k int) bool {
+		return ordered[i].seq > ordered[k].seq
+	})
+
+	jobs := make([]JobStatus, len(ordered))
+	for i, j := range ordered {
+		jobs[i] = j.status
+	}
+
+	return jobs
+}
+
+// CancelJob stops a running job. The in-flight chunk is rolled back and the
+// job is marked cancelled.
+//
+// Parameters:
+//   - id (string): job ID
+//
+// Returns:
+//   - error: nil on success, ErrJobNotFound if unknown or not running
+func (e *Engine) CancelJob(id string) error {
+	e.jobsMu.Lock()
+	defer e.jobsMu.Unlock()
+
+	j, ok := e.jobs[id]
+	if !ok || j.status.State != JobStateRunning {
+		return fmt.Errorf("%s: %w", id, ErrJobNotFound)
+	}
+
+	j.cancel()
+	return nil
+}
+
+// startJob registers a running job and returns a cancellable context for it.
+func (e *Engine) startJob(ctx context.Context, jobType string, fromBlock, toBlock uint64) (context.Context, string) {
+	e.jobsMu.Lock()
+	defer e.jobsMu.Unlock()
+
+	return e.addJob(ctx, jobType, fromBlock, toBlock)
+}
+
+// startExclusiveJob claims the reindexing slot and registers the job in
+// one step under jobsMu, so of two concurrent requests exactly one starts.
+// The caller releases the slot with e.reindexing.Store(false) once done.
+//
+// Returns:
+//   - context.Context: the job context
+//   - string: the job ID
+//   - error: nil on success, ErrReindexInProgress if the slot is taken
+func (e *Engine) startExclusiveJob(ctx context.Context, jobType string, fromBlock, toBlock uint64) (context.Context, string, error) {
+	e.jobsMu.Lock()
+	defer e.jobsMu.Unlock()
+
+	if !e.reindexing.CompareAndSwap(false, true) {
+		return nil, "", ErrReindexInProgress
+	}
+	jobCtx, id := e.addJob(ctx, jobType, fromBlock, toBlock)
+	return jobCtx, id, nil
+}
+
+// addJob registers a running job. Callers hold e.jobsMu.
+func (e *Engine) addJob(ctx context.Context, jobType string, fromBlock, toBlock uint64) (context.Context, string) {
+	jobCtx, cancel := context.WithCancel(ctx)
+
+	if e.jobs == nil {
+		e.jobs = make(map[string]*job)
+	}
+
+	e.jobSeq++
+	id := fmt.Sprintf("%s-%d", jobType, e.jobSeq)
+	e.jobs[id] = &job{
+		seq: e.jobSeq,
+		status: JobStatus{
+			ID:        id,
+			Type:      jobType,
+			State:     JobStateRunning,
+			FromBlock: fromBlock,
+			ToBlock:   toBlock,
+			StartedAt: time.Now(),
+		},
+		cancel: cancel,
+	}
+
+	return jobCtx, id
+}
+
+// updateJobProgress records the last completed block of a job.
+func (e *Engine) updateJobProgress(id string, block uint64) {
+	e.jobsMu.Lock()
+	defer e.jobsMu.Unlock()
+
+	if j, ok := e.jobs[id]; ok {
+		j.status.CurrentBlock = block
+	}
+}
+
+// finishJob marks a job done and prunes old finished jobs.
+func (e *Engine) finishJob(id string, err error) {
+	e.jobsMu.Lock()
+	defer e.jobsMu.Unlock()
+
+	j, ok := e.jobs[id]
+	if !ok {
+		return
+	}
+	j.cancel()
+
+	now := time.Now()
+	j.status.FinishedAt = &now
+	switch {
+	case err == nil:
+		j.status.State = JobStateCompleted
+	case errors.Is(err, context.Canceled):
+		j.status.State = JobStateCancelled
+		j.status.Error = err.Error()
+	default:
+		j.status.State = JobStateFailed
+		j.status.Error = err.Error()
+	}
+
+	// Prune the oldest finished jobs beyond the retention cap
+	finished := make([]*job, 0, len(e.jobs))
+	for _, other := range e.jobs {
+		if other.status.State != JobStateRunning {
+			finished = append(finished, other)
+		}
+	}
+	if len(finished) <= maxFinishedJobs {
+		return
+	}
+	sort.Slice(finished, func(i, k int) bool {
+		return finished[i].seq < finished[k].seq
+	})
+	for _, old := range finished[:len(finished)-maxFinishedJobs] {
+		delete(e.jobs, old.status.ID)
+	}
+}
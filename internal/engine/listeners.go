@@ -0,0 +1,133 @@
+package engine
+
+import (
+	"maps"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/0xredeth/Rafale/pkg/decoder"
+)
+
+// listenerBuffer is how many events a listener may lag behind before
+// events are dropped for it, as for broadcaster subscriptions.
+const listenerBuffer = 100
+
+var listenerDrops = promauto.NewCounter(
+	prometheus.CounterOpts{
+		Name: "rafale_event_listener_drops_total",
+		Help: "Total number of events dropped for in-process listeners whose buffer was full",
+	},
+)
+
+// EventFilter selects the events delivered to an OnEvent callback. Empty
+// fields match everything.
+type EventFilter struct {
+	// Contract matches the contract name.
+	Contract string
+
+	// EventName matches the stored event name.
+	EventName string
+
+	// Match, if set, must also return true (e.g. value above a threshold).
+	// It runs on the sync path and must be fast.
+	Match func(event *decoder.DecodedEvent) bool
+}
+
+// matches reports whether event passes the filter.
+func (f EventFilter) matches(event *decoder.DecodedEvent) bool {
+	if f.Contract != "" && f.Contract != event.ContractName {
+		return false
+	}
+	if f.EventName != "" && f.EventName != event.EventName {
+		return false
+	}
+	return f.Match == nil || f.Match(event)
+}
+
+// eventListener is a registered OnEvent callback fed through a buffered
+// channel, so a slow callback never blocks the sync loop.
+type eventListener struct {
+	filter EventFilter
+	ch     chan *decoder.DecodedEvent
+}
+
+// OnEvent registers an in-process callback for decoded events matching
+// filter, for applications embedding the engine as a library. Events are
+// delivered in order on a dedicated goroutine, as they are stored: like
+// broadcaster subscriptions, they precede the batch commit, and are
+// dropped (with a warning) if the callback falls too far behind. The
+// callback receives its own copy of the event.
+//
+// Parameters:
+//   - filter (EventFilter): events to deliver
+//   - fn (func(*decoder.DecodedEvent)): callback
+//
+// Returns:
+//   - func(): unsubscribes; safe to call more than once
+func (e *Engine) OnEvent(filter EventFilter, fn func(*decoder.DecodedEvent)) (unsubscribe func()) {
+	l := &eventListener{filter: filter, ch: make(chan *decoder.DecodedEvent, listenerBuffer)}
+
+	e.listenersMu.Lock()
+	if e.listeners == nil {
+		e.listeners = make(map[uint64]*eventListener)
+	}
+	e.listenerSeq++
+	id := e.listenerSeq
+	e.listeners[id] = l
+	e.listenersMu.Unlock()
+
+	go func() {
+		for event := range l.ch {
+			fn(event)
+		}
+	}()
+
+	return func() {
+		e.listenersMu.Lock()
+		defer e.listenersMu.Unlock()
+
+		if _, ok := e.listeners[id]; ok {
+			delete(e.listeners, id)
+			close(l.ch)
+		}
+	}
+}
+
+// notifyListeners hands event to every listener whose filter matches it,
+// without blocking.
+func (e *Engine) notifyListeners(event *decoder.DecodedEvent) {
+	e.listenersMu.RLock()
+	defer e.listenersMu.RUnlock()
+
+	for id, l := range e.listeners {
+		if !l.filter.matches(event) {
+			continue
+		}
+
+		// Copy so callbacks can't race with handlers on Data
+		c := *event
+		c.Data = maps.Clone(event.Data)
+
+		select {
+		case l.ch <- &c:
+		default:
+			listenerDrops.Inc()
+			log.Warn().
+				Uint64("listenerID", id).
+				Str("eventName", event.EventName).
+				Msg("event listener buffer full, dropping event")
+		}
+	}
+}
+
+// closeListeners unsubscribes every listener, ending their goroutines.
+func (e *Engine) closeListeners() {
+	e.listenersMu.Lock()
+	defer e.listenersMu.Unlock()
+
+	for id, l := range e.listeners {
+		close(l.ch)
+		delete(e.listeners, id)
+	}
+}
@@ -0,0 +1,6 @@
+package engine
+
+import "github.com/0xredeth/Rafale/internal/logging"
+
+// log is the engine subsystem's logger (level set by log.levels.engine).
+var log = logging.New("engine")
@@ -0,0 +1,193 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"time"
+
+	"github.com/0xredeth/Rafale/internal/store"
+)
+
+// ManifestVersion identifies the row canonicalization used for the digest.
+// Bump it if canonicalRow changes so old manifests are not misverified.
+const ManifestVersion = 1
+
+// ErrManifestMismatch is returned when a manifest does not match the store.
+var ErrManifestMismatch = errors.New("manifest does not match indexed data")
+
+// Manifest describes an export of indexed data for a block range.
+type Manifest struct {
+	Version     int              `json:"version"`
+	FromBlock   uint64           `json:"fromBlock"`
+	ToBlock     uint64           `json:"toBlock"`
+	RowCounts   map[string]int64 `json:"rowCounts"` // per table ("events", "transfers")
+	SHA256      string           `json:"sha256"`    // hex digest of canonicalized rows
+	GeneratedAt time.Time        `json:"generatedAt"`
+}
+
+// canonicalRow is the hashed representation of a row. Database-local
+// fields (row IDs, insert times) are excluded so independently indexed
+// copies of the same chain data hash identically.
+type canonicalRow struct {
+	Type         string          `json:"type"`
+	BlockNumber  uint64          `json:"blockNumber"`
+	TxHash       string          `json:"txHash"`
+	TxIndex      uint            `json:"txIndex"`
+	LogIndex     uint            `json:"logIndex"`
+	Timestamp    int64           `json:"timestamp"`
+	ContractName string          `json:"contractName"`
+	EventName    string          `json:"eventName"`
+	Data         json.RawMessage `json:"data"`
+}
+
+// manifestDigest accumulates row counts and the SHA-256 over canonical rows.
+type manifestDigest struct {
+	h      hash.Hash
+	counts map[string]int64
+}
+
+// newManifestDigest creates an empty digest.
+func newManifestDigest() *manifestDigest {
+	return &manifestDigest{
+		h: sha256.New(),
+		counts: map[string]int64{
+			"events":    0,
+			"transfers": 0,
+		},
+	}
+}
+
+// add hashes one row. Rows must be added in canonical stream order.
+func (d *manifestDigest) add(ev store.UnifiedEvent) error {
+	data, err := canonicalJSON(ev.Data)
+	if err != nil {
+		return fmt.Errorf("canonicalizing data at block %d log %d: %w", ev.BlockNumber, ev.LogIndex, err)
+	}
+
+	line, err := json.Marshal(canonicalRow{
+		Type:         ev.Type,
+		BlockNumber:  ev.BlockNumber,
+		TxHash:       ev.TxHash,
+		TxIndex:      ev.TxIndex,
+		LogIndex:     ev.LogIndex,
+		Timestamp:    ev.Timestamp.Unix(),
+		ContractName: ev.ContractName,
+		EventName:    ev.EventName,
+		Data:         data,
+	})
+	if err != nil {
+		return fmt.Errorf("marshaling row: %w", err)
+	}
+
+	d.h.Write(line)
+	d.h.Write([]byte{'\n'})
+
+	switch ev.Type {
+	case store.UnifiedTypeTransfer:
+		d.counts["transfers"]++
+	default:
+		d.counts["events"]++
+	}
+
+	return nil
+}
+
+// sum returns the hex digest.
+func (d *manifestDigest) sum() string {
+	return hex.EncodeToString(d.h.Sum(nil))
+}
+
+// canonicalJSON re-encodes JSON with sorted object keys and no insignificant
+// whitespace, so JSONB storage order does not affect the digest.
+func canonicalJSON(raw []byte) (json.RawMessage, error) {
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return json.RawMessage("null"), nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	// encoding/json sorts map keys
+	out, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ExportManifest computes a deterministic manifest for an inclusive block
+// range: per-table row counts and a SHA-256 over rows canonicalized and
+// streamed in (block_number, tx_index, log_index) order.
+//
+// Parameters:
+//   - ctx (context.Context): context for cancellation
+//   - fromBlock (uint64): first block (inclusive)
+//   - toBlock (uint64): last block (inclusive)
+//
+// Returns:
+//   - Manifest: computed manifest
+//   - error: nil on success, error on failure
+func (e *Engine) ExportManifest(ctx context.Context, fromBlock, toBlock uint64) (Manifest, error) {
+	if fromBlock > toBlock {
+		return Manifest{}, fmt.Errorf("%w: %d-%d", ErrInvalidRange, fromBlock, toBlock)
+	}
+
+	digest := newManifestDigest()
+	if err := e.store.StreamAllEvents(ctx, fromBlock, toBlock, digest.add); err != nil {
+		return Manifest{}, fmt.Errorf("streaming events: %w", err)
+	}
+
+	return Manifest{
+		Version:     ManifestVersion,
+		FromBlock:   fromBlock,
+		ToBlock:     toBlock,
+		RowCounts:   digest.counts,
+		SHA256:      digest.sum(),
+		GeneratedAt: time.Now().UTC(),
+	}, nil
+}
+
+// VerifyManifest recomputes a manifest from the store and compares it.
+//
+// Parameters:
+//   - ctx (context.Context): context for cancellation
+//   - m (Manifest): manifest to verify
+//
+// Returns:
+//   - error: nil if it matches, ErrManifestMismatch if not, error on failure
+func (e *Engine) VerifyManifest(ctx context.Context, m Manifest) error {
+	if m.Version != ManifestVersion {
+		return fmt.Errorf("unsupported manifest version %d (want %d)", m.Version, ManifestVersion)
+	}
+
+	got, err := e.ExportManifest(ctx, m.FromBlock, m.ToBlock)
+	if err != nil {
+		return err
+	}
+
+	return compareManifests(m, got)
+}
+
+// compareManifests reports the first difference between two manifests.
+func compareManifests(want, got Manifest) error {
+	for table, n := range got.RowCounts {
+		if want.RowCounts[table] != n {
+			return fmt.Errorf("%w: %s has %d rows, manifest says %d", ErrManifestMismatch, table, n, want.RowCounts[table])
+		}
+	}
+	if want.SHA256 != got.SHA256 {
+		return fmt.Errorf("%w: sha256 %s, manifest says %s", ErrManifestMismatch, got.SHA256, want.SHA256)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package engine
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/0xredeth/Rafale/internal/store"
+)
+
+// fetchedBatch is a batch fetched from the RPC, waiting to be stored.
+type fetchedBatch struct {
+	from, to uint64
+	header   *types.Header // last block of the batch
+	window   []blockRef    // hashes of earlier blocks of the batch within the reorg window
+	logs     []types.Log
+	commit   func() // advances schedule cursors once stored
+	reverts  []store.RevertedTx
+	started  time.Time
+	err      error // fetch error, returned when the batch is committed
+}
+
+// syncRanges splits the blocks after lastBlock up to target into at most
+// count consecutive batches of at most batchSize blocks.
+//
+// Parameters:
+//   - lastBlock (uint64): last indexed block
+//   - target (uint64): last block to sync
+//   - batchSize (uint64): maximum blocks per batch
+//   - count (int): maximum number of batches
+//
+// Returns:
+//   - [][2]uint64: inclusive block ranges in ascending order
+func syncRanges(lastBlock, target, batchSize uint64, count int) [][2]uint64 {
+	var ranges [][2]uint64
+	for len(ranges) < max(count, 1) {
+		from, to, ok := batchRange(lastBlock, target, batchSize)
+		if !ok {
+			break
+		}
+		ranges = append(ranges, [2]uint64{from, to})
+		lastBlock = to
+	}
+	return ranges
+}
+
+// fetchConcurrency returns how many batches may be fetched at once:
+// sync.concurrency, or 1 while a contract has its own schedule or
+// filter_mode is on, since both track state across consecutive batches.
+func (e *Engine) fetchConcurrency() int {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if len(e.schedules) > 0 || e.cfg.Sync.FilterMode {
+		return 1
+	}
+	return max(e.cfg.Sync.Concurrency, 1)
+}
+
+// fetchBatches fetches batch ranges concurrently. Every request still goes
+// through the RPC client's retries and circuit breaker.
+//
+// Parameters:
+//   - ctx (context.Context): context for cancellation; cancel it to abandon pending fetches
+//   - ranges ([][2]uint64): inclusive block ranges
+//   - headBlock (uint64): effective chain head
+//
+// Returns:
+//   - []<-chan *fetchedBatch: one channel per range, in range order, each receiving its batch
+func (e *Engine) fetchBatches(ctx context.Context, ranges [][2]uint64, headBlock uint64) []<-chan *fetchedBatch {
+	results := make([]<-chan *fetchedBatch, len(ranges))
+	for i, r := range ranges {
+		result := make(chan *fetchedBatch, 1)
+		results[i] = result
+		go func() {
+			result <- e.fetchBatch(ctx, r[0], r[1], headBlock)
+		}()
+	}
+	return results
+}
@@ -0,0 +1,177 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"gorm.io/gorm"
+
+	"github.com/0xredeth/Rafale/internal/store"
+)
+
+// rawEventLog rebuilds the original log from an event's stored raw fields.
+//
+// Parameters:
+//   - ev (*store.Event): stored event with RawTopics set
+//
+// Returns:
+//   - types.Log: reconstructed log
+//   - error: nil on success, error if the raw topics are malformed
+func rawEventLog(ev *store.Event) (types.Log, error) {
+	var topics []common.Hash
+	if err := json.Unmarshal(ev.RawTopics, &topics); err != nil {
+		return types.Log{}, fmt.Errorf("parsing raw topics of event %d: %w", ev.ID, err)
+	}
+
+	return types.Log{
+		Address:     common.HexToAddress(ev.ContractAddr),
+		Topics:      topics,
+		Data:        ev.RawData,
+		BlockNumber: ev.BlockNumber,
+		TxHash:      common.HexToHash(ev.TxHash),
+		TxIndex:     ev.TxIndex,
+		Index:       ev.LogIndex,
+	}, nil
+}
+
+// RedecodeStored re-decodes stored raw logs in an inclusive block range
+// with the current decoder and rewrites their Data column in place. No RPC
+// calls are made; only events indexed with sync.store_raw_log are updated.
+// Runs as a tracked job and excludes concurrent reindexing.
+//
+// Parameters:
+//   - ctx (context.Context): context for cancellation
+//   - fromBlock (uint64): first block (inclusive)
+//   - toBlock (uint64): last block (inclusive)
+//
+// Returns:
+//   - error: nil on success, ErrInvalidRange, ErrReindexInProgress, or error on failure
+func (e *Engine) RedecodeStored(ctx context.Context, fromBlock, toBlock uint64) (err error) {
+	e.mu.RLock()
+	lastBlock := e.lastBlock
+	batchSize := e.cfg.Sync.BatchSize
+	e.mu.RUnlock()
+
+	if fromBlock > toBlock || toBlock > lastBlock {
+		return fmt.Errorf("%w: %d-%d (last indexed block %d)", ErrInvalidRange, fromBlock, toBlock, lastBlock)
+	}
+
+	ctx, jobID, err := e.startExclusiveJob(ctx, JobTypeRedecode, fromBlock, toBlock)
+	if err != nil {
+		return err
+	}
+	defer e.reindexing.Store(false)
+	defer func() { e.finishJob(jobID, err) }()
+
+	if batchSize == 0 {
+		batchSize = 1
+	}
+
+	log.Info().
+		Str("job", jobID).
+		Uint64("from", fromBlock).
+		Uint64("to", toBlock).
+		Msg("redecode started")
+
+	var updated, skipped int
+	for start := fromBlock; start <= toBlock; start += batchSize {
+		end := start + batchSize - 1
+		if end > toBlock || end < start {
+			end = toBlock
+		}
+
+		err = e.store.Transaction(ctx, func(tx *gorm.DB) error {
+			events, err := store.ListRawEventsTx(tx, start, end)
+			if err != nil {
+				return err
+			}
+
+			for i := range events {
+				ok, err := e.redecodeEvent(ctx, tx, &events[i])
+				if err != nil {
+					return err
+				}
+				if ok {
+					updated++
+				} else {
+					skipped++
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("redecoding blocks %d-%d: %w", start, end, err)
+		}
+		store.PurgeCachedBlocks(start, end)
+
+		e.updateJobProgress(jobID, end)
+
+		if end == toBlock {
+			break
+		}
+	}
+
+	log.Info().
+		Str("job", jobID).
+		Uint64("from", fromBlock).
+		Uint64("to", toBlock).
+		Int("updated", updated).
+		Int("skipped", skipped).
+		Msg("redecode complete")
+
+	return nil
+}
+
+// redecodeEvent re-decodes one stored event and updates its Data.
+// Events the current decoder no longer recognizes are left untouched.
+//
+// Returns:
+//   - bool: true if the event was updated
+//   - error: nil on success, error on failure
+func (e *Engine) redecodeEvent(ctx context.Context, tx *gorm.DB, ev *store.Event) (bool, error) {
+	logEntry, err := rawEventLog(ev)
+	if err != nil {
+		return false, err
+	}
+
+	e.mu.RLock()
+	decoded, err := e.decoder.Decode(logEntry)
+	e.mu.RUnlock()
+	if err != nil {
+		log.Debug().
+			Err(err).
+			Uint64("id", ev.ID).
+			Uint64("block", ev.BlockNumber).
+			Msg("skipping stored event the decoder no longer recognizes")
+		return false, nil
+	}
+
+	if err := e.applyTransforms(ctx, decoded); err != nil {
+		return false, err
+	}
+
+	// The schema policy applies as when indexing; a skipped event keeps its row
+	if skip, err := e.checkEventSchema(decoded); err != nil || skip {
+		if skip {
+			logsSkipped.WithLabelValues("schema_violation").Inc()
+		}
+		return false, err
+	}
+
+	data, skip, err := e.encodeEventData(logEntry, decoded)
+	if err != nil {
+		return false, err
+	}
+	if skip {
+		// The skip policy applies to new events; keep the existing row
+		return false, nil
+	}
+
+	if err := store.UpdateEventDataTx(tx, ev, data); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,268 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+
+	"github.com/0xredeth/Rafale/internal/store"
+)
+
+// ErrReorgTooDeep is returned when a reorg exceeds Sync.MaxReorgDepth.
+// The engine halts rather than rolling back, since such a deep reorg is
+// either a bug or a chain event that needs human judgment.
+var ErrReorgTooDeep = errors.New("reorg exceeds max reorg depth")
+
+// blockRef is a processed block number and its canonical hash.
+type blockRef struct {
+	Number uint64
+	Hash   common.Hash
+}
+
+// hashFetcher returns the current canonical hash for a block number.
+type hashFetcher func(ctx context.Context, number uint64) (common.Hash, error)
+
+// canonicalHash returns the hash of the canonical block at number.
+func (e *Engine) canonicalHash(ctx context.Context, number uint64) (common.Hash, error) {
+	header, err := e.rpc.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return header.Hash(), nil
+}
+
+// recordBlockHash remembers the hash of a processed block and prunes entries
+// older than the reorg window. Must be called with e.mu held.
+func (e *Engine) recordBlockHash(number uint64, hash common.Hash) {
+	// Drop entries at or above number (re-processed after a rollback)
+	i := sort.Search(len(e.recentBlocks), func(i int) bool {
+		return e.recentBlocks[i].Number >= number
+	})
+	e.recentBlocks = append(e.recentBlocks[:i], blockRef{Number: number, Hash: hash})
+
+	maxDepth := e.cfg.Sync.MaxReorgDepth
+	if number <= maxDepth {
+		return
+	}
+	cutoff := number - maxDepth
+	j := sort.Search(len(e.recentBlocks), func(i int) bool {
+		return e.recentBlocks[i].Number >= cutoff
+	})
+	e.recentBlocks = e.recentBlocks[j:]
+}
+
+// windowHashes returns the hashes of a batch's blocks below its last one
+// that are within the reorg window of the head, in ascending order. Only
+// a batch reaching into the window fetches headers: at the head, batches
+// are a block or two, and during a backfill the window is behind them.
+//
+// Parameters:
+//   - ctx (context.Context): context for cancellation
+//   - fromBlock (uint64): first block of the batch
+//   - toBlock (uint64): last block of the batch
+//   - headBlock (uint64): effective chain head
+//
+// Returns:
+//   - []blockRef: block hashes, nil if no earlier block is within the window
+//   - error: nil on success, RPC error on failure
+func (e *Engine) windowHashes(ctx context.Context, fromBlock, toBlock, headBlock uint64) ([]blockRef, error) {
+	e.mu.RLock()
+	maxDepth := e.cfg.Sync.MaxReorgDepth
+	e.mu.RUnlock()
+
+	lo := fromBlock
+	if headBlock > maxDepth {
+		lo = max(lo, headBlock-maxDepth)
+	}
+	if lo >= toBlock {
+		return nil, nil
+	}
+
+	window := make([]blockRef, 0, toBlock-lo)
+	for number := lo; number < toBlock; number++ {
+		hash, err := e.canonicalHash(ctx, number)
+		if err != nil {
+			return nil, fmt.Errorf("getting block %d hash: %w", number, err)
+		}
+		window = append(window, blockRef{Number: number, Hash: hash})
+	}
+	return window, nil
+}
+
+// findCommonAncestor walks the recorded block hashes from newest to oldest
+// and returns the newest block whose hash still matches the canonical chain.
+//
+// Parameters:
+//   - ctx (context.Context): context for cancellation
+//   - history ([]blockRef): recorded blocks in ascending order
+//   - lastBlock (uint64): last indexed block
+//   - maxDepth (uint64): maximum rollback depth
+//   - fetch (hashFetcher): canonical hash lookup
+//
+// Returns:
+//   - uint64: common ancestor block number
+//   - error: ErrReorgTooDeep if no ancestor within maxDepth, RPC error on failure
+func findCommonAncestor(ctx context.Context, history []blockRef, lastBlock, maxDepth uint64, fetch hashFetcher) (uint64, error) {
+	for i := len(history) - 1; i >= 0; i-- {
+		ref := history[i]
+		if lastBlock-ref.Number > maxDepth {
+			break
+		}
+
+		hash, err := fetch(ctx, ref.Number)
+		if err != nil {
+			return 0, fmt.Errorf("getting block %d hash: %w", ref.Number, err)
+		}
+		if hash == ref.Hash {
+			return ref.Number, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: no common ancestor within %d blocks of %d", ErrReorgTooDeep, maxDepth, lastBlock)
+}
+
+// verifyResumeBlock checks that the block of the stored sync cursor is
+// still canonical before resuming, so a reorg that happened while the
+// engine was down is rolled back like a live one. The block hashes stored
+// on events within the reorg window seed the common ancestor search.
+//
+// Returns:
+//   - error: ErrReorgTooDeep if the reorg exceeds MaxReorgDepth, error on failure
+func (e *Engine) verifyResumeBlock(ctx context.Context) error {
+	status, err := e.store.GetSyncStatus(ctx, store.SyncStatusAll)
+	if err != nil {
+		return err
+	}
+	if status == nil || status.LastBlockHash == "" || status.LastBlock == 0 {
+		return nil
+	}
+
+	var fromBlock uint64
+	if status.LastBlock > e.cfg.Sync.MaxReorgDepth {
+		fromBlock = status.LastBlock - e.cfg.Sync.MaxReorgDepth
+	}
+	hashes, err := e.store.ListBlockHashes(ctx, fromBlock, status.LastBlock-1)
+	if err != nil {
+		return err
+	}
+
+	e.seedBlockHistory(status.LastBlock, common.HexToHash(status.LastBlockHash), hashes)
+	return e.checkReorg(ctx)
+}
+
+// seedBlockHistory restores the processed block hashes from storage, as if
+// the engine had just indexed up to lastBlock.
+//
+// Parameters:
+//   - lastBlock (uint64): last indexed block
+//   - lastHash (common.Hash): hash of lastBlock when it was indexed
+//   - hashes (map[uint64]string): stored hashes of earlier blocks
+func (e *Engine) seedBlockHistory(lastBlock uint64, lastHash common.Hash, hashes map[uint64]string) {
+	history := make([]blockRef, 0, len(hashes)+1)
+	for number, hash := range hashes {
+		if number < lastBlock {
+			history = append(history, blockRef{Number: number, Hash: common.HexToHash(hash)})
+		}
+	}
+	sort.Slice(history, func(i, j int) bool { return history[i].Number < history[j].Number })
+	history = append(history, blockRef{Number: lastBlock, Hash: lastHash})
+
+	e.mu.Lock()
+	e.lastBlock = lastBlock
+	e.recentBlocks = history
+	e.mu.Unlock()
+}
+
+// checkReorg verifies the last indexed block is still canonical and rolls
+// back to the common ancestor if not.
+//
+// Returns:
+//   - error: ErrReorgTooDeep if the reorg exceeds MaxReorgDepth, error on failure
+func (e *Engine) checkReorg(ctx context.Context) error {
+	e.mu.RLock()
+	lastBlock := e.lastBlock
+	maxDepth := e.cfg.Sync.MaxReorgDepth
+	history := append([]blockRef(nil), e.recentBlocks...)
+	e.mu.RUnlock()
+
+	if len(history) == 0 || history[len(history)-1].Number != lastBlock {
+		return nil
+	}
+
+	fetch := e.canonicalHash
+
+	current, err := fetch(ctx, lastBlock)
+	if err != nil {
+		return fmt.Errorf("getting block %d hash: %w", lastBlock, err)
+	}
+	if current == history[len(history)-1].Hash {
+		return nil
+	}
+
+	reorgsDetected.Inc()
+
+	ancestor, err := findCommonAncestor(ctx, history[:len(history)-1], lastBlock, maxDepth, fetch)
+	if err != nil {
+		if errors.Is(err, ErrReorgTooDeep) {
+			log.Error().
+				Err(err).
+				Uint64("lastBlock", lastBlock).
+				Uint64("maxReorgDepth", maxDepth).
+				Msg("ALERT: reorg deeper than max_reorg_depth, halting without deleting data")
+		}
+		return err
+	}
+
+	var ancestorHash common.Hash
+	for _, ref := range history {
+		if ref.Number == ancestor {
+			ancestorHash = ref.Hash
+		}
+	}
+
+	// Delete and move the sync cursor back together
+	var deleted int64
+	err = e.store.Transaction(ctx, func(tx *gorm.DB) error {
+		var err error
+		if deleted, err = store.DeleteBlockRangeTx(tx, ancestor+1, lastBlock); err != nil {
+			return err
+		}
+		return store.UpsertSyncStatusTx(tx, store.SyncStatus{
+			Contract:      store.SyncStatusAll,
+			LastBlock:     ancestor,
+			LastBlockHash: ancestorHash.Hex(),
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("rolling back blocks %d-%d: %w", ancestor+1, lastBlock, err)
+	}
+	store.PurgeCachedBlocks(ancestor+1, lastBlock)
+	if e.broadcaster != nil {
+		e.broadcaster.RewindEvents(ancestor)
+	}
+
+	e.mu.Lock()
+	e.lastBlock = ancestor
+	e.lastBlockAt.Store(0) // ancestor's timestamp unknown until the next batch
+	i := sort.Search(len(e.recentBlocks), func(i int) bool {
+		return e.recentBlocks[i].Number > ancestor
+	})
+	e.recentBlocks = e.recentBlocks[:i]
+	e.rewindSchedules(ancestor)
+	e.mu.Unlock()
+	currentBlock.Set(float64(ancestor))
+	e.mirrorCheckpoint(ancestor)
+
+	log.Warn().
+		Uint64("ancestor", ancestor).
+		Uint64("depth", lastBlock-ancestor).
+		Int64("deleted", deleted).
+		Msg("chain reorg detected, rolled back to common ancestor")
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/0xredeth/Rafale/internal/rpc"
+	"github.com/0xredeth/Rafale/internal/store"
+)
+
+var revertedTxsTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "rafale_reverted_txs_total",
+		Help: "Total number of reverted transactions to indexed contracts stored (sync.track_reverts)",
+	},
+	[]string{"contract"},
+)
+
+// scanReverts finds the failed transactions sent to indexed contracts in
+// a block range. Each block is fetched with its transactions and each
+// transaction to an indexed contract gets its receipt checked; reverted
+// ones are replayed with eth_call against the parent block's state to
+// recover the revert data. The replay ignores earlier transactions of the
+// same block, so a revert caused by them may not reproduce: the record is
+// then stored without a reason.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - fromBlock (uint64): first block (inclusive)
+//   - toBlock (uint64): last block (inclusive)
+//
+// Returns:
+//   - []store.RevertedTx: reverted transactions in block order
+//   - error: nil on success, RPC error on failure
+func (e *Engine) scanReverts(ctx context.Context, fromBlock, toBlock uint64) ([]store.RevertedTx, error) {
+	tc, ok := e.rpc.(rpc.TxClient)
+	if !ok {
+		return nil, nil
+	}
+
+	e.mu.RLock()
+	contracts := make(map[common.Address]string)
+	for _, addr := range e.decoder.GetAddresses() {
+		if name, ok := e.decoder.ContractName(addr); ok {
+			contracts[addr] = name
+		}
+	}
+	signer := types.LatestSignerForChainID(new(big.Int).SetUint64(e.cfg.ChainID))
+	e.mu.RUnlock()
+
+	var reverts []store.RevertedTx
+	for block := fromBlock; block <= toBlock; block++ {
+		b, err := e.rpc.BlockByNumber(ctx, new(big.Int).SetUint64(block))
+		if err != nil {
+			return nil, fmt.Errorf("getting block %d: %w", block, err)
+		}
+
+		for i, tx := range b.Transactions() {
+			if tx.To() == nil {
+				continue
+			}
+			name, ok := contracts[*tx.To()]
+			if !ok {
+				continue
+			}
+
+			receipt, err := tc.TransactionReceipt(ctx, tx.Hash())
+			if err != nil {
+				return nil, fmt.Errorf("getting receipt of %s: %w", tx.Hash().Hex(), err)
+			}
+			if receipt.Status != types.ReceiptStatusFailed {
+				continue
+			}
+
+			revert := store.RevertedTx{
+				Timestamp:    time.Unix(int64(b.Time()), 0).UTC(), //nolint:gosec // G115: Timestamp won't overflow
+				BlockNumber:  block,
+				TxHash:       tx.Hash().Hex(),
+				TxIndex:      uint(i), //nolint:gosec // G115: index is non-negative
+				ContractName: name,
+				To:           e.formatAddress(*tx.To()),
+				GasUsed:      receipt.GasUsed,
+			}
+			if err := e.replayRevert(ctx, tc, signer, tx, block, &revert); err != nil {
+				return nil, err
+			}
+			reverts = append(reverts, revert)
+		}
+	}
+	return reverts, nil
+}
+
+// replayRevert fills a reverted transaction's sender and revert reason,
+// replaying it with eth_call. Replay failures other than cancellation
+// leave the reason empty.
+func (e *Engine) replayRevert(ctx context.Context, tc rpc.TxClient, signer types.Signer, tx *types.Transaction, block uint64, revert *store.RevertedTx) error {
+	msg := ethereum.CallMsg{
+		To:         tx.To(),
+		Gas:        tx.Gas(),
+		Value:      tx.Value(),
+		Data:       tx.Data(),
+		AccessList: tx.AccessList(),
+	}
+	if from, err := types.Sender(signer, tx); err == nil {
+		msg.From = from
+		revert.From = e.formatAddress(from)
+	}
+
+	parent := block
+	if parent > 0 {
+		parent--
+	}
+	_, err := tc.CallContract(ctx, msg, new(big.Int).SetUint64(parent))
+	if err == nil {
+		log.Debug().Str("tx", revert.TxHash).Msg("reverted transaction succeeded on replay, storing without reason")
+		return nil
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("replaying %s: %w", revert.TxHash, err)
+	}
+
+	data, ok := rpc.RevertData(err)
+	if !ok {
+		log.Debug().Err(err).Str("tx", revert.TxHash).Msg("replaying reverted transaction returned no revert data")
+		return nil
+	}
+	e.mu.RLock()
+	revert.Reason = e.decoder.DecodeRevert(*tx.To(), data)
+	e.mu.RUnlock()
+	revert.RevertData = data
+	return nil
+}
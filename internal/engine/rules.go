@@ -0,0 +1,129 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/0xredeth/Rafale/internal/api/graphql/model"
+	"github.com/0xredeth/Rafale/internal/store"
+	"github.com/0xredeth/Rafale/pkg/config"
+	"github.com/0xredeth/Rafale/pkg/handler"
+)
+
+// registerRules builds handlers from declarative rules, creates their
+// tables, and registers them. Rules for an event with a Go handler run
+// after it. Rules and rule tables registered before are replaced, so a
+// reload doesn't stack them or keep rolling back dropped tables.
+//
+// Parameters:
+//   - ctx (context.Context): context for table creation
+//   - rules ([]config.RuleConfig): configured rules
+//
+// Returns:
+//   - error: nil on success, error if a rule doesn't fit its event ABI or its table can't be created
+func (e *Engine) registerRules(ctx context.Context, rules []config.RuleConfig) error {
+	byEvent := make(map[string][]handler.Func)
+	var order, tables []string
+	for _, rule := range rules {
+		inputs, ok := e.decoder.EventInputs(rule.Event)
+		if !ok {
+			return fmt.Errorf("rule %s: event %s is not registered", rule.Name, rule.Event)
+		}
+
+		if rule.Table != "" {
+			stmts, err := handler.RuleTableDDL(rule, inputs)
+			if err != nil {
+				return err
+			}
+			for _, stmt := range stmts {
+				if err := e.store.DB().WithContext(ctx).Exec(stmt).Error; err != nil {
+					return fmt.Errorf("rule %s: creating table %s: %w", rule.Name, rule.Table, err)
+				}
+			}
+			if !slices.Contains(tables, rule.Table) {
+				tables = append(tables, rule.Table)
+			}
+		}
+
+		h, err := handler.NewRuleHandler(rule, inputs, e.broadcastRule)
+		if err != nil {
+			return err
+		}
+		if _, ok := byEvent[rule.Event]; !ok {
+			order = append(order, rule.Event)
+		}
+		byEvent[rule.Event] = append(byEvent[rule.Event], h)
+	}
+
+	e.unregisterRules()
+
+	// Roll rule rows back with reorgs and reindexing
+	for _, table := range tables {
+		store.RegisterBlockTable(table)
+	}
+	e.ruleTables = tables
+
+	for _, eventID := range order {
+		chain := byEvent[eventID]
+		base, ok := e.handlers.Get(eventID)
+		if ok {
+			chain = append([]handler.Func{base}, chain...)
+		}
+		if e.ruleBases == nil {
+			e.ruleBases = make(map[string]handler.Func)
+		}
+		e.ruleBases[eventID] = base
+		e.handlers.Register(eventID, handler.Chain(chain...))
+
+		log.Info().
+			Str("event", eventID).
+			Int("rules", len(byEvent[eventID])).
+			Msg("registered config rules")
+	}
+
+	return nil
+}
+
+// unregisterRules puts back the handlers rule chains were built on and
+// stops rolling back rule tables.
+func (e *Engine) unregisterRules() {
+	for eventID, base := range e.ruleBases {
+		if base != nil {
+			e.handlers.Register(eventID, base)
+		} else {
+			e.handlers.Unregister(eventID)
+		}
+	}
+	e.ruleBases = nil
+
+	for _, table := range e.ruleTables {
+		store.UnregisterBlockTable(table)
+	}
+	e.ruleTables = nil
+}
+
+// broadcastRule publishes an event matched by a broadcasting rule, named
+// after the rule so subscribers can filter on it.
+func (e *Engine) broadcastRule(ctx *handler.Context, rule string, data map[string]any) {
+	if e.broadcaster == nil {
+		return
+	}
+	event := &model.GenericEvent{
+		ID:          "0", // ID not available until tx commits
+		BlockNumber: strconv.FormatUint(ctx.Block.Number, 10),
+		TxHash:      ctx.Log.TxHash.Hex(),
+		TxIndex:     int(ctx.Log.TxIndex), //nolint:gosec // G115: TxIndex is small
+		LogIndex:    int(ctx.Log.Index),   //nolint:gosec // G115: LogIndex is small
+		Timestamp:   ctx.Block.Time,
+		Contract:    ctx.Event.ContractName,
+		EventName:   rule,
+		Data:        convertEventData(data),
+	}
+	if pending, ok := e.ruleBroadcasts.Load(ctx); ok {
+		pending.(*pendingBroadcasts).add(event)
+		return
+	}
+	e.broadcaster.BroadcastEvent(event)
+}
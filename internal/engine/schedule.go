@@ -0,0 +1,275 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/0xredeth/Rafale/internal/rpc"
+	"github.com/0xredeth/Rafale/pkg/config"
+)
+
+// contractSchedule tracks a contract polled less often than the global
+// interval. Its logs are left out of the per-tick fetch and fetched only
+// when due, from its own cursor up to the current batch end.
+type contractSchedule struct {
+	addr     common.Address
+	interval time.Duration // 0 = catching up before rejoining the per-tick fetch
+	cursor   uint64        // last block fetched for this contract
+	nextDue  time.Time
+}
+
+// slowContracts returns contracts whose poll_interval is longer than the
+// global poll interval, keyed by name.
+func slowContracts(cfg *config.Config) map[string]time.Duration {
+	slow := make(map[string]time.Duration)
+	for name, contract := range cfg.Contracts {
+		if contract.PollInterval > cfg.PollInterval {
+			slow[name] = contract.PollInterval
+		}
+	}
+	return slow
+}
+
+// initSchedules creates schedules for slow contracts when sync starts.
+//
+// Sync resumes from the highest indexed block across all contracts, so a
+// slow contract may have had a deferred range pending when the process
+// stopped. Its cursor is set back to its own last indexed block, bounded
+// by the largest range it can have deferred, so that range is fetched
+// again without duplicating stored events.
+//
+// Parameters:
+//   - ctx (context.Context): context for cancellation
+//   - startBlock (uint64): block sync resumes from
+//
+// Returns:
+//   - error: nil on success, store error on failure
+func (e *Engine) initSchedules(ctx context.Context, startBlock uint64) error {
+	e.mu.RLock()
+	cfg := e.cfg
+	e.mu.RUnlock()
+
+	schedules := make(map[string]*contractSchedule)
+	for name, interval := range slowContracts(cfg) {
+		contract := cfg.Contracts[name]
+
+		indexed, err := e.store.GetMaxContractBlockNumber(ctx, name)
+		if err != nil {
+			return fmt.Errorf("getting max block for %s: %w", name, err)
+		}
+
+		// Deferred range is at most one batch per global tick until due
+		ticks := uint64(1)
+		if cfg.PollInterval > 0 {
+			ticks = uint64((interval + cfg.PollInterval - 1) / cfg.PollInterval) //nolint:gosec // G115: positive durations
+		}
+		window := cfg.Sync.BatchSize * ticks
+
+		cursor := indexed
+		if startBlock > window && startBlock-window > cursor {
+			cursor = startBlock - window
+		}
+		if contract.StartBlock > 0 && contract.StartBlock-1 > cursor {
+			cursor = contract.StartBlock - 1
+		}
+		if cursor > startBlock {
+			cursor = startBlock
+		}
+
+		schedules[name] = &contractSchedule{
+			addr:     common.HexToAddress(contract.Address),
+			interval: interval,
+			cursor:   cursor,
+		}
+
+		log.Info().
+			Str("contract", name).
+			Dur("pollInterval", interval).
+			Uint64("cursor", cursor).
+			Msg("contract uses its own poll interval")
+	}
+
+	e.mu.Lock()
+	e.schedules = schedules
+	e.mu.Unlock()
+
+	return nil
+}
+
+// reloadSchedules reconciles schedules with a new config. Must be called
+// with e.mu held. Contracts that stop being slow keep their schedule until
+// their deferred range is fetched.
+func (e *Engine) reloadSchedules(newCfg *config.Config) {
+	slow := slowContracts(newCfg)
+	schedules := make(map[string]*contractSchedule)
+
+	for name, sched := range e.schedules {
+		contract, ok := newCfg.Contracts[name]
+		if !ok || common.HexToAddress(contract.Address) != sched.addr {
+			continue
+		}
+		sched.interval = slow[name]
+		if sched.interval == 0 {
+			sched.nextDue = time.Time{}
+		}
+		schedules[name] = sched
+	}
+
+	for name, interval := range slow {
+		if _, ok := schedules[name]; ok {
+			continue
+		}
+		schedules[name] = &contractSchedule{
+			addr:     common.HexToAddress(newCfg.Contracts[name].Address),
+			interval: interval,
+			cursor:   e.lastBlock,
+		}
+	}
+
+	e.schedules = schedules
+}
+
+// fetchSyncLogs fetches logs for a sync batch. Contracts on their own poll
+// interval are left out unless due, in which case their deferred range up
+// to toBlock is fetched as well. The returned commit function advances
+// their cursors and must be called once the logs are stored.
+//
+// Parameters:
+//   - ctx (context.Context): context for cancellation
+//   - fromBlock (uint64): first block of the batch
+//   - toBlock (uint64): last block of the batch
+//
+// Returns:
+//   - []types.Log: logs ordered by block and log index
+//   - func(): advances schedule cursors
+//   - error: nil on success, RPC error on failure
+func (e *Engine) fetchSyncLogs(ctx context.Context, fromBlock, toBlock uint64) ([]types.Log, func(), error) {
+	e.mu.RLock()
+	if len(e.schedules) == 0 {
+		filterMode := e.cfg.Sync.FilterMode
+		e.mu.RUnlock()
+		if fc, ok := e.rpc.(rpc.FilterClient); ok && filterMode {
+			return e.fetchFilterLogs(ctx, fc, fromBlock, toBlock)
+		}
+		logs, err := e.fetchBlockRangeLogs(ctx, fromBlock, toBlock)
+		return logs, func() {}, err
+	}
+
+	scheduled := make(map[common.Address]bool, len(e.schedules))
+	for _, sched := range e.schedules {
+		scheduled[sched.addr] = true
+	}
+
+	var fast []common.Address
+	for _, addr := range e.decoder.GetAddresses() {
+		if !scheduled[addr] {
+			fast = append(fast, addr)
+		}
+	}
+
+	// Deferred fetches wait while a reindex owns the already-indexed range
+	now := time.Now()
+	due := make(map[string]contractSchedule)
+	if !e.reindexing.Load() {
+		for name, sched := range e.schedules {
+			if !now.Before(sched.nextDue) && sched.cursor < toBlock {
+				due[name] = *sched
+			}
+		}
+	}
+	topics := [][]common.Hash{e.decoder.GetEventSignatures()}
+	e.mu.RUnlock()
+
+	// An empty batch (fromBlock > toBlock) only fetches deferred ranges
+	var logs []types.Log
+	if len(fast) > 0 && fromBlock <= toBlock {
+		fetched, err := e.rpc.FetchLogs(ctx, fast, topics, fromBlock, toBlock)
+		if err != nil {
+			return nil, nil, fmt.Errorf("fetching logs: %w", err)
+		}
+		logs = append(logs, fetched...)
+	}
+
+	for name, sched := range due {
+		fetched, err := e.rpc.FetchLogs(ctx, []common.Address{sched.addr}, topics, sched.cursor+1, toBlock)
+		if err != nil {
+			return nil, nil, fmt.Errorf("fetching logs for %s: %w", name, err)
+		}
+		logs = append(logs, fetched...)
+
+		log.Debug().
+			Str("contract", name).
+			Uint64("from", sched.cursor+1).
+			Uint64("to", toBlock).
+			Int("logs", len(fetched)).
+			Msg("fetched scheduled contract logs")
+	}
+
+	sort.Slice(logs, func(i, k int) bool {
+		if logs[i].BlockNumber != logs[k].BlockNumber {
+			return logs[i].BlockNumber < logs[k].BlockNumber
+		}
+		return logs[i].Index < logs[k].Index
+	})
+
+	commit := func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+
+		for name := range due {
+			sched, ok := e.schedules[name]
+			if !ok {
+				continue
+			}
+			sched.cursor = toBlock
+			if sched.interval == 0 {
+				// Caught up; rejoin the per-tick fetch
+				delete(e.schedules, name)
+				continue
+			}
+			sched.nextDue = now.Add(sched.interval)
+		}
+	}
+
+	return logs, commit, nil
+}
+
+// rewindSchedules moves schedule cursors back to a rollback target.
+// Must be called with e.mu held.
+func (e *Engine) rewindSchedules(block uint64) {
+	for _, sched := range e.schedules {
+		if sched.cursor > block {
+			sched.cursor = block
+		}
+	}
+}
+
+// dropDeferredLogs removes logs beyond a scheduled contract's cursor. Those
+// blocks are owned by the contract's deferred fetch.
+func (e *Engine) dropDeferredLogs(logs []types.Log) []types.Log {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if len(e.schedules) == 0 {
+		return logs
+	}
+
+	cursors := make(map[common.Address]uint64, len(e.schedules))
+	for _, sched := range e.schedules {
+		cursors[sched.addr] = sched.cursor
+	}
+
+	kept := logs[:0]
+	for _, l := range logs {
+		if cursor, ok := cursors[l.Address]; ok && l.BlockNumber > cursor {
+			continue
+		}
+		kept = append(kept, l)
+	}
+	return kept
+}
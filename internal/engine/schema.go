@@ -0,0 +1,151 @@
+package engine
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/0xredeth/Rafale/pkg/config"
+	"github.com/0xredeth/Rafale/pkg/decoder"
+)
+
+var schemaViolations = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "rafale_schema_violations_total",
+		Help: "Total number of events whose data didn't match their event_schemas entry",
+	},
+	[]string{"event"},
+)
+
+// eventSchema maps lowercased field names to their expected type.
+type eventSchema map[string]string
+
+// buildEventSchemas indexes the configured event schemas by event ID.
+//
+// Parameters:
+//   - schemas ([]config.EventSchemaConfig): configured schemas
+//   - dec (*decoder.Decoder): decoder the events must be registered with
+//
+// Returns:
+//   - map[string]eventSchema: schemas by event ID (nil if none)
+//   - error: nil on success, error if a schema's event is not registered
+func buildEventSchemas(schemas []config.EventSchemaConfig, dec *decoder.Decoder) (map[string]eventSchema, error) {
+	if len(schemas) == 0 {
+		return nil, nil
+	}
+
+	byEvent := make(map[string]eventSchema, len(schemas))
+	for _, schema := range schemas {
+		if _, ok := dec.EventInputs(schema.Event); !ok {
+			return nil, fmt.Errorf("event schema: event %s is not registered", schema.Event)
+		}
+		fields := make(eventSchema, len(schema.Fields))
+		for name, typ := range schema.Fields {
+			fields[strings.ToLower(name)] = typ
+		}
+		byEvent[schema.Event] = fields
+	}
+	return byEvent, nil
+}
+
+// checkEventSchema validates a decoded event against its schema, if any,
+// and applies sync.schema_violation_policy to a mismatch.
+//
+// Parameters:
+//   - event (*decoder.DecodedEvent): decoded (and transformed) event
+//
+// Returns:
+//   - bool: true if the event should be skipped
+//   - error: the violation under the halt policy, nil otherwise
+func (e *Engine) checkEventSchema(event *decoder.DecodedEvent) (bool, error) {
+	schema, ok := e.schemas[event.EventID]
+	if !ok {
+		return false, nil
+	}
+	err := schema.validate(event.Data)
+	if err == nil {
+		return false, nil
+	}
+
+	schemaViolations.WithLabelValues(event.EventID).Inc()
+	if e.cfg.Sync.SchemaViolationPolicy == config.SchemaPolicyHalt {
+		return false, fmt.Errorf("event %s at block %d violates its schema: %w", event.EventID, event.Log.BlockNumber, err)
+	}
+
+	log.Warn().
+		Err(err).
+		Str("event", event.EventID).
+		Str("txHash", event.Log.TxHash.Hex()).
+		Uint("logIndex", event.Log.Index).
+		Msg("event data violates its schema, skipping")
+	return true, nil
+}
+
+// validate checks that every schema field is present in data with the
+// expected type. Field names match case-insensitively.
+//
+// Parameters:
+//   - data (map[string]interface{}): decoded event data
+//
+// Returns:
+//   - error: nil if data conforms, the first mismatch (in field order) otherwise
+func (s eventSchema) validate(data map[string]interface{}) error {
+	values := make(map[string]interface{}, len(data))
+	for name, v := range data {
+		values[strings.ToLower(name)] = v
+	}
+
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		v, ok := values[name]
+		if !ok {
+			return fmt.Errorf("missing field %s", name)
+		}
+		if typ := s[name]; !matchesSchemaType(v, typ) {
+			return fmt.Errorf("field %s: want %s, got %T", name, typ, v)
+		}
+	}
+	return nil
+}
+
+// matchesSchemaType reports whether a decoded value has a schema type.
+func matchesSchemaType(v interface{}, typ string) bool {
+	switch val := v.(type) {
+	case common.Address:
+		return typ == config.SchemaTypeAddress
+	case string:
+		return typ == config.SchemaTypeString || (typ == config.SchemaTypeAddress && common.IsHexAddress(val))
+	case *big.Int:
+		return typ == config.SchemaTypeInt || (typ == config.SchemaTypeUint && val != nil && val.Sign() >= 0)
+	case bool:
+		return typ == config.SchemaTypeBool
+	case common.Hash, []byte:
+		return typ == config.SchemaTypeBytes
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return typ == config.SchemaTypeUint || typ == config.SchemaTypeInt
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return typ == config.SchemaTypeInt || (typ == config.SchemaTypeUint && rv.Int() >= 0)
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return typ == config.SchemaTypeBytes
+		}
+		return typ == config.SchemaTypeArray
+	default:
+		return false
+	}
+}
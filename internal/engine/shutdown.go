@@ -0,0 +1,135 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/0xredeth/Rafale/internal/store"
+)
+
+// shutdownState coordinates Stop with a running sync loop.
+type shutdownState struct {
+	stopOnce sync.Once
+	stopping chan struct{} // closed by Stop: no new batch starts
+
+	abortCtx context.Context    // cancelled once Stop's deadline passes
+	abort    context.CancelFunc // rolls back the in-flight commit
+
+	doneOnce sync.Once
+	done     chan struct{} // closed when Run returns
+}
+
+// shutdown returns the engine's shutdown state, creating it on first use.
+func (e *Engine) shutdown() *shutdownState {
+	e.shutdownOnce.Do(func() {
+		abortCtx, abort := context.WithCancel(context.Background())
+		e.shutdownSt = &shutdownState{
+			stopping: make(chan struct{}),
+			abortCtx: abortCtx,
+			abort:    abort,
+			done:     make(chan struct{}),
+		}
+	})
+	return e.shutdownSt
+}
+
+// stopRequested reports whether the sync loop must not start another
+// batch: ctx was cancelled or Stop was called.
+//
+// Parameters:
+//   - ctx (context.Context): sync loop context
+//
+// Returns:
+//   - bool: true once shutting down
+func (e *Engine) stopRequested(ctx context.Context) bool {
+	if ctx.Err() != nil {
+		return true
+	}
+	select {
+	case <-e.shutdown().stopping:
+		return true
+	default:
+		return false
+	}
+}
+
+// commitContext returns the context a batch commits under. It outlives
+// ctx, so a shutdown never abandons a transaction half way, but is
+// cancelled when Stop gives up waiting, rolling the transaction back.
+//
+// Parameters:
+//   - ctx (context.Context): sync loop context
+//
+// Returns:
+//   - context.Context: commit context
+//   - context.CancelFunc: releases the context once the commit is done
+func (e *Engine) commitContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	commitCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(e.shutdown().abortCtx, cancel)
+	return commitCtx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// Stop shuts the sync loop down gracefully: no new batch is started, the
+// batch being committed finishes, and the sync cursor is persisted. It
+// returns once Run has returned. Cancelling Run's context has the same
+// effect on the loop; Stop additionally waits for it and bounds the wait.
+//
+// Parameters:
+//   - ctx (context.Context): deadline for the shutdown; when it passes the
+//     in-flight transaction is rolled back, to be re-processed on restart
+//
+// Returns:
+//   - error: nil once stopped, ctx error if the batch didn't commit in time, cursor write error on failure
+func (e *Engine) Stop(ctx context.Context) error {
+	s := e.shutdown()
+	s.stopOnce.Do(func() { close(s.stopping) })
+
+	if e.running.Load() {
+		select {
+		case <-s.done:
+		case <-ctx.Done():
+			s.abort()
+			return fmt.Errorf("waiting for in-flight batch: %w", ctx.Err())
+		}
+	}
+
+	return e.persistSyncStatus(ctx)
+}
+
+// persistSyncStatus writes the engine-wide sync cursor at the last indexed
+// block. Every batch already writes it as it commits; this final write is
+// a safeguard leaving the cursor at the engine's own last block.
+//
+// Parameters:
+//   - ctx (context.Context): context for cancellation
+//
+// Returns:
+//   - error: nil on success or when nothing was indexed, write error on failure
+func (e *Engine) persistSyncStatus(ctx context.Context) error {
+	e.mu.RLock()
+	lastBlock := e.lastBlock
+	var hash string
+	if n := len(e.recentBlocks); n > 0 && e.recentBlocks[n-1].Number == lastBlock {
+		hash = e.recentBlocks[n-1].Hash.Hex()
+	}
+	e.mu.RUnlock()
+
+	// Without the block's hash the cursor couldn't be verified on resume
+	if hash == "" {
+		return nil
+	}
+
+	if err := e.store.UpsertSyncStatus(ctx, store.SyncStatus{
+		Contract:      store.SyncStatusAll,
+		LastBlock:     lastBlock,
+		LastBlockHash: hash,
+	}); err != nil {
+		return fmt.Errorf("persisting sync status: %w", err)
+	}
+	log.Info().Uint64("lastBlock", lastBlock).Msg("sync cursor persisted")
+	return nil
+}
@@ -0,0 +1,50 @@
+package engine
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/0xredeth/Rafale/internal/api/graphql/model"
+)
+
+// Sync states published to sync state subscribers.
+const (
+	// SyncStateBackfilling means indexing trails the head by a batch or more.
+	SyncStateBackfilling = "backfilling"
+
+	// SyncStateSynced means indexing reached the head.
+	SyncStateSynced = "synced"
+)
+
+// trackSyncState publishes the sync state when it changes: the first
+// state after startup, then every transition between backfilling and
+// synced. A batch ending at the head is synced; one ending short of it
+// (the lag exceeded the batch size) is backfilling.
+//
+// Parameters:
+//   - block (uint64): last indexed block
+//   - head (uint64): chain head
+func (e *Engine) trackSyncState(block, head uint64) {
+	state := SyncStateBackfilling
+	if block >= head {
+		state = SyncStateSynced
+	}
+	if prev := e.syncState.Swap(state); prev == state {
+		return
+	}
+
+	log.Info().
+		Str("state", state).
+		Uint64("block", block).
+		Uint64("head", head).
+		Msg("sync state changed")
+
+	if e.broadcaster != nil {
+		e.broadcaster.BroadcastSyncState(&model.SyncStateChange{
+			Type:      state,
+			Block:     strconv.FormatUint(block, 10),
+			HeadBlock: strconv.FormatUint(head, 10),
+			Time:      time.Now().UTC(),
+		})
+	}
+}
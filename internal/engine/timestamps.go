@@ -0,0 +1,130 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/0xredeth/Rafale/internal/store"
+)
+
+// BackfillTimestamps corrects stored event timestamps in an inclusive block
+// range that were defaulted to insertion time instead of the block time.
+// Suspect blocks are those with rows timestamped within a second of their
+// creation; their real timestamps are fetched from the block headers and
+// mismatching rows are updated. Runs as a tracked job, one transaction per
+// batch.
+//
+// Parameters:
+//   - ctx (context.Context): context for cancellation
+//   - fromBlock (uint64): first block (inclusive)
+//   - toBlock (uint64): last block (inclusive)
+//
+// Returns:
+//   - error: nil on success, ErrInvalidRange, or error on failure
+func (e *Engine) BackfillTimestamps(ctx context.Context, fromBlock, toBlock uint64) (err error) {
+	e.mu.RLock()
+	lastBlock := e.lastBlock
+	batchSize := e.cfg.Sync.BatchSize
+	e.mu.RUnlock()
+
+	if fromBlock > toBlock || toBlock > lastBlock {
+		return fmt.Errorf("%w: %d-%d (last indexed block %d)", ErrInvalidRange, fromBlock, toBlock, lastBlock)
+	}
+
+	ctx, jobID := e.startJob(ctx, JobTypeBackfillTimestamps, fromBlock, toBlock)
+	defer func() { e.finishJob(jobID, err) }()
+
+	if batchSize == 0 {
+		batchSize = 1
+	}
+
+	log.Info().
+		Str("job", jobID).
+		Uint64("from", fromBlock).
+		Uint64("to", toBlock).
+		Msg("timestamp backfill started")
+
+	blockTimes := make(map[uint64]time.Time)
+	var blocks int
+	var updated int64
+	for start := fromBlock; start <= toBlock; start += batchSize {
+		end := start + batchSize - 1
+		if end > toBlock || end < start {
+			end = toBlock
+		}
+
+		err = e.store.Transaction(ctx, func(tx *gorm.DB) error {
+			suspects, err := store.ListSuspectTimestampBlocksTx(tx, start, end)
+			if err != nil {
+				return err
+			}
+
+			for _, block := range suspects {
+				blockTime, err := e.blockTime(ctx, blockTimes, block)
+				if err != nil {
+					return err
+				}
+
+				n, err := store.SetBlockTimestampTx(tx, block, blockTime)
+				if err != nil {
+					return err
+				}
+				if n > 0 {
+					blocks++
+					updated += n
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("backfilling timestamps %d-%d: %w", start, end, err)
+		}
+		store.PurgeCachedBlocks(start, end)
+
+		e.updateJobProgress(jobID, end)
+
+		if end == toBlock {
+			break
+		}
+	}
+
+	log.Info().
+		Str("job", jobID).
+		Uint64("from", fromBlock).
+		Uint64("to", toBlock).
+		Int("blocks", blocks).
+		Int64("rows", updated).
+		Msg("timestamp backfill complete")
+
+	return nil
+}
+
+// blockTime returns a block's header timestamp, caching it so
+// retried batches don't refetch.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - cache (map[uint64]time.Time): timestamps fetched so far
+//   - block (uint64): block number
+//
+// Returns:
+//   - time.Time: block timestamp
+//   - error: nil on success, RPC error on failure
+func (e *Engine) blockTime(ctx context.Context, cache map[uint64]time.Time, block uint64) (time.Time, error) {
+	if t, ok := cache[block]; ok {
+		return t, nil
+	}
+
+	header, err := e.rpc.HeaderByNumber(ctx, new(big.Int).SetUint64(block))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("getting block %d header: %w", block, err)
+	}
+
+	t := time.Unix(int64(header.Time), 0).UTC() //nolint:gosec // G115: Timestamp won't overflow
+	cache[block] = t
+	return t, nil
+}
@@ -0,0 +1,46 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0xredeth/Rafale/pkg/decoder"
+)
+
+// Transform mutates a decoded event before it is stored or handled, e.g.
+// to redact or add fields in Data. Returning an error fails the batch.
+type Transform func(ctx context.Context, event *decoder.DecodedEvent) error
+
+// AddTransform appends a transform applied to every decoded event.
+// Transforms run in the order they were added, after decoding and
+// before storage, broadcasting and typed handlers.
+//
+// Parameters:
+//   - t (Transform): the transform to append
+func (e *Engine) AddTransform(t Transform) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.transforms = append(e.transforms, t)
+}
+
+// applyTransforms runs the registered transforms on event in order.
+//
+// Parameters:
+//   - ctx (context.Context): batch context
+//   - event (*decoder.DecodedEvent): event to transform in place
+//
+// Returns:
+//   - error: nil on success, the first transform error otherwise
+func (e *Engine) applyTransforms(ctx context.Context, event *decoder.DecodedEvent) error {
+	e.mu.RLock()
+	transforms := e.transforms
+	e.mu.RUnlock()
+
+	for i, t := range transforms {
+		if err := t(ctx, event); err != nil {
+			return fmt.Errorf("transform %d on %s: %w", i, event.EventID, err)
+		}
+	}
+	return nil
+}
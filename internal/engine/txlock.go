@@ -0,0 +1,114 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// txLockKey is the statement context key of a txLock.
+type txLockKey struct{}
+
+// txLock serializes statements on a transaction shared by goroutines: a
+// transaction is bound to one connection, which runs one statement at a
+// time. Each goroutine holds its own txLock over the shared mutex, so
+// nested statements (e.g. association saves) don't deadlock.
+type txLock struct {
+	mu    *sync.Mutex
+	depth int
+}
+
+// acquire takes the shared mutex for a statement.
+func (l *txLock) acquire() {
+	if l.depth == 0 {
+		l.mu.Lock()
+	}
+	l.depth++
+}
+
+// release returns the shared mutex once the outermost statement is done.
+func (l *txLock) release() {
+	if l.depth == 0 {
+		return
+	}
+	l.depth--
+	if l.depth == 0 {
+		l.mu.Unlock()
+	}
+}
+
+// releaseAll returns the shared mutex if a statement never finished, as
+// when a handler panicked mid-statement and the panic was recovered.
+func (l *txLock) releaseAll() {
+	if l.depth > 0 {
+		l.depth = 0
+		l.mu.Unlock()
+	}
+}
+
+// lockedSession returns a session of tx whose statements hold mu while they
+// run, for one goroutine (see txLock).
+//
+// Parameters:
+//   - tx (*gorm.DB): shared transaction
+//   - mu (*sync.Mutex): mutex shared by every session of tx
+//
+// Returns:
+//   - *gorm.DB: session for one goroutine
+//   - *txLock: the session's lock, to release with releaseAll when done
+func lockedSession(tx *gorm.DB, mu *sync.Mutex) (*gorm.DB, *txLock) {
+	lock := &txLock{mu: mu}
+	ctx := tx.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return tx.WithContext(context.WithValue(ctx, txLockKey{}, lock)), lock
+}
+
+// registerTxLockCallbacks makes every statement run under the txLock of
+// its session, if any: the lock is taken before the first callback and
+// released after the last, so rows are fully scanned before another
+// goroutine's statement runs. Rows left open by Rows() are not covered;
+// handlers sharing a transaction must not use it.
+//
+// Parameters:
+//   - db (*gorm.DB): database whose callbacks to extend
+//
+// Returns:
+//   - error: nil on success, registration error on failure
+func registerTxLockCallbacks(db *gorm.DB) error {
+	acquire := func(db *gorm.DB) {
+		if lock, ok := db.Statement.Context.Value(txLockKey{}).(*txLock); ok {
+			lock.acquire()
+		}
+	}
+	release := func(db *gorm.DB) {
+		if lock, ok := db.Statement.Context.Value(txLockKey{}).(*txLock); ok {
+			lock.release()
+		}
+	}
+
+	const lockName, unlockName = "rafale:tx_lock", "rafale:tx_unlock"
+	callbacks := db.Callback()
+	for _, register := range []func() error{
+		func() error { return callbacks.Create().Before("*").Register(lockName, acquire) },
+		func() error { return callbacks.Create().After("*").Register(unlockName, release) },
+		func() error { return callbacks.Query().Before("*").Register(lockName, acquire) },
+		func() error { return callbacks.Query().After("*").Register(unlockName, release) },
+		func() error { return callbacks.Update().Before("*").Register(lockName, acquire) },
+		func() error { return callbacks.Update().After("*").Register(unlockName, release) },
+		func() error { return callbacks.Delete().Before("*").Register(lockName, acquire) },
+		func() error { return callbacks.Delete().After("*").Register(unlockName, release) },
+		func() error { return callbacks.Row().Before("*").Register(lockName, acquire) },
+		func() error { return callbacks.Row().After("*").Register(unlockName, release) },
+		func() error { return callbacks.Raw().Before("*").Register(lockName, acquire) },
+		func() error { return callbacks.Raw().After("*").Register(unlockName, release) },
+	} {
+		if err := register(); err != nil {
+			return fmt.Errorf("registering transaction lock callbacks: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/0xredeth/Rafale/pkg/config"
+)
+
+// ErrNodeNotSynced is returned when the RPC node's reported head is not plausible.
+var ErrNodeNotSynced = errors.New("node appears not synced")
+
+// headFetcher returns the node's current head block number.
+type headFetcher func(ctx context.Context) (uint64, error)
+
+// checkHeadPlausible validates a reported head against the network preset.
+//
+// Parameters:
+//   - head (uint64): reported head block
+//   - preset (config.NetworkPreset): network preset (zero value skips range check)
+//
+// Returns:
+//   - error: ErrNodeNotSynced if implausible, nil otherwise
+func checkHeadPlausible(head uint64, preset config.NetworkPreset) error {
+	if head == 0 {
+		return fmt.Errorf("%w: reported head is 0", ErrNodeNotSynced)
+	}
+	if preset.MinHeadBlock > 0 && head < preset.MinHeadBlock {
+		return fmt.Errorf("%w: reported head %d is below expected minimum %d", ErrNodeNotSynced, head, preset.MinHeadBlock)
+	}
+	return nil
+}
+
+// waitForHeadAdvance polls the head until it moves past first or the timeout elapses.
+//
+// Parameters:
+//   - ctx (context.Context): context for cancellation
+//   - fetch (headFetcher): head lookup
+//   - first (uint64): head observed at the first poll
+//   - interval (time.Duration): delay between polls
+//   - timeout (time.Duration): maximum time to wait
+//
+// Returns:
+//   - uint64: advanced head
+//   - error: ErrNodeNotSynced if the head did not advance, RPC or context error on failure
+func waitForHeadAdvance(ctx context.Context, fetch headFetcher, first uint64, interval, timeout time.Duration) (uint64, error) {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-deadline.C:
+			return 0, fmt.Errorf("%w: head stuck at %d for %s", ErrNodeNotSynced, first, timeout)
+		case <-ticker.C:
+			head, err := fetch(ctx)
+			if err != nil {
+				return 0, fmt.Errorf("getting block number: %w", err)
+			}
+			if head > first {
+				return head, nil
+			}
+		}
+	}
+}
+
+// warmUp verifies the RPC node reports a plausible, advancing chain head
+// before syncing starts, so a fresh or stalled node fails fast instead of
+// leaving the indexer idle. Custom networks may be devnets sitting at
+// genesis or mining on demand, and bounded (end_block) runs don't need new
+// blocks, so neither waits for the head to advance.
+//
+// Parameters:
+//   - ctx (context.Context): context for cancellation
+//
+// Returns:
+//   - error: ErrNodeNotSynced if the node is not usable, error on failure
+func (e *Engine) warmUp(ctx context.Context) error {
+	preset, _ := e.cfg.NetworkPreset()
+	_, custom := e.cfg.Networks[e.cfg.Network]
+	_, bounded := syncEndBlock(e.cfg)
+
+	fetch := func(ctx context.Context) (uint64, error) {
+		return e.rpc.BlockNumber(ctx)
+	}
+
+	head, err := fetch(ctx)
+	if err != nil {
+		return fmt.Errorf("getting block number: %w", err)
+	}
+	if head > 0 || !custom {
+		if err := checkHeadPlausible(head, preset); err != nil {
+			return err
+		}
+	}
+
+	if custom || bounded {
+		log.Info().
+			Uint64("head", head).
+			Msg("RPC node head verified")
+		return nil
+	}
+
+	interval := e.cfg.PollInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	// Allow a few block times for at least one new block to appear
+	timeout := 3 * max(preset.BlockTime, interval)
+
+	advanced, err := waitForHeadAdvance(ctx, fetch, head, interval, timeout)
+	if err != nil {
+		return err
+	}
+
+	log.Info().
+		Uint64("head", advanced).
+		Msg("RPC node head verified")
+
+	return nil
+}
+
+// startupDelay sleeps a random duration in [0, jitter) so instances started
+// together spread their first RPC calls.
+//
+// Parameters:
+//   - ctx (context.Context): context for cancellation
+//   - jitter (time.Duration): upper bound of the delay (0 = no delay)
+//
+// Returns:
+//   - error: nil after the delay, ctx error if cancelled while waiting
+func startupDelay(ctx context.Context, jitter time.Duration) error {
+	if jitter <= 0 {
+		return nil
+	}
+
+	delay := rand.N(jitter) //nolint:gosec // G404: jitter needs no crypto randomness
+	log.Info().Dur("delay", delay).Msg("delaying start by startup jitter")
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
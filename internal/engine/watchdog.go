@@ -0,0 +1,71 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// markProgress records a successful sync loop tick for the watchdog.
+func (e *Engine) markProgress() {
+	e.lastProgress.Store(time.Now().UnixNano())
+}
+
+// stalledFor returns how long the sync loop has gone without progress.
+func (e *Engine) stalledFor(now time.Time) time.Duration {
+	return now.Sub(time.Unix(0, e.lastProgress.Load()))
+}
+
+// superviseSyncLoop runs the sync loop under a watchdog. If no tick succeeds
+// within timeout, the loop is cancelled and started again. A loop that does
+// not exit within another timeout after cancellation is stuck beyond
+// recovery, so the process panics for its supervisor to restart it.
+//
+// Parameters:
+//   - ctx (context.Context): context for cancellation
+//   - timeout (time.Duration): maximum time without progress
+//
+// Returns:
+//   - error: nil on shutdown, error if sync must halt
+func (e *Engine) superviseSyncLoop(ctx context.Context, timeout time.Duration) error {
+	check := time.NewTicker(max(timeout/4, 10*time.Millisecond))
+	defer check.Stop()
+
+	for {
+		loopCtx, cancel := context.WithCancel(ctx)
+		done := make(chan error, 1)
+		e.markProgress()
+		go func() { done <- e.syncLoop(loopCtx) }()
+
+	watch:
+		for {
+			select {
+			case err := <-done:
+				cancel()
+				return err
+
+			case now := <-check.C:
+				stalled := e.stalledFor(now)
+				if stalled < timeout {
+					continue
+				}
+
+				syncStalls.Inc()
+				log.Error().
+					Dur("stalled", stalled).
+					Dur("stallTimeout", timeout).
+					Msg("ALERT: sync loop made no progress, restarting")
+
+				cancel()
+				select {
+				case <-done:
+					break watch
+				case <-ctx.Done():
+					return nil
+				case <-time.After(timeout):
+					panic(fmt.Sprintf("sync loop stalled for %s and did not stop after cancellation", stalled))
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// Package logging provides per-subsystem loggers whose levels can be set
+// independently from config (log.levels), on top of zerolog's global logger.
+package logging
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// Logger is a subsystem's logger. Entries carry a "subsystem" field and are
+// filtered at the subsystem's configured level, or the default level.
+type Logger struct {
+	name   string
+	cached atomic.Pointer[zerolog.Logger] // rebuilt after Configure/SetDefaultLevel
+}
+
+var (
+	mu           sync.RWMutex
+	loggers      []*Logger
+	levels       = make(map[string]zerolog.Level)
+	defaultLevel = zerolog.InfoLevel
+)
+
+// New returns the logger of a subsystem. Call it once per subsystem, from a
+// package-level variable.
+//
+// Parameters:
+//   - subsystem (string): subsystem name, as used in log.levels
+//
+// Returns:
+//   - *Logger: the subsystem's logger
+func New(subsystem string) *Logger {
+	mu.Lock()
+	defer mu.Unlock()
+
+	l := &Logger{name: subsystem}
+	loggers = append(loggers, l)
+	return l
+}
+
+// SetDefaultLevel sets the level of subsystems without a configured level
+// and of the global logger. Call it after replacing log.Logger's output.
+//
+// Parameters:
+//   - level (zerolog.Level): default level
+func SetDefaultLevel(level zerolog.Level) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	defaultLevel = level
+	apply()
+}
+
+// Configure sets per-subsystem levels, replacing any set before.
+//
+// Parameters:
+//   - subsystemLevels (map[string]string): level name ("debug", "warn", ...) by subsystem
+//
+// Returns:
+//   - error: nil on success, error on an unknown level name
+func Configure(subsystemLevels map[string]string) error {
+	parsed := make(map[string]zerolog.Level, len(subsystemLevels))
+	for subsystem, name := range subsystemLevels {
+		level, err := zerolog.ParseLevel(name)
+		if err != nil {
+			return fmt.Errorf("log level of %s: %w", subsystem, err)
+		}
+		parsed[subsystem] = level
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	levels = parsed
+	apply()
+	return nil
+}
+
+// Level returns the effective level of a subsystem.
+//
+// Parameters:
+//   - subsystem (string): subsystem name
+//
+// Returns:
+//   - zerolog.Level: configured level, or the default level
+func Level(subsystem string) zerolog.Level {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if level, ok := levels[subsystem]; ok {
+		return level
+	}
+	return defaultLevel
+}
+
+// ConfiguredLevel returns the level configured for a subsystem, ignoring
+// the default level.
+//
+// Parameters:
+//   - subsystem (string): subsystem name
+//
+// Returns:
+//   - zerolog.Level: configured level
+//   - bool: false if the subsystem has no configured level
+func ConfiguredLevel(subsystem string) (zerolog.Level, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	level, ok := levels[subsystem]
+	return level, ok
+}
+
+// apply lowers zerolog's global level to the most verbose level in use, so
+// it doesn't filter a subsystem turned up, and drops cached loggers. Must be
+// called with mu held.
+func apply() {
+	lowest := defaultLevel
+	for _, level := range levels {
+		if level < lowest {
+			lowest = level
+		}
+	}
+	zerolog.SetGlobalLevel(lowest)
+	log.Logger = log.Logger.Level(defaultLevel)
+
+	for _, l := range loggers {
+		l.cached.Store(nil)
+	}
+}
+
+// logger returns the zerolog logger of the subsystem at its level.
+func (l *Logger) logger() *zerolog.Logger {
+	if cached := l.cached.Load(); cached != nil {
+		return cached
+	}
+
+	zl := log.Logger.With().Str("subsystem", l.name).Logger().Level(Level(l.name))
+	l.cached.Store(&zl)
+	return &zl
+}
+
+// Debug starts a debug level entry.
+func (l *Logger) Debug() *zerolog.Event { return l.logger().Debug() }
+
+// Info starts an info level entry.
+func (l *Logger) Info() *zerolog.Event { return l.logger().Info() }
+
+// Warn starts a warn level entry.
+func (l *Logger) Warn() *zerolog.Event { return l.logger().Warn() }
+
+// Error starts an error level entry.
+func (l *Logger) Error() *zerolog.Event { return l.logger().Error() }
@@ -0,0 +1,52 @@
+package logging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubsystemLevels(t *testing.T) {
+	saved := log.Logger
+	defer func() {
+		log.Logger = saved
+		require.NoError(t, Configure(nil))
+		SetDefaultLevel(zerolog.InfoLevel)
+	}()
+
+	var buf bytes.Buffer
+	log.Logger = zerolog.New(&buf)
+	SetDefaultLevel(zerolog.InfoLevel)
+	require.NoError(t, Configure(map[string]string{"rpc": "debug", "store": "warn"}))
+
+	rpc, store, engine := New("rpc"), New("store"), New("engine")
+	rpc.Debug().Msg("rpc debug")
+	store.Info().Msg("store info")
+	store.Warn().Msg("store warn")
+	engine.Debug().Msg("engine debug")
+	engine.Info().Msg("engine info")
+
+	out := buf.String()
+	require.Contains(t, out, "rpc debug")
+	require.Contains(t, out, `"subsystem":"rpc"`)
+	require.NotContains(t, out, "store info")
+	require.Contains(t, out, "store warn")
+	require.NotContains(t, out, "engine debug", "unconfigured subsystems use the default level")
+	require.Contains(t, out, "engine info")
+	require.Equal(t, 3, strings.Count(out, "\n"))
+
+	require.Equal(t, zerolog.WarnLevel, Level("store"))
+	require.Equal(t, zerolog.InfoLevel, Level("engine"))
+
+	level, ok := ConfiguredLevel("store")
+	require.True(t, ok)
+	require.Equal(t, zerolog.WarnLevel, level)
+	_, ok = ConfiguredLevel("engine")
+	require.False(t, ok, "the default level is not a configured level")
+
+	require.Error(t, Configure(map[string]string{"rpc": "loud"}))
+}
@@ -6,16 +6,41 @@ import (
 	"sync"
 
 	"github.com/google/uuid"
-	"github.com/rs/zerolog/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 
 	"github.com/0xredeth/Rafale/internal/api/graphql/model"
 )
 
+// DefaultEventBuffer is the number of events an event subscriber may fall
+// behind by before its oldest events are dropped.
+const DefaultEventBuffer = 100
+
+// subscriptionDrops counts messages dropped for subscribers too slow to
+// keep up.
+var subscriptionDrops = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "rafale_subscription_drops_total",
+		Help: "Total number of messages dropped for slow subscribers, by subscription type",
+	},
+	[]string{"type"},
+)
+
 // Broadcaster manages subscription channels for real-time event streaming.
 // It provides a thread-safe pub/sub mechanism for GraphQL subscriptions.
+//
+// Broadcasting never blocks on a slow consumer: every subscriber has a
+// bounded buffer, and once it is full the subscriber's oldest message is
+// dropped to make room for the new one (counted in
+// rafale_subscription_drops_total). A subscriber that can't keep up thus
+// misses messages but always sees the latest ones, and indexing
+// throughput doesn't depend on consumers.
 type Broadcaster struct {
 	mu sync.RWMutex
 
+	// Buffer size of new event subscriptions (see SetEventBuffer)
+	eventBuffer int
+
 	// Event subscriptions: subscriberID -> channel
 	eventSubs map[string]*eventSubscription
 
@@ -24,6 +49,15 @@ type Broadcaster struct {
 
 	// Sync status subscriptions: subscriberID -> channel
 	statusSubs map[string]chan *model.SyncStatus
+
+	// Sync state subscriptions: subscriberID -> channel
+	stateSubs map[string]chan *model.SyncStateChange
+
+	// Last sync state broadcast, sent to new state subscribers
+	lastState *model.SyncStateChange
+
+	// Holds events for in-order publishing (nil = publish immediately)
+	orderer *eventOrderer
 }
 
 // eventSubscription holds an event channel with optional filters.
@@ -39,15 +73,33 @@ type eventSubscription struct {
 //   - *Broadcaster: initialized broadcaster
 func NewBroadcaster() *Broadcaster {
 	return &Broadcaster{
-		eventSubs:  make(map[string]*eventSubscription),
-		blockSubs:  make(map[string]chan *model.Block),
-		statusSubs: make(map[string]chan *model.SyncStatus),
+		eventBuffer: DefaultEventBuffer,
+		eventSubs:   make(map[string]*eventSubscription),
+		blockSubs:   make(map[string]chan *model.Block),
+		statusSubs:  make(map[string]chan *model.SyncStatus),
+		stateSubs:   make(map[string]chan *model.SyncStateChange),
 	}
 }
 
+// SetEventBuffer sets the buffer size of event subscriptions created
+// afterwards by SubscribeEvents (server.subscription_buffer).
+//
+// Parameters:
+//   - size (int): events buffered per subscriber (values below 1 are ignored)
+func (b *Broadcaster) SetEventBuffer(size int) {
+	if size < 1 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.eventBuffer = size
+}
+
 // SubscribeEvents creates a new event subscription with optional filters.
-// The returned channel receives events matching the filters.
-// Call the returned cleanup function to unsubscribe.
+// The returned channel receives events matching the filters; others are
+// skipped before reaching it. Call the returned cleanup function to
+// unsubscribe: it closes and removes the channel.
 //
 // Parameters:
 //   - ctx (context.Context): context for automatic cleanup on cancellation
@@ -58,11 +110,32 @@ func NewBroadcaster() *Broadcaster {
 //   - <-chan *model.GenericEvent: channel receiving matching events
 //   - func(): cleanup function to call when done
 func (b *Broadcaster) SubscribeEvents(ctx context.Context, contract, eventName *string) (<-chan *model.GenericEvent, func()) {
+	b.mu.RLock()
+	buffer := b.eventBuffer
+	b.mu.RUnlock()
+
+	return b.SubscribeEventsWithBuffer(ctx, contract, eventName, buffer)
+}
+
+// SubscribeEventsWithBuffer is SubscribeEvents with its own buffer size,
+// for consumers expecting bursts larger than the default buffer. When the
+// buffer is full, the oldest buffered event is dropped for the newest.
+//
+// Parameters:
+//   - ctx (context.Context): context for automatic cleanup on cancellation
+//   - contract (*string): optional contract name filter
+//   - eventName (*string): optional event name filter
+//   - buffer (int): events buffered for this subscriber (at least 1)
+//
+// Returns:
+//   - <-chan *model.GenericEvent: channel receiving matching events
+//   - func(): cleanup function to call when done
+func (b *Broadcaster) SubscribeEventsWithBuffer(ctx context.Context, contract, eventName *string, buffer int) (<-chan *model.GenericEvent, func()) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
 	id := uuid.New().String()
-	ch := make(chan *model.GenericEvent, 100) // buffered to prevent blocking
+	ch := make(chan *model.GenericEvent, max(buffer, 1))
 
 	b.eventSubs[id] = &eventSubscription{
 		ch:        ch,
@@ -89,12 +162,7 @@ func (b *Broadcaster) SubscribeEvents(ctx context.Context, contract, eventName *
 	}
 
 	// Auto-cleanup on context cancellation
-	go func() {
-		<-ctx.Done()
-		cleanup()
-	}()
-
-	return ch, cleanup
+	return ch, cleanupOnDone(ctx, cleanup)
 }
 
 // SubscribeBlocks creates a new block subscription.
@@ -128,12 +196,7 @@ func (b *Broadcaster) SubscribeBlocks(ctx context.Context) (<-chan *model.Block,
 		}
 	}
 
-	go func() {
-		<-ctx.Done()
-		cleanup()
-	}()
-
-	return ch, cleanup
+	return ch, cleanupOnDone(ctx, cleanup)
 }
 
 // SubscribeSyncStatus creates a new sync status subscription.
@@ -167,21 +230,82 @@ func (b *Broadcaster) SubscribeSyncStatus(ctx context.Context) (<-chan *model.Sy
 		}
 	}
 
-	go func() {
-		<-ctx.Done()
-		cleanup()
-	}()
+	return ch, cleanupOnDone(ctx, cleanup)
+}
 
-	return ch, cleanup
+// SubscribeSyncState creates a new sync state subscription.
+// The returned channel receives the current state (if any) first, then
+// every transition between backfilling and synced.
+//
+// Parameters:
+//   - ctx (context.Context): context for automatic cleanup on cancellation
+//
+// Returns:
+//   - <-chan *model.SyncStateChange: channel receiving state changes
+//   - func(): cleanup function to call when done
+func (b *Broadcaster) SubscribeSyncState(ctx context.Context) (<-chan *model.SyncStateChange, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := uuid.New().String()
+	ch := make(chan *model.SyncStateChange, 10)
+	if b.lastState != nil {
+		ch <- b.lastState
+	}
+
+	b.stateSubs[id] = ch
+
+	log.Debug().Str("subscriberID", id).Msg("new sync state subscription")
+
+	cleanup := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		if existingCh, exists := b.stateSubs[id]; exists {
+			close(existingCh)
+			delete(b.stateSubs, id)
+			log.Debug().Str("subscriberID", id).Msg("sync state subscription removed")
+		}
+	}
+
+	return ch, cleanupOnDone(ctx, cleanup)
+}
+
+// cleanupOnDone runs a subscription's cleanup once ctx is cancelled. The
+// returned function runs it right away and stops watching ctx, so
+// unsubscribing early leaves no goroutine waiting on a long-lived context.
+//
+// Parameters:
+//   - ctx (context.Context): subscription context
+//   - cleanup (func()): closes and removes the subscription (idempotent)
+//
+// Returns:
+//   - func(): cleanup function to call when done
+func cleanupOnDone(ctx context.Context, cleanup func()) func() {
+	stop := context.AfterFunc(ctx, cleanup)
+	return func() {
+		stop()
+		cleanup()
+	}
 }
 
 // BroadcastEvent sends an event to all matching subscribers.
 // Events are filtered by contract and event name if specified by the subscriber.
-// Non-blocking: if a subscriber's buffer is full, the event is dropped for that subscriber.
+// Non-blocking: if a subscriber's buffer is full, its oldest event is dropped.
+// With EnableOrdering, the event is held until its block is released.
 //
 // Parameters:
 //   - event (*model.GenericEvent): the event to broadcast
 func (b *Broadcaster) BroadcastEvent(event *model.GenericEvent) {
+	if b.orderer != nil {
+		b.orderer.add(event)
+		return
+	}
+	b.publishEvent(event)
+}
+
+// publishEvent sends an event to all matching subscribers.
+func (b *Broadcaster) publishEvent(event *model.GenericEvent) {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 
@@ -194,20 +318,18 @@ func (b *Broadcaster) BroadcastEvent(event *model.GenericEvent) {
 			continue
 		}
 
-		// Non-blocking send
-		select {
-		case sub.ch <- event:
-		default:
+		if !sendDropOldest(sub.ch, event) {
+			subscriptionDrops.WithLabelValues("event").Inc()
 			log.Warn().
 				Str("subscriberID", id).
 				Str("eventName", event.EventName).
-				Msg("event subscription buffer full, dropping event")
+				Msg("event subscription buffer full, dropped oldest event")
 		}
 	}
 }
 
 // BroadcastBlock sends a block to all block subscribers.
-// Non-blocking: if a subscriber's buffer is full, the block is dropped for that subscriber.
+// Non-blocking: if a subscriber's buffer is full, its oldest block is dropped.
 //
 // Parameters:
 //   - block (*model.Block): the block to broadcast
@@ -216,19 +338,18 @@ func (b *Broadcaster) BroadcastBlock(block *model.Block) {
 	defer b.mu.RUnlock()
 
 	for id, ch := range b.blockSubs {
-		select {
-		case ch <- block:
-		default:
+		if !sendDropOldest(ch, block) {
+			subscriptionDrops.WithLabelValues("block").Inc()
 			log.Warn().
 				Str("subscriberID", id).
 				Str("blockNumber", block.Number).
-				Msg("block subscription buffer full, dropping block")
+				Msg("block subscription buffer full, dropped oldest block")
 		}
 	}
 }
 
 // BroadcastSyncStatus sends a sync status update to all status subscribers.
-// Non-blocking: if a subscriber's buffer is full, the status is dropped for that subscriber.
+// Non-blocking: if a subscriber's buffer is full, its oldest status is dropped.
 //
 // Parameters:
 //   - status (*model.SyncStatus): the status to broadcast
@@ -237,12 +358,33 @@ func (b *Broadcaster) BroadcastSyncStatus(status *model.SyncStatus) {
 	defer b.mu.RUnlock()
 
 	for id, ch := range b.statusSubs {
-		select {
-		case ch <- status:
-		default:
+		if !sendDropOldest(ch, status) {
+			subscriptionDrops.WithLabelValues("sync_status").Inc()
+			log.Warn().
+				Str("subscriberID", id).
+				Msg("sync status subscription buffer full, dropped oldest status")
+		}
+	}
+}
+
+// BroadcastSyncState sends a sync state change to all state subscribers
+// and remembers it for subscribers joining later.
+// Non-blocking: if a subscriber's buffer is full, its oldest change is dropped.
+//
+// Parameters:
+//   - state (*model.SyncStateChange): the state change to broadcast
+func (b *Broadcaster) BroadcastSyncState(state *model.SyncStateChange) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastState = state
+	for id, ch := range b.stateSubs {
+		if !sendDropOldest(ch, state) {
+			subscriptionDrops.WithLabelValues("sync_state").Inc()
 			log.Warn().
 				Str("subscriberID", id).
-				Msg("sync status subscription buffer full, dropping status")
+				Str("state", state.Type).
+				Msg("sync state subscription buffer full, dropped oldest state change")
 		}
 	}
 }
@@ -259,3 +401,31 @@ func (b *Broadcaster) SubscriberCount() (events, blocks, status int) {
 
 	return len(b.eventSubs), len(b.blockSubs), len(b.statusSubs)
 }
+
+// sendDropOldest sends v on ch without blocking. If ch's buffer is full,
+// its oldest value is discarded to make room. Should concurrent senders
+// refill the freed slot first, v itself is discarded.
+//
+// Parameters:
+//   - ch (chan T): subscriber channel
+//   - v (T): value to send
+//
+// Returns:
+//   - bool: true if sent without dropping anything
+func sendDropOldest[T any](ch chan T, v T) bool {
+	select {
+	case ch <- v:
+		return true
+	default:
+	}
+
+	select {
+	case <-ch:
+	default:
+	}
+	select {
+	case ch <- v:
+	default:
+	}
+	return false
+}
@@ -0,0 +1,250 @@
+package pubsub
+
+import (
+	"context"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xredeth/Rafale/internal/api/graphql/model"
+)
+
+func TestSendDropOldest(t *testing.T) {
+	tests := []struct {
+		name     string
+		buffer   int
+		sends    []int
+		wantOK   []bool
+		received []int
+	}{
+		{name: "room in buffer", buffer: 3, sends: []int{1, 2}, wantOK: []bool{true, true}, received: []int{1, 2}},
+		{name: "full buffer drops oldest", buffer: 2, sends: []int{1, 2, 3}, wantOK: []bool{true, true, false}, received: []int{2, 3}},
+		{name: "keeps the newest", buffer: 2, sends: []int{1, 2, 3, 4, 5}, wantOK: []bool{true, true, false, false, false}, received: []int{4, 5}},
+		{name: "single slot", buffer: 1, sends: []int{1, 2}, wantOK: []bool{true, false}, received: []int{2}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ch := make(chan int, tc.buffer)
+			for i, v := range tc.sends {
+				require.Equal(t, tc.wantOK[i], sendDropOldest(ch, v), "send %d", v)
+			}
+			close(ch)
+
+			var got []int
+			for v := range ch {
+				got = append(got, v)
+			}
+			require.Equal(t, tc.received, got)
+		})
+	}
+}
+
+func TestSubscribeEventsWithBuffer(t *testing.T) {
+	usdc := "USDC"
+
+	tests := []struct {
+		name      string
+		buffer    int
+		contract  *string
+		events    int
+		received  []string
+		wantDrops float64
+	}{
+		{name: "within buffer", buffer: 4, events: 3, received: []string{"0", "1", "2"}},
+		{name: "oldest dropped", buffer: 2, events: 5, received: []string{"3", "4"}, wantDrops: 3},
+		{name: "buffer below one holds one", buffer: 0, events: 3, received: []string{"2"}, wantDrops: 2},
+		{name: "filtered events are not dropped", buffer: 1, contract: &usdc, events: 3, received: nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b := NewBroadcaster()
+			ch, cleanup := b.SubscribeEventsWithBuffer(context.Background(), tc.contract, nil, tc.buffer)
+
+			drops := testutil.ToFloat64(subscriptionDrops.WithLabelValues("event"))
+			for i := range tc.events {
+				b.BroadcastEvent(&model.GenericEvent{ID: strconv.Itoa(i), Contract: "WETH", EventName: "Transfer"})
+			}
+			require.Equal(t, tc.wantDrops, testutil.ToFloat64(subscriptionDrops.WithLabelValues("event"))-drops)
+
+			cleanup()
+			var got []string
+			for event := range ch {
+				got = append(got, event.ID)
+			}
+			require.Equal(t, tc.received, got)
+		})
+	}
+}
+
+func TestSetEventBuffer(t *testing.T) {
+	b := NewBroadcaster()
+	b.SetEventBuffer(2)
+	b.SetEventBuffer(0) // ignored
+
+	ch, cleanup := b.SubscribeEvents(context.Background(), nil, nil)
+	defer cleanup()
+	require.Equal(t, 2, cap(ch))
+}
+
+func TestUnsubscribeEarly(t *testing.T) {
+	b := NewBroadcaster()
+	baseline := runtime.NumGoroutine()
+
+	// Long-lived context: unsubscribing must not leave anything waiting on it
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var cleanups []func()
+	for range 50 {
+		_, unsubEvents := b.SubscribeEvents(ctx, nil, nil)
+		_, unsubBlocks := b.SubscribeBlocks(ctx)
+		_, unsubStatus := b.SubscribeSyncStatus(ctx)
+		_, unsubState := b.SubscribeSyncState(ctx)
+		cleanups = append(cleanups, unsubEvents, unsubBlocks, unsubStatus, unsubState)
+	}
+	events, blocks, status := b.SubscriberCount()
+	require.Equal(t, []int{50, 50, 50}, []int{events, blocks, status})
+
+	for _, cleanup := range cleanups {
+		cleanup()
+		cleanup() // idempotent
+	}
+	events, blocks, status = b.SubscriberCount()
+	require.Equal(t, []int{0, 0, 0}, []int{events, blocks, status})
+	require.Empty(t, b.stateSubs)
+
+	// Polled inline: require.Eventually runs its condition on a goroutine
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > baseline && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.LessOrEqual(t, runtime.NumGoroutine(), baseline)
+}
+
+func TestUnsubscribeOnCancel(t *testing.T) {
+	b := NewBroadcaster()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, _ := b.SubscribeEvents(ctx, nil, nil)
+	cancel()
+
+	// The channel is closed once the subscription is removed
+	_, open := <-ch
+	require.False(t, open)
+	events, _, _ := b.SubscriberCount()
+	require.Zero(t, events)
+}
+
+// orderedEventAt builds a broadcast event at a block and log index.
+func orderedEventAt(block uint64, logIndex int) *model.GenericEvent {
+	return &model.GenericEvent{
+		ID:          strconv.FormatUint(block, 10) + "/" + strconv.Itoa(logIndex),
+		BlockNumber: strconv.FormatUint(block, 10),
+		LogIndex:    logIndex,
+		Contract:    "USDC",
+		EventName:   "Transfer",
+	}
+}
+
+// receivedEvents returns the IDs of the events waiting on ch.
+func receivedEvents(ch <-chan *model.GenericEvent) []string {
+	var got []string
+	for {
+		select {
+		case event := <-ch:
+			got = append(got, event.ID)
+		default:
+			return got
+		}
+	}
+}
+
+func TestOrderingHoldsUntilRelease(t *testing.T) {
+	b := NewBroadcaster()
+	b.EnableOrdering(time.Hour)
+	ch, cleanup := b.SubscribeEvents(context.Background(), nil, nil)
+	defer cleanup()
+
+	b.BroadcastEvent(orderedEventAt(101, 0))
+	b.BroadcastEvent(orderedEventAt(100, 1))
+	b.BroadcastEvent(orderedEventAt(100, 0))
+	require.Empty(t, receivedEvents(ch))
+
+	b.ReleaseEvents(100)
+	require.Equal(t, []string{"100/0", "100/1"}, receivedEvents(ch))
+
+	b.ReleaseEvents(101)
+	require.Equal(t, []string{"101/0"}, receivedEvents(ch))
+}
+
+func TestOrderingTimeoutFlush(t *testing.T) {
+	b := NewBroadcaster()
+	b.EnableOrdering(20 * time.Millisecond)
+	ch, cleanup := b.SubscribeEvents(context.Background(), nil, nil)
+	defer cleanup()
+
+	b.BroadcastEvent(orderedEventAt(101, 0))
+	b.BroadcastEvent(orderedEventAt(100, 0))
+	require.Empty(t, receivedEvents(ch))
+
+	// Never released: published in order once held for the timeout
+	var got []string
+	require.Eventually(t, func() bool {
+		got = append(got, receivedEvents(ch)...)
+		return len(got) == 2
+	}, time.Second, 5*time.Millisecond)
+	require.Equal(t, []string{"100/0", "101/0"}, got)
+}
+
+func TestOrderingLateEvent(t *testing.T) {
+	b := NewBroadcaster()
+	b.EnableOrdering(time.Hour)
+	ch, cleanup := b.SubscribeEvents(context.Background(), nil, nil)
+	defer cleanup()
+
+	b.BroadcastEvent(orderedEventAt(101, 0))
+	b.ReleaseEvents(101)
+	require.Equal(t, []string{"101/0"}, receivedEvents(ch))
+
+	// Behind the last published event: published right away, out of order
+	b.BroadcastEvent(orderedEventAt(100, 5))
+	require.Equal(t, []string{"100/5"}, receivedEvents(ch))
+
+	// Ahead of it: held as usual
+	b.BroadcastEvent(orderedEventAt(101, 1))
+	require.Empty(t, receivedEvents(ch))
+	b.ReleaseEvents(101)
+	require.Equal(t, []string{"101/1"}, receivedEvents(ch))
+}
+
+func TestOrderingRewind(t *testing.T) {
+	b := NewBroadcaster()
+	b.EnableOrdering(time.Hour)
+	ch, cleanup := b.SubscribeEvents(context.Background(), nil, nil)
+	defer cleanup()
+
+	b.BroadcastEvent(orderedEventAt(100, 0))
+	b.BroadcastEvent(orderedEventAt(101, 0))
+	b.ReleaseEvents(101)
+	b.BroadcastEvent(orderedEventAt(102, 0))
+	require.Equal(t, []string{"100/0", "101/0"}, receivedEvents(ch))
+
+	// Rolled back to 100: the held 102 event is dropped, and the
+	// re-broadcast 101 events are ordered again
+	b.RewindEvents(100)
+	b.BroadcastEvent(orderedEventAt(101, 1))
+	b.BroadcastEvent(orderedEventAt(101, 0))
+	require.Empty(t, receivedEvents(ch))
+
+	b.ReleaseEvents(102)
+	require.Equal(t, []string{"101/0", "101/1"}, receivedEvents(ch))
+
+	// Without ordering it's a no-op
+	NewBroadcaster().RewindEvents(100)
+}
@@ -0,0 +1,6 @@
+package pubsub
+
+import "github.com/0xredeth/Rafale/internal/logging"
+
+// log is the pubsub subsystem's logger (level set by log.levels.pubsub).
+var log = logging.New("pubsub")
@@ -0,0 +1,184 @@
+package pubsub
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/0xredeth/Rafale/internal/api/graphql/model"
+)
+
+// eventOrderer holds broadcast events until their block is released, then
+// publishes them in (block number, log index) order. Events are published
+// without a release once held for the timeout, so a block that is never
+// released doesn't stall the stream.
+type eventOrderer struct {
+	timeout time.Duration
+	publish func(*model.GenericEvent)
+
+	mu      sync.Mutex
+	pending []orderedEvent // sorted by position, then arrival
+	last    eventPosition  // position of the last published event
+	timer   *time.Timer
+}
+
+// orderedEvent is a held event.
+type orderedEvent struct {
+	pos     eventPosition
+	event   *model.GenericEvent
+	expires time.Time
+}
+
+// eventPosition is the (block number, log index) position of an event.
+type eventPosition struct {
+	block    uint64
+	logIndex int
+}
+
+// less reports whether p comes before other.
+func (p eventPosition) less(other eventPosition) bool {
+	if p.block != other.block {
+		return p.block < other.block
+	}
+	return p.logIndex < other.logIndex
+}
+
+// EnableOrdering makes BroadcastEvent hold events and publish them in strict
+// (block number, log index) order as blocks are released with ReleaseEvents.
+// Held events are published in order without a release after timeout.
+// Call it before broadcasting.
+//
+// Parameters:
+//   - timeout (time.Duration): maximum time an event is held
+func (b *Broadcaster) EnableOrdering(timeout time.Duration) {
+	b.orderer = &eventOrderer{timeout: timeout, publish: b.publishEvent}
+}
+
+// ReleaseEvents publishes, in order, the held events up to and including
+// a block once every event of those blocks has been broadcast. No-op
+// without EnableOrdering.
+//
+// Parameters:
+//   - block (uint64): last block whose events are complete
+func (b *Broadcaster) ReleaseEvents(block uint64) {
+	if b.orderer == nil {
+		return
+	}
+	b.orderer.release(func(ev orderedEvent) bool { return ev.pos.block <= block })
+}
+
+// RewindEvents forgets events after a block after a reorg rolled it back:
+// held events of the rolled back blocks are dropped, and the re-broadcast
+// events of those blocks are ordered again rather than published as late.
+// No-op without EnableOrdering.
+//
+// Parameters:
+//   - block (uint64): common ancestor the chain was rolled back to
+func (b *Broadcaster) RewindEvents(block uint64) {
+	if b.orderer == nil {
+		return
+	}
+	b.orderer.rewind(block)
+}
+
+// add holds an event, or publishes it right away if an event after it was
+// already published (ordering can't be restored).
+func (o *eventOrderer) add(event *model.GenericEvent) {
+	block, _ := strconv.ParseUint(event.BlockNumber, 10, 64)
+	pos := eventPosition{block: block, logIndex: event.LogIndex}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if pos.less(o.last) {
+		log.Warn().
+			Uint64("block", pos.block).
+			Int("logIndex", pos.logIndex).
+			Uint64("lastBlock", o.last.block).
+			Msg("event broadcast after later events, publishing out of order")
+		o.publish(event)
+		return
+	}
+
+	// Insert after events at the same position, keeping arrival order
+	i := sort.Search(len(o.pending), func(i int) bool { return pos.less(o.pending[i].pos) })
+	o.pending = append(o.pending, orderedEvent{})
+	copy(o.pending[i+1:], o.pending[i:])
+	o.pending[i] = orderedEvent{pos: pos, event: event, expires: time.Now().Add(o.timeout)}
+
+	if o.timer == nil {
+		o.timer = time.AfterFunc(o.timeout, o.expire)
+	}
+}
+
+// expire publishes the held events that timed out, with the events before
+// them, and re-arms the timer for the next expiry.
+func (o *eventOrderer) expire() {
+	o.mu.Lock()
+	now := time.Now()
+	var through *eventPosition
+	for _, ev := range o.pending {
+		if !ev.expires.After(now) {
+			through = &ev.pos
+		}
+	}
+	o.mu.Unlock()
+
+	if through != nil {
+		log.Warn().
+			Uint64("block", through.block).
+			Dur("timeout", o.timeout).
+			Msg("event ordering timed out waiting for block release, publishing held events")
+		o.release(func(ev orderedEvent) bool { return !through.less(ev.pos) })
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.timer = nil
+	if len(o.pending) > 0 {
+		next := o.pending[0].expires
+		for _, ev := range o.pending[1:] {
+			if ev.expires.Before(next) {
+				next = ev.expires
+			}
+		}
+		o.timer = time.AfterFunc(time.Until(next), o.expire)
+	}
+}
+
+// release publishes the leading held events matching ready, in order.
+func (o *eventOrderer) release(ready func(orderedEvent) bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	n := 0
+	for n < len(o.pending) && ready(o.pending[n]) {
+		o.publish(o.pending[n].event)
+		o.last = o.pending[n].pos
+		n++
+	}
+	o.pending = append(o.pending[:0], o.pending[n:]...)
+}
+
+// rewind drops held events after block and moves the last published
+// position back to the end of block.
+func (o *eventOrderer) rewind(block uint64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	i := sort.Search(len(o.pending), func(i int) bool { return o.pending[i].pos.block > block })
+	if dropped := len(o.pending) - i; dropped > 0 {
+		log.Warn().
+			Uint64("block", block).
+			Int("dropped", dropped).
+			Msg("dropped held events of rolled back blocks")
+	}
+	o.pending = o.pending[:i]
+
+	if o.last.block > block {
+		o.last = eventPosition{block: block, logIndex: math.MaxInt}
+	}
+}
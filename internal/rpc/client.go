@@ -13,7 +13,6 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
-	"github.com/rs/zerolog/log"
 	"github.com/sony/gobreaker"
 )
 
@@ -36,6 +35,13 @@ var (
 		[]string{"method", "status"},
 	)
 
+	rpcTruncatedLogs = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "rafale_rpc_truncated_logs_total",
+			Help: "getLogs responses detected as silently truncated and re-fetched in smaller ranges",
+		},
+	)
+
 	circuitBreakerState = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "rafale_circuit_breaker_state",
@@ -45,12 +51,46 @@ var (
 	)
 )
 
+// EthClient is the set of RPC operations the sync engine depends on.
+// *Client satisfies it; tests can substitute a fake returning canned
+// heads, headers and logs.
+type EthClient interface {
+	// ChainID returns the chain ID.
+	ChainID() *big.Int
+
+	// BlockNumber returns the current head block number.
+	BlockNumber(ctx context.Context) (uint64, error)
+
+	// BlockByNumber returns a block by number (nil for latest).
+	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
+
+	// HeaderByNumber returns a block header by number (nil for latest).
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+
+	// FilterLogs returns logs matching a single filter query.
+	FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error)
+
+	// FetchLogs returns logs for a block range, splitting on range errors.
+	FetchLogs(ctx context.Context, addresses []common.Address, topics [][]common.Hash, fromBlock, toBlock uint64) ([]types.Log, error)
+
+	// Close releases the underlying connection.
+	Close()
+}
+
+// Compile-time check that Client implements EthClient.
+var _ EthClient = (*Client)(nil)
+
 // Client wraps an Ethereum client with circuit breaker and metrics.
 type Client struct {
 	eth     *ethclient.Client
 	cb      *gobreaker.CircuitBreaker
 	chainID *big.Int
 	url     string
+	wsURL   string        // "" = no newHeads subscription
+	sem     chan struct{} // nil when concurrency is unlimited
+
+	classifier RetryClassifier // nil = built-in classification only
+	verifyLogs bool
 }
 
 // ClientConfig holds RPC client configuration.
@@ -58,6 +98,10 @@ type ClientConfig struct {
 	// URL is the RPC endpoint URL.
 	URL string
 
+	// WSURL is a ws:// or wss:// endpoint used by Subscribe for newHeads
+	// ("" = no subscription; callers poll instead).
+	WSURL string
+
 	// Timeout is the request timeout.
 	Timeout time.Duration
 
@@ -66,6 +110,26 @@ type ClientConfig struct {
 
 	// CircuitBreaker holds circuit breaker settings.
 	CircuitBreaker CircuitBreakerConfig
+
+	// MaxConcurrentPerEndpoint caps simultaneous in-flight requests to the
+	// endpoint (0 = unlimited). Callers beyond the cap wait for a slot.
+	MaxConcurrentPerEndpoint int
+
+	// MaxResponseBytes caps the size of a single response (0 = transport
+	// default). Larger responses fail with ErrResponseTooLarge, which
+	// FetchLogs handles by splitting the block range.
+	MaxResponseBytes int64
+
+	// RetryClassifier classifies request errors before the built-in
+	// rules, e.g. to recognize a provider's wording for oversized ranges
+	// or throttling (nil = built-in only).
+	RetryClassifier RetryClassifier
+
+	// VerifyLogRanges cross-checks getLogs responses for silent
+	// truncation: when the last log ends before the range does, the tail
+	// is re-fetched, and a fuller tail causes a re-fetch in smaller
+	// ranges. Costs one extra request per such range.
+	VerifyLogRanges bool
 }
 
 // CircuitBreakerConfig holds circuit breaker settings.
@@ -110,10 +174,17 @@ func DefaultConfig() ClientConfig {
 //   - *Client: the initialized client
 //   - error: nil on success, connection error on failure
 func New(ctx context.Context, cfg ClientConfig) (*Client, error) {
-	eth, err := ethclient.DialContext(ctx, cfg.URL)
+	if cfg.WSURL != "" {
+		if err := validateWSURL(cfg.WSURL); err != nil {
+			return nil, err
+		}
+	}
+
+	rpcClient, err := dial(ctx, cfg.URL, cfg.MaxResponseBytes)
 	if err != nil {
 		return nil, fmt.Errorf("connecting to RPC: %w", err)
 	}
+	eth := ethclient.NewClient(rpcClient)
 
 	chainID, err := eth.ChainID(ctx)
 	if err != nil {
@@ -121,6 +192,15 @@ func New(ctx context.Context, cfg ClientConfig) (*Client, error) {
 		return nil, fmt.Errorf("getting chain ID: %w", err)
 	}
 
+	c := &Client{
+		eth:        eth,
+		chainID:    chainID,
+		url:        cfg.URL,
+		wsURL:      cfg.WSURL,
+		classifier: cfg.RetryClassifier,
+		verifyLogs: cfg.VerifyLogRanges,
+	}
+
 	// Configure circuit breaker
 	cbSettings := gobreaker.Settings{
 		Name:        "linea-rpc",
@@ -130,6 +210,9 @@ func New(ctx context.Context, cfg ClientConfig) (*Client, error) {
 		ReadyToTrip: func(counts gobreaker.Counts) bool {
 			return counts.ConsecutiveFailures >= cfg.CircuitBreaker.FailureThreshold
 		},
+		IsSuccessful: func(err error) bool {
+			return !c.countsAsFailure(err)
+		},
 		OnStateChange: func(name string, from gobreaker.State, to gobreaker.State) {
 			log.Warn().
 				Str("name", name).
@@ -151,19 +234,44 @@ func New(ctx context.Context, cfg ClientConfig) (*Client, error) {
 		},
 	}
 
-	cb := gobreaker.NewCircuitBreaker(cbSettings)
+	c.cb = gobreaker.NewCircuitBreaker(cbSettings)
 
 	log.Info().
 		Str("url", cfg.URL).
 		Uint64("chainID", chainID.Uint64()).
 		Msg("connected to Linea RPC")
 
-	return &Client{
-		eth:     eth,
-		cb:      cb,
-		chainID: chainID,
-		url:     cfg.URL,
-	}, nil
+	if cfg.MaxConcurrentPerEndpoint > 0 {
+		c.sem = make(chan struct{}, cfg.MaxConcurrentPerEndpoint)
+	}
+
+	return c, nil
+}
+
+// acquire waits for a concurrency slot, respecting ctx cancellation.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//
+// Returns:
+//   - error: nil when a slot is held, ctx error if cancelled while waiting
+func (c *Client) acquire(ctx context.Context) error {
+	if c.sem == nil {
+		return nil
+	}
+	select {
+	case c.sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees a concurrency slot taken by acquire.
+func (c *Client) release() {
+	if c.sem != nil {
+		<-c.sem
+	}
 }
 
 // Close closes the RPC connection.
@@ -188,6 +296,11 @@ func (c *Client) ChainID() *big.Int {
 //   - uint64: current block number
 //   - error: nil on success, RPC error on failure
 func (c *Client) BlockNumber(ctx context.Context) (uint64, error) {
+	if err := c.acquire(ctx); err != nil {
+		return 0, fmt.Errorf("getting block number: %w", err)
+	}
+	defer c.release()
+
 	start := time.Now()
 
 	result, err := c.cb.Execute(func() (interface{}, error) {
@@ -216,6 +329,11 @@ func (c *Client) BlockNumber(ctx context.Context) (uint64, error) {
 //   - *types.Block: the block
 //   - error: nil on success, RPC error on failure
 func (c *Client) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, fmt.Errorf("getting block: %w", err)
+	}
+	defer c.release()
+
 	start := time.Now()
 
 	result, err := c.cb.Execute(func() (interface{}, error) {
@@ -244,6 +362,11 @@ func (c *Client) BlockByNumber(ctx context.Context, number *big.Int) (*types.Blo
 //   - []types.Log: matching logs
 //   - error: nil on success, RPC error on failure
 func (c *Client) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, fmt.Errorf("filtering logs: %w", err)
+	}
+	defer c.release()
+
 	start := time.Now()
 
 	result, err := c.cb.Execute(func() (interface{}, error) {
@@ -272,6 +395,11 @@ func (c *Client) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]
 //   - *types.Header: the block header
 //   - error: nil on success, RPC error on failure
 func (c *Client) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, fmt.Errorf("getting header: %w", err)
+	}
+	defer c.release()
+
 	start := time.Now()
 
 	result, err := c.cb.Execute(func() (interface{}, error) {
@@ -300,6 +428,11 @@ func (c *Client) HeaderByNumber(ctx context.Context, number *big.Int) (*types.He
 //   - *types.Receipt: the transaction receipt
 //   - error: nil on success, RPC error on failure
 func (c *Client) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, fmt.Errorf("getting receipt: %w", err)
+	}
+	defer c.release()
+
 	start := time.Now()
 
 	result, err := c.cb.Execute(func() (interface{}, error) {
@@ -2,10 +2,21 @@ package rpc
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
 	"github.com/stretchr/testify/require"
 )
 
@@ -232,7 +243,12 @@ func TestIsRangeTooLargeErrorEdgeCases(t *testing.T) {
 		{
 			name: "alchemy style error",
 			err:  errors.New("Log response size exceeded. You can make eth_getLogs requests with up to a 2K block range"),
-			want: false, // Not matching current patterns
+			want: true,
+		},
+		{
+			name: "ankr style error",
+			err:  errors.New("query exceeds max block range 1000"),
+			want: true,
 		},
 		{
 			name: "infura style - query returned more than 10000 results",
@@ -299,3 +315,437 @@ func TestClientConfigWithCustomValues(t *testing.T) {
 	require.Equal(t, 10, cfg.MaxRetries)
 	require.Equal(t, uint32(20), cfg.CircuitBreaker.MaxRequests)
 }
+
+func TestClientConcurrencyCap(t *testing.T) {
+	c := &Client{sem: make(chan struct{}, 2)}
+	ctx := context.Background()
+
+	require.NoError(t, c.acquire(ctx))
+	require.NoError(t, c.acquire(ctx))
+
+	// Third caller waits and gives up when its context expires
+	waitCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	err := c.acquire(waitCtx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// Releasing a slot lets the next caller through
+	c.release()
+	require.NoError(t, c.acquire(ctx))
+}
+
+func TestClientConcurrencyUnlimited(t *testing.T) {
+	c := &Client{}
+
+	for i := 0; i < 100; i++ {
+		require.NoError(t, c.acquire(context.Background()))
+	}
+	c.release() // no-op without a semaphore
+}
+
+func TestBlockMayContain(t *testing.T) {
+	ours := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	other := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	transferSig := common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	approvalSig := common.HexToHash("0x8c5be1e5ebec7d5bd14f71427b1e84f3dd0314c0f7b2291e5b200ac8c7c3b925")
+
+	// Block with a single Transfer log from our contract
+	bloom := types.CreateBloom(&types.Receipt{
+		Logs: []*types.Log{{Address: ours, Topics: []common.Hash{transferSig}}},
+	})
+	header := &types.Header{Bloom: bloom}
+	empty := &types.Header{}
+
+	tests := []struct {
+		name   string
+		header *types.Header
+		addrs  []common.Address
+		topics [][]common.Hash
+		want   bool
+	}{
+		{"matching address and topic", header, []common.Address{ours}, [][]common.Hash{{transferSig}}, true},
+		{"any address matches", header, []common.Address{other, ours}, nil, true},
+		{"any topic in position matches", header, nil, [][]common.Hash{{approvalSig, transferSig}}, true},
+		{"no filter", header, nil, nil, true},
+		{"empty position is wildcard", header, []common.Address{ours}, [][]common.Hash{{transferSig}, {}}, true},
+		{"nil header", nil, []common.Address{ours}, nil, true},
+		{"address absent", header, []common.Address{other}, nil, false},
+		{"topic absent", header, []common.Address{ours}, [][]common.Hash{{approvalSig}}, false},
+		{"empty block", empty, []common.Address{ours}, [][]common.Hash{{transferSig}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, BlockMayContain(tt.header, tt.addrs, tt.topics))
+		})
+	}
+}
+
+func TestClientMaxResponseBytes(t *testing.T) {
+	// Answers eth_chainId compactly and pads every other response past the limit
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		padding := ""
+		if req.Method != "eth_chainId" {
+			padding = strings.Repeat(" ", 4096)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":"0x1"%s}`, req.ID, padding)
+	}))
+	defer srv.Close()
+
+	cfg := DefaultConfig()
+	cfg.URL = srv.URL
+	cfg.MaxResponseBytes = 1024
+
+	client, err := New(context.Background(), cfg)
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.BlockNumber(context.Background())
+	require.Error(t, err)
+	require.True(t, isRangeTooLargeError(err), "got %v", err)
+}
+
+func TestIsRangeTooLargeErrorWrapped(t *testing.T) {
+	require.True(t, isRangeTooLargeError(fmt.Errorf("eth_getLogs: %w", ErrResponseTooLarge)))
+}
+
+func TestClassify(t *testing.T) {
+	custom := func(err error) RetryAction {
+		if strings.Contains(err.Error(), "block span exceeds") {
+			return RangeTooLarge
+		}
+		if strings.Contains(err.Error(), "rate limit exceeded") {
+			return RateLimited
+		}
+		return Unclassified
+	}
+
+	tests := []struct {
+		name       string
+		classifier RetryClassifier
+		err        error
+		want       RetryAction
+	}{
+		{"nil error", nil, nil, Unclassified},
+		{"built-in range", nil, errors.New("query returned more than 10000 results"), RangeTooLarge},
+		{"built-in response size", nil, fmt.Errorf("eth_getLogs: %w", ErrResponseTooLarge), RangeTooLarge},
+		{"built-in rate limit", nil, errors.New("429 Too Many Requests"), RateLimited},
+		{"built-in cancellation", nil, fmt.Errorf("getting block: %w", context.Canceled), Fatal},
+		{"built-in default", nil, errors.New("connection reset by peer"), Retry},
+		{"built-in unknown filter", nil, errors.New("filter not found"), Fatal},
+		{"built-in revert", nil, errors.New("execution reverted: insufficient balance"), Fatal},
+		{"unknown wording without classifier", nil, errors.New("block span exceeds 5000"), Retry},
+		{"classifier teaches new wording", custom, errors.New("block span exceeds 5000"), RangeTooLarge},
+		{"classifier overrides built-in", custom, errors.New("rate limit exceeded"), RateLimited},
+		{"classifier defers", custom, errors.New("connection reset by peer"), Retry},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{classifier: tt.classifier}
+			require.Equal(t, tt.want, c.Classify(tt.err))
+		})
+	}
+}
+
+func TestFetchLogsSplitsOnClassifiedError(t *testing.T) {
+	// Refuses getLogs spans over 10 blocks with a provider-specific message
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		w.Header().Set("Content-Type", "application/json")
+
+		if req.Method == "eth_chainId" {
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":"0x1"}`, req.ID)
+			return
+		}
+
+		calls.Add(1)
+		var filter struct {
+			FromBlock hexutil.Uint64 `json:"fromBlock"`
+			ToBlock   hexutil.Uint64 `json:"toBlock"`
+		}
+		require.NoError(t, json.Unmarshal(req.Params[0], &filter))
+		if filter.ToBlock-filter.FromBlock >= 10 {
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":-32000,"message":"block span exceeds 10"}}`, req.ID)
+			return
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":[]}`, req.ID)
+	}))
+	defer srv.Close()
+
+	cfg := DefaultConfig()
+	cfg.URL = srv.URL
+
+	client, err := New(context.Background(), cfg)
+	require.NoError(t, err)
+	_, err = client.FetchLogs(context.Background(), nil, nil, 0, 15)
+	require.ErrorContains(t, err, "block span exceeds")
+	client.Close()
+
+	cfg.RetryClassifier = func(err error) RetryAction {
+		if strings.Contains(err.Error(), "block span exceeds") {
+			return RangeTooLarge
+		}
+		return Unclassified
+	}
+	client, err = New(context.Background(), cfg)
+	require.NoError(t, err)
+	defer client.Close()
+
+	calls.Store(0)
+	_, err = client.FetchLogs(context.Background(), nil, nil, 0, 15)
+	require.NoError(t, err)
+	require.Equal(t, int32(3), calls.Load()) // 0-15 refused, then 0-7 and 8-15
+}
+
+func TestFetchLogsSplitsAlchemyRange(t *testing.T) {
+	// Refuses spans over 2 blocks with Alchemy's wording; one log per block otherwise
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		w.Header().Set("Content-Type", "application/json")
+
+		if req.Method == "eth_chainId" {
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":"0x1"}`, req.ID)
+			return
+		}
+
+		var filter struct {
+			FromBlock hexutil.Uint64 `json:"fromBlock"`
+			ToBlock   hexutil.Uint64 `json:"toBlock"`
+		}
+		require.NoError(t, json.Unmarshal(req.Params[0], &filter))
+		if filter.ToBlock-filter.FromBlock >= 2 {
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":-32602,"message":"Log response size exceeded. You can make eth_getLogs requests with up to a 2K block range and no limit on the response size"}}`, req.ID)
+			return
+		}
+
+		logs := make([]string, 0, 2)
+		for n := filter.FromBlock; n <= filter.ToBlock; n++ {
+			logs = append(logs, fmt.Sprintf(`{"address":"0x0000000000000000000000000000000000000001","topics":[],"data":"0x","blockNumber":"%s","transactionHash":"0x%064x","transactionIndex":"0x0","blockHash":"0x%064x","logIndex":"0x0","removed":false}`, hexutil.Uint64(n), n, n))
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":[%s]}`, req.ID, strings.Join(logs, ","))
+	}))
+	defer srv.Close()
+
+	cfg := DefaultConfig()
+	cfg.URL = srv.URL
+	client, err := New(context.Background(), cfg)
+	require.NoError(t, err)
+	defer client.Close()
+
+	logs, err := client.FetchLogs(context.Background(), nil, nil, 0, 9)
+	require.NoError(t, err)
+	require.Len(t, logs, 10)
+	for i, l := range logs {
+		require.Equal(t, uint64(i), l.BlockNumber)
+	}
+}
+
+// headsService serves eth_chainId and a newHeads subscription fed by headers.
+type headsService struct {
+	headers chan *types.Header
+}
+
+func (s *headsService) ChainId() *hexutil.Big { //nolint:revive // matches the eth_chainId method name
+	return (*hexutil.Big)(big.NewInt(1))
+}
+
+func (s *headsService) NewHeads(ctx context.Context) (*gethrpc.Subscription, error) {
+	notifier, ok := gethrpc.NotifierFromContext(ctx)
+	if !ok {
+		return nil, gethrpc.ErrNotificationsUnsupported
+	}
+	sub := notifier.CreateSubscription()
+	go func() {
+		for header := range s.headers {
+			_ = notifier.Notify(sub.ID, header)
+		}
+	}()
+	return sub, nil
+}
+
+func TestSubscribeNewHeads(t *testing.T) {
+	svc := &headsService{headers: make(chan *types.Header)}
+	rpcSrv := gethrpc.NewServer()
+	require.NoError(t, rpcSrv.RegisterName("eth", svc))
+	defer rpcSrv.Stop()
+
+	ws := rpcSrv.WebsocketHandler([]string{"*"})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") == "websocket" {
+			ws.ServeHTTP(w, r)
+			return
+		}
+		rpcSrv.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	cfg := DefaultConfig()
+	cfg.URL = srv.URL
+
+	// Without a WebSocket URL, callers poll
+	client, err := New(context.Background(), cfg)
+	require.NoError(t, err)
+	_, err = client.Subscribe(context.Background())
+	require.ErrorIs(t, err, ErrNoWebSocket)
+	client.Close()
+
+	cfg.WSURL = "http://" + strings.TrimPrefix(srv.URL, "http://")
+	_, err = New(context.Background(), cfg)
+	require.ErrorContains(t, err, "must use ws:// or wss://")
+
+	cfg.WSURL = "ws://" + strings.TrimPrefix(srv.URL, "http://")
+	client, err = New(context.Background(), cfg)
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	heads, err := client.Subscribe(ctx)
+	require.NoError(t, err)
+
+	svc.headers <- &types.Header{Number: big.NewInt(42), Difficulty: big.NewInt(0)}
+	select {
+	case header := <-heads:
+		require.Equal(t, uint64(42), header.Number.Uint64())
+	case <-time.After(5 * time.Second):
+		t.Fatal("no header received")
+	}
+
+	// Cancelling ends the subscription
+	cancel()
+	select {
+	case _, ok := <-heads:
+		require.False(t, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription channel not closed")
+	}
+}
+
+func TestLogFilter(t *testing.T) {
+	// Serves one poll of a single log, then forgets the filter
+	var polls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		w.Header().Set("Content-Type", "application/json")
+
+		switch req.Method {
+		case "eth_chainId":
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":"0x1"}`, req.ID)
+		case "eth_newFilter":
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":"0xabc"}`, req.ID)
+		case "eth_getFilterChanges":
+			if polls.Add(1) > 1 {
+				fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":-32000,"message":"filter not found"}}`, req.ID)
+				return
+			}
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":[{"address":"0x0000000000000000000000000000000000000001","topics":[],"data":"0x","blockNumber":"0x10","transactionHash":"0x%064x","transactionIndex":"0x0","blockHash":"0x%064x","logIndex":"0x2","removed":false}]}`, req.ID, 1, 2)
+		case "eth_uninstallFilter":
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":-32000,"message":"filter not found"}}`, req.ID)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := DefaultConfig()
+	cfg.URL = srv.URL
+	client, err := New(context.Background(), cfg)
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx := context.Background()
+	id, err := client.NewLogFilter(ctx, []common.Address{{0x01}}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "0xabc", id)
+
+	logs, err := client.LogFilterChanges(ctx, id)
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	require.Equal(t, uint64(16), logs[0].BlockNumber)
+
+	_, err = client.LogFilterChanges(ctx, id)
+	require.ErrorIs(t, err, ErrFilterNotFound)
+
+	// Removing a filter the node already dropped succeeds
+	require.NoError(t, client.UninstallFilter(ctx, id))
+}
+
+func TestFetchLogsDetectsTruncation(t *testing.T) {
+	// One log per block, but ranges over 10 blocks are silently capped at 5 logs
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		w.Header().Set("Content-Type", "application/json")
+
+		if req.Method == "eth_chainId" {
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":"0x1"}`, req.ID)
+			return
+		}
+
+		var filter struct {
+			FromBlock hexutil.Uint64 `json:"fromBlock"`
+			ToBlock   hexutil.Uint64 `json:"toBlock"`
+		}
+		require.NoError(t, json.Unmarshal(req.Params[0], &filter))
+
+		logs := []types.Log{}
+		for n := uint64(filter.FromBlock); n <= uint64(filter.ToBlock); n++ {
+			logs = append(logs, types.Log{BlockNumber: n, Topics: []common.Hash{}, Data: []byte{}})
+		}
+		if filter.ToBlock-filter.FromBlock >= 10 {
+			logs = logs[:5]
+		}
+		result, err := json.Marshal(logs)
+		require.NoError(t, err)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":%s}`, req.ID, result)
+	}))
+	defer srv.Close()
+
+	fetch := func(verify bool) []types.Log {
+		cfg := DefaultConfig()
+		cfg.URL = srv.URL
+		cfg.VerifyLogRanges = verify
+
+		client, err := New(context.Background(), cfg)
+		require.NoError(t, err)
+		defer client.Close()
+
+		logs, err := client.FetchLogs(context.Background(), nil, nil, 0, 19)
+		require.NoError(t, err)
+		return logs
+	}
+
+	// Without verification the capped response is accepted as is
+	require.Len(t, fetch(false), 5)
+
+	// With verification the range is re-fetched until complete
+	logs := fetch(true)
+	require.Len(t, logs, 20)
+	for i, l := range logs {
+		require.Equal(t, uint64(i), l.BlockNumber)
+	}
+}
@@ -0,0 +1,143 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrFilterNotFound is returned by LogFilterChanges when the node no longer
+// knows the filter: nodes drop filters that go unpolled for a few minutes,
+// and restarts or load-balancer failover lose them too.
+var ErrFilterNotFound = errors.New("log filter not found")
+
+// FilterClient is implemented by clients that support installed log
+// filters (eth_newFilter/eth_getFilterChanges). The node tracks the
+// filter's position, so each poll returns only logs of new blocks.
+type FilterClient interface {
+	// NewLogFilter installs a filter for logs of blocks mined from now on.
+	NewLogFilter(ctx context.Context, addresses []common.Address, topics [][]common.Hash) (string, error)
+
+	// LogFilterChanges returns the logs matched since the previous poll.
+	LogFilterChanges(ctx context.Context, id string) ([]types.Log, error)
+
+	// UninstallFilter removes a filter from the node.
+	UninstallFilter(ctx context.Context, id string) error
+}
+
+// Compile-time check that Client implements FilterClient.
+var _ FilterClient = (*Client)(nil)
+
+// filterNotFoundIndicators are error fragments nodes use for unknown filter IDs.
+var filterNotFoundIndicators = []string{
+	"filter not found",
+	"filter does not exist",
+	"filter id does not exist",
+}
+
+// isFilterNotFoundError reports whether err says the filter is unknown to the node.
+func isFilterNotFoundError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrFilterNotFound) {
+		return true
+	}
+	errStr := strings.ToLower(err.Error())
+	for _, indicator := range filterNotFoundIndicators {
+		if strings.Contains(errStr, indicator) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewLogFilter installs a log filter with eth_newFilter.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - addresses ([]common.Address): contract addresses to filter
+//   - topics ([][]common.Hash): topic filters
+//
+// Returns:
+//   - string: filter ID
+//   - error: nil on success, RPC error on failure
+func (c *Client) NewLogFilter(ctx context.Context, addresses []common.Address, topics [][]common.Hash) (string, error) {
+	arg := map[string]interface{}{
+		"address": addresses,
+		"topics":  topics,
+	}
+
+	var id string
+	if err := c.call(ctx, "eth_newFilter", &id, arg); err != nil {
+		return "", fmt.Errorf("installing log filter: %w", err)
+	}
+	return id, nil
+}
+
+// LogFilterChanges polls a log filter with eth_getFilterChanges.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - id (string): filter ID
+//
+// Returns:
+//   - []types.Log: logs matched since the previous poll
+//   - error: nil on success, ErrFilterNotFound if the node dropped the filter, RPC error on failure
+func (c *Client) LogFilterChanges(ctx context.Context, id string) ([]types.Log, error) {
+	var logs []types.Log
+	if err := c.call(ctx, "eth_getFilterChanges", &logs, id); err != nil {
+		if isFilterNotFoundError(err) {
+			return nil, fmt.Errorf("polling log filter %s: %w: %w", id, ErrFilterNotFound, err)
+		}
+		return nil, fmt.Errorf("polling log filter %s: %w", id, err)
+	}
+	return logs, nil
+}
+
+// UninstallFilter removes a filter with eth_uninstallFilter. Removing a
+// filter the node already dropped is not an error.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - id (string): filter ID
+//
+// Returns:
+//   - error: nil on success, RPC error on failure
+func (c *Client) UninstallFilter(ctx context.Context, id string) error {
+	var removed bool
+	if err := c.call(ctx, "eth_uninstallFilter", &removed, id); err != nil && !isFilterNotFoundError(err) {
+		return fmt.Errorf("uninstalling log filter %s: %w", id, err)
+	}
+	return nil
+}
+
+// call performs a raw JSON-RPC call through the concurrency limit and
+// circuit breaker, recording metrics under method.
+func (c *Client) call(ctx context.Context, method string, result interface{}, args ...interface{}) error {
+	if err := c.acquire(ctx); err != nil {
+		return err
+	}
+	defer c.release()
+
+	start := time.Now()
+
+	_, err := c.cb.Execute(func() (interface{}, error) {
+		return nil, c.eth.Client().CallContext(ctx, result, method, args...)
+	})
+
+	rpcRequestDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		rpcRequestTotal.WithLabelValues(method, "error").Inc()
+		return err
+	}
+
+	rpcRequestTotal.WithLabelValues(method, "success").Inc()
+	return nil
+}
@@ -0,0 +1,110 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// ErrNoWebSocket is returned by Subscribe when the client has no WebSocket
+// URL; callers fall back to polling.
+var ErrNoWebSocket = errors.New("no WebSocket URL configured")
+
+// HeadSubscriber is implemented by clients that can push new chain heads
+// (eth_subscribe("newHeads")) instead of being polled for them.
+type HeadSubscriber interface {
+	// Subscribe returns a channel of new block headers. The channel is
+	// closed when ctx is cancelled or the subscription drops.
+	Subscribe(ctx context.Context) (<-chan *types.Header, error)
+}
+
+// Compile-time check that Client implements HeadSubscriber.
+var _ HeadSubscriber = (*Client)(nil)
+
+// validateWSURL checks that a URL is a ws:// or wss:// endpoint.
+//
+// Parameters:
+//   - raw (string): URL to check
+//
+// Returns:
+//   - error: nil if valid, description of the problem otherwise
+func validateWSURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("parsing WebSocket URL: %w", err)
+	}
+	if u.Scheme != "ws" && u.Scheme != "wss" {
+		return fmt.Errorf("WebSocket URL %q must use ws:// or wss://", raw)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("WebSocket URL %q has no host", raw)
+	}
+	return nil
+}
+
+// Subscribe subscribes to new heads over the client's WebSocket URL, on a
+// connection of its own. The returned channel holds at most one header: a
+// consumer busy with a batch finds only the latest head when it returns.
+//
+// Parameters:
+//   - ctx (context.Context): subscription lifetime
+//
+// Returns:
+//   - <-chan *types.Header: new heads, closed when the subscription ends
+//   - error: nil on success, ErrNoWebSocket without a WebSocket URL, dial or subscribe error on failure
+func (c *Client) Subscribe(ctx context.Context) (<-chan *types.Header, error) {
+	if c.wsURL == "" {
+		return nil, ErrNoWebSocket
+	}
+
+	wsClient, err := gethrpc.DialContext(ctx, c.wsURL)
+	if err != nil {
+		rpcRequestTotal.WithLabelValues("eth_subscribe", "error").Inc()
+		return nil, fmt.Errorf("connecting to WebSocket RPC: %w", err)
+	}
+	eth := ethclient.NewClient(wsClient)
+
+	raw := make(chan *types.Header, 16)
+	sub, err := eth.SubscribeNewHead(ctx, raw)
+	if err != nil {
+		eth.Close()
+		rpcRequestTotal.WithLabelValues("eth_subscribe", "error").Inc()
+		return nil, fmt.Errorf("subscribing to new heads: %w", err)
+	}
+	rpcRequestTotal.WithLabelValues("eth_subscribe", "success").Inc()
+
+	heads := make(chan *types.Header, 1)
+	go func() {
+		defer close(heads)
+		defer eth.Close()
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case err := <-sub.Err():
+				if err != nil {
+					log.Warn().Err(err).Msg("newHeads subscription dropped")
+				}
+				return
+
+			case header := <-raw:
+				// Keep only the latest head
+				select {
+				case <-heads:
+				default:
+				}
+				heads <- header
+			}
+		}
+	}()
+
+	return heads, nil
+}
@@ -0,0 +1,6 @@
+package rpc
+
+import "github.com/0xredeth/Rafale/internal/logging"
+
+// log is the rpc subsystem's logger (level set by log.levels.rpc).
+var log = logging.New("rpc")
@@ -2,6 +2,7 @@ package rpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"strings"
@@ -9,10 +10,11 @@ import (
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/rs/zerolog/log"
 )
 
 // FetchLogs fetches logs for a block range with binary split on range errors.
+// A range the provider refuses is halved and each half fetched in turn, down
+// to single blocks; the halves' logs are returned together in block order.
 //
 // Parameters:
 //   - ctx (context.Context): request context
@@ -56,11 +58,22 @@ func (c *Client) fetchLogsWithSplit(
 
 	logs, err := c.FilterLogs(ctx, query)
 	if err == nil {
-		return logs, nil
-	}
+		truncated, err := c.isTruncated(ctx, addresses, topics, logs, toBlock)
+		if err != nil {
+			return nil, err
+		}
+		if !truncated {
+			return logs, nil
+		}
 
-	// Check if error indicates range too large
-	if !isRangeTooLargeError(err) {
+		rpcTruncatedLogs.Inc()
+		log.Warn().
+			Uint64("from", fromBlock).
+			Uint64("to", toBlock).
+			Int("logs", len(logs)).
+			Msg("getLogs response looks truncated, re-fetching in smaller ranges")
+	} else if c.Classify(err) != RangeTooLarge {
+		// Split only when the provider refused the range size
 		return nil, err
 	}
 
@@ -98,6 +111,59 @@ func (c *Client) fetchLogsWithSplit(
 	return allLogs, nil
 }
 
+// isTruncated cross-checks a getLogs response when VerifyLogRanges is set.
+// Providers that silently cap results return a prefix of the range, so if
+// the last log ends before toBlock, the tail from the last log's block is
+// re-fetched: finding more logs there than the response holds means the
+// response was cut short.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - addresses ([]common.Address): contract addresses to filter
+//   - topics ([][]common.Hash): topic filters
+//   - logs ([]types.Log): the response, in block order
+//   - toBlock (uint64): end of the requested range
+//
+// Returns:
+//   - bool: true if the response looks truncated
+//   - error: nil on success, tail fetch error on failure
+func (c *Client) isTruncated(
+	ctx context.Context,
+	addresses []common.Address,
+	topics [][]common.Hash,
+	logs []types.Log,
+	toBlock uint64,
+) (bool, error) {
+	if !c.verifyLogs || len(logs) == 0 {
+		return false, nil
+	}
+	last := logs[len(logs)-1].BlockNumber
+	if last >= toBlock {
+		return false, nil
+	}
+
+	// Logs already held for the tail range (only the last block's)
+	held := 0
+	for i := len(logs) - 1; i >= 0 && logs[i].BlockNumber == last; i-- {
+		held++
+	}
+
+	tail, err := c.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(last),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: addresses,
+		Topics:    topics,
+	})
+	if err != nil {
+		// A refused tail means the wider range can't have been complete
+		if c.Classify(err) == RangeTooLarge {
+			return true, nil
+		}
+		return false, fmt.Errorf("verifying log range [%d-%d]: %w", last, toBlock, err)
+	}
+	return len(tail) > held, nil
+}
+
 // isRangeTooLargeError checks if the error indicates the block range is too large.
 //
 // Parameters:
@@ -109,6 +175,9 @@ func isRangeTooLargeError(err error) bool {
 	if err == nil {
 		return false
 	}
+	if errors.Is(err, ErrResponseTooLarge) {
+		return true
+	}
 
 	errStr := strings.ToLower(err.Error())
 
@@ -124,6 +193,9 @@ func isRangeTooLargeError(err error) bool {
 		"response too large",
 		"max results",
 		"limit exceeded",
+		"response size exceeded",  // Alchemy: "Log response size exceeded. ... up to a 2K block range"
+		"exceeds max block range", // Ankr
+		"requests with up to a",   // Alchemy range hint without the size prefix
 	}
 
 	for _, indicator := range rangeTooLargeIndicators {
@@ -180,3 +252,53 @@ func (c *Client) FetchLogsInBatches(
 
 	return allLogs, nil
 }
+
+// BlockMayContain reports whether a block can contain logs matching the
+// filter, based on the header's logsBloom. A false result is definitive:
+// the block has no matching logs and getLogs can be skipped. A true result
+// may be a false positive.
+//
+// Parameters:
+//   - header (*types.Header): block header
+//   - addrs ([]common.Address): contract addresses (any may match; empty matches all)
+//   - topics ([][]common.Hash): topic filters by position (any per position; empty matches all)
+//
+// Returns:
+//   - bool: false if the bloom excludes every match, true otherwise
+func BlockMayContain(header *types.Header, addrs []common.Address, topics [][]common.Hash) bool {
+	if header == nil {
+		return true
+	}
+
+	if len(addrs) > 0 {
+		found := false
+		for _, addr := range addrs {
+			if header.Bloom.Test(addr.Bytes()) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	// Every constrained topic position must have at least one candidate present
+	for _, position := range topics {
+		if len(position) == 0 {
+			continue
+		}
+		found := false
+		for _, topic := range position {
+			if header.Bloom.Test(topic.Bytes()) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
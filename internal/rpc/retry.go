@@ -0,0 +1,115 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// RetryAction is how the client treats a failed request.
+type RetryAction int
+
+const (
+	// Unclassified defers to the built-in classification. It is the zero
+	// value, so a RetryClassifier returns it for errors it doesn't know.
+	Unclassified RetryAction = iota
+
+	// Retry marks a transient failure worth retrying as is.
+	Retry
+
+	// RangeTooLarge marks a getLogs request the provider refused for its
+	// size; FetchLogs splits the block range. Not an endpoint failure.
+	RangeTooLarge
+
+	// RateLimited marks a throttled request; callers should back off.
+	RateLimited
+
+	// Fatal marks a request that will never succeed (e.g. invalid params).
+	// Not an endpoint failure.
+	Fatal
+)
+
+// String returns the action name.
+func (a RetryAction) String() string {
+	switch a {
+	case Retry:
+		return "retry"
+	case RangeTooLarge:
+		return "range_too_large"
+	case RateLimited:
+		return "rate_limited"
+	case Fatal:
+		return "fatal"
+	default:
+		return "unclassified"
+	}
+}
+
+// RetryClassifier maps a request error to a RetryAction. It is consulted
+// before the built-in classification and returns Unclassified to defer to it.
+type RetryClassifier func(err error) RetryAction
+
+// rateLimitIndicators are error fragments providers use for throttling.
+var rateLimitIndicators = []string{
+	"429",
+	"too many requests",
+	"rate limit",
+	"rate-limited",
+	"exceeded the quota",
+	"compute units",
+}
+
+// Classify returns how the client treats err: the configured
+// RetryClassifier's answer if it has one, the built-in classification otherwise.
+//
+// Parameters:
+//   - err (error): request error
+//
+// Returns:
+//   - RetryAction: the action (Unclassified only for a nil error)
+func (c *Client) Classify(err error) RetryAction {
+	if err == nil {
+		return Unclassified
+	}
+	if c.classifier != nil {
+		if action := c.classifier(err); action != Unclassified {
+			return action
+		}
+	}
+	return classifyError(err)
+}
+
+// classifyError is the built-in classification: range errors first (so
+// FetchLogs keeps splitting on them), then rate limits; cancellation,
+// unknown filters and reverted calls are fatal and anything else is retryable.
+func classifyError(err error) RetryAction {
+	if isRangeTooLargeError(err) {
+		return RangeTooLarge
+	}
+	if errors.Is(err, context.Canceled) || isFilterNotFoundError(err) || isRevertError(err) {
+		return Fatal
+	}
+
+	errStr := strings.ToLower(err.Error())
+	for _, indicator := range rateLimitIndicators {
+		if strings.Contains(errStr, indicator) {
+			return RateLimited
+		}
+	}
+	return Retry
+}
+
+// countsAsFailure reports whether err should count toward opening the
+// circuit breaker. Oversized and invalid requests say nothing about the
+// endpoint's health.
+func (c *Client) countsAsFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch c.Classify(err) {
+	case RangeTooLarge, Fatal:
+		return false
+	default:
+		return true
+	}
+}
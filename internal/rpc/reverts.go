@@ -0,0 +1,98 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// TxClient is implemented by clients that can fetch receipts and replay
+// calls, used to track reverted transactions (sync.track_reverts).
+type TxClient interface {
+	// TransactionReceipt returns the receipt for a transaction.
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+
+	// CallContract executes a call against the state at a block.
+	CallContract(ctx context.Context, msg ethereum.CallMsg, block *big.Int) ([]byte, error)
+}
+
+// Compile-time check that Client implements TxClient.
+var _ TxClient = (*Client)(nil)
+
+// CallContract executes a message call with eth_call against the state at
+// a block. A revert is returned as an error carrying the revert data (see
+// RevertData); it is not an endpoint failure.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - msg (ethereum.CallMsg): call to execute
+//   - block (*big.Int): block whose state to use (nil for latest)
+//
+// Returns:
+//   - []byte: the call's return data
+//   - error: nil on success, revert or RPC error on failure
+func (c *Client) CallContract(ctx context.Context, msg ethereum.CallMsg, block *big.Int) ([]byte, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, fmt.Errorf("calling contract: %w", err)
+	}
+	defer c.release()
+
+	start := time.Now()
+
+	result, err := c.cb.Execute(func() (interface{}, error) {
+		return c.eth.CallContract(ctx, msg, block)
+	})
+
+	duration := time.Since(start).Seconds()
+	rpcRequestDuration.WithLabelValues("eth_call").Observe(duration)
+
+	if err != nil {
+		rpcRequestTotal.WithLabelValues("eth_call", "error").Inc()
+		return nil, fmt.Errorf("calling contract: %w", err)
+	}
+
+	rpcRequestTotal.WithLabelValues("eth_call", "success").Inc()
+	return result.([]byte), nil
+}
+
+// RevertData extracts the revert data from a failed call's error.
+//
+// Parameters:
+//   - err (error): call error
+//
+// Returns:
+//   - []byte: the revert data
+//   - bool: false if err carries no revert data
+func RevertData(err error) ([]byte, bool) {
+	var dataErr gethrpc.DataError
+	if !errors.As(err, &dataErr) {
+		return nil, false
+	}
+	hexData, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return nil, false
+	}
+	data, decodeErr := hexutil.Decode(hexData)
+	if decodeErr != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// isRevertError reports whether err is a reverted call rather than a
+// failed request.
+func isRevertError(err error) bool {
+	if _, ok := RevertData(err); ok {
+		return true
+	}
+	return strings.Contains(strings.ToLower(err.Error()), "execution reverted")
+}
@@ -0,0 +1,87 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// ErrResponseTooLarge is returned when an RPC response exceeds
+// ClientConfig.MaxResponseBytes. FetchLogs treats it like a provider-side
+// range limit and splits the block range.
+var ErrResponseTooLarge = errors.New("rpc response too large")
+
+// dial connects to the endpoint, capping response sizes when maxBytes > 0.
+//
+// Parameters:
+//   - ctx (context.Context): context for connection
+//   - url (string): RPC endpoint URL (http(s) or ws(s))
+//   - maxBytes (int64): response size limit in bytes (0 = transport default)
+//
+// Returns:
+//   - *gethrpc.Client: the connected RPC client
+//   - error: nil on success, connection error on failure
+func dial(ctx context.Context, url string, maxBytes int64) (*gethrpc.Client, error) {
+	if maxBytes <= 0 {
+		return gethrpc.DialContext(ctx, url)
+	}
+
+	var opts []gethrpc.ClientOption
+	if strings.HasPrefix(url, "ws://") || strings.HasPrefix(url, "wss://") {
+		opts = append(opts, gethrpc.WithWebsocketMessageSizeLimit(maxBytes))
+	} else {
+		opts = append(opts, gethrpc.WithHTTPClient(&http.Client{
+			Transport: &limitedTransport{base: http.DefaultTransport, maxBytes: maxBytes},
+		}))
+	}
+	return gethrpc.DialOptions(ctx, url, opts...)
+}
+
+// limitedTransport wraps response bodies so reads past maxBytes fail with
+// ErrResponseTooLarge instead of surfacing as a truncated-JSON parse error.
+type limitedTransport struct {
+	base     http.RoundTripper
+	maxBytes int64
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = &limitedBody{body: resp.Body, remaining: t.maxBytes}
+	return resp, nil
+}
+
+// limitedBody is an io.ReadCloser that errors once more than its budget is read.
+type limitedBody struct {
+	body      io.ReadCloser
+	remaining int64
+}
+
+// Read implements io.Reader.
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, ErrResponseTooLarge
+	}
+	// Read one byte past the budget to tell "exactly at limit" from "over"
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.body.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return 0, ErrResponseTooLarge
+	}
+	return n, err
+}
+
+// Close implements io.Closer.
+func (b *limitedBody) Close() error {
+	return b.body.Close()
+}
@@ -0,0 +1,85 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Address cases for Config.AddressCase.
+const (
+	// AddressCaseLower stores and matches addresses lowercased.
+	AddressCaseLower = "lower"
+
+	// AddressCaseChecksum stores and matches addresses EIP-55 checksummed.
+	AddressCaseChecksum = "checksum"
+)
+
+// TransferAddressColumns are the address columns of a transfers table.
+var TransferAddressColumns = []string{"from", "to", "contract_addr"}
+
+// EventAddressColumns are the address columns of the events table.
+var EventAddressColumns = []string{"contract_addr"}
+
+// formatAddress puts an address filter value in the case addresses are
+// stored in. Values that aren't addresses are only lowercased, so they
+// still match nothing rather than erroring.
+func (s *Store) formatAddress(addr string) string {
+	if s.addressCase == AddressCaseChecksum && common.IsHexAddress(addr) {
+		return common.HexToAddress(addr).Hex()
+	}
+	return strings.ToLower(addr)
+}
+
+// NormalizeAddressCase rewrites addresses stored in another case than
+// Config.AddressCase, such as rows written before the setting existed or
+// under the other case, so address filters match every row. Rows already
+// in the configured case are left alone, so later runs only scan.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - table (string): table name
+//   - columns (...string): address columns of the table
+//
+// Returns:
+//   - error: nil on success, update error on failure
+func (s *Store) NormalizeAddressCase(ctx context.Context, table string, columns ...string) error {
+	db := s.db.WithContext(ctx)
+	for _, column := range columns {
+		if s.addressCase != AddressCaseChecksum {
+			result := db.Exec(fmt.Sprintf(`UPDATE %[1]q SET %[2]q = lower(%[2]q) WHERE %[2]q <> lower(%[2]q)`, table, column))
+			if result.Error != nil {
+				return fmt.Errorf("lowercasing %s.%s: %w", table, column, result.Error)
+			}
+			if result.RowsAffected > 0 {
+				log.Info().Str("table", table).Str("column", column).Int64("rows", result.RowsAffected).Msg("lowercased stored addresses")
+			}
+			continue
+		}
+
+		// Checksums can't be computed in SQL: rewrite each lowercase address
+		var addrs []string
+		if err := db.Raw(fmt.Sprintf(`SELECT DISTINCT %[2]q FROM %[1]q WHERE %[2]q = lower(%[2]q) AND %[2]q ~ '[a-f]'`, table, column)).
+			Scan(&addrs).Error; err != nil {
+			return fmt.Errorf("reading %s.%s: %w", table, column, err)
+		}
+		var rows int64
+		for _, addr := range addrs {
+			checksummed := s.formatAddress(addr)
+			if checksummed == addr {
+				continue
+			}
+			result := db.Exec(fmt.Sprintf(`UPDATE %[1]q SET %[2]q = ? WHERE %[2]q = ?`, table, column), checksummed, addr)
+			if result.Error != nil {
+				return fmt.Errorf("checksumming %s.%s: %w", table, column, result.Error)
+			}
+			rows += result.RowsAffected
+		}
+		if rows > 0 {
+			log.Info().Str("table", table).Str("column", column).Int64("rows", rows).Msg("checksummed stored addresses")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNoIndexedBlocks is returned by BlockNumberAtTime when no event or
+// transfer is stored to derive a block from.
+var ErrNoIndexedBlocks = errors.New("no indexed blocks")
+
+// blockAtTimeTransfersSQL is the blockAtTimeSQL branch of one transfer
+// table.
+const blockAtTimeTransfersSQL = `
+		(SELECT block_number, timestamp FROM %[1]q WHERE timestamp <= @t ORDER BY timestamp DESC, block_number DESC LIMIT 1)
+		UNION ALL
+		(SELECT block_number, timestamp FROM %[1]q WHERE timestamp >= @t ORDER BY timestamp ASC, block_number ASC LIMIT 1)`
+
+// blockAtTimeSQL picks the nearest stored row on each side of @t in every
+// table (each branch is a LIMIT 1 scan of the timestamp index), then the
+// closest of those; ties go to the earlier block.
+const blockAtTimeSQL = `
+	SELECT block_number FROM (
+		(SELECT block_number, timestamp FROM events WHERE timestamp <= @t ORDER BY timestamp DESC, block_number DESC LIMIT 1)
+		UNION ALL
+		(SELECT block_number, timestamp FROM events WHERE timestamp >= @t ORDER BY timestamp ASC, block_number ASC LIMIT 1)
+		UNION ALL%s
+	) c
+	ORDER BY abs(extract(epoch FROM timestamp - @t::timestamptz)), block_number
+	LIMIT 1`
+
+// BlockNumberAtTime returns the indexed block whose timestamp is closest
+// to t, from the timestamps stored with events and transfers. It needs no
+// RPC, but only knows blocks holding indexed rows: the answer is the
+// nearest such block, not necessarily the chain's block at t.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - t (time.Time): target time
+//
+// Returns:
+//   - uint64: nearest indexed block number
+//   - error: nil on success, ErrNoIndexedBlocks if nothing is stored, query error on failure
+func (s *Store) BlockNumberAtTime(ctx context.Context, t time.Time) (uint64, error) {
+	start := time.Now()
+
+	var nearest struct {
+		BlockNumber *uint64
+	}
+	if err := s.db.WithContext(ctx).Raw(fmt.Sprintf(blockAtTimeSQL, unionTransferTables(blockAtTimeTransfersSQL)), map[string]any{"t": t.UTC()}).Scan(&nearest).Error; err != nil {
+		return 0, fmt.Errorf("finding block at %s: %w", t.UTC().Format(time.RFC3339), err)
+	}
+
+	dbQueryDuration.WithLabelValues("block_number_at_time").Observe(time.Since(start).Seconds())
+	if nearest.BlockNumber == nil {
+		return 0, ErrNoIndexedBlocks
+	}
+	return *nearest.BlockNumber, nil
+}
@@ -0,0 +1,155 @@
+package store
+
+import (
+	"container/list"
+	"slices"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var cacheRequests = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "rafale_store_cache_requests_total",
+		Help: "Total number of row cache lookups by ID (Config.CacheSize), by table and result (hit, miss)",
+	},
+	[]string{"table", "result"},
+)
+
+// rowCache is a bounded LRU cache of rows by ID. Stored rows don't change
+// once written, except when a block range is deleted (reorg rollback,
+// reindex) or redecoded: purgeBlocks drops the rows of those blocks.
+//
+// A purge bumps the cache generation, and rows read from the database
+// are only added if no purge happened since the read started, so a read
+// racing a delete can't cache the deleted row.
+type rowCache[T any] struct {
+	table    string
+	capacity int
+	block    func(*T) uint64 // block number of a row
+
+	mu         sync.Mutex
+	entries    map[uint64]*list.Element
+	order      *list.List // front = most recently used
+	generation uint64
+}
+
+// cacheEntry is a cached row.
+type cacheEntry[T any] struct {
+	id  uint64
+	row T
+}
+
+// newRowCache creates a cache holding up to capacity rows.
+func newRowCache[T any](table string, capacity int, block func(*T) uint64) *rowCache[T] {
+	return &rowCache[T]{
+		table:    table,
+		capacity: capacity,
+		block:    block,
+		entries:  make(map[uint64]*list.Element, capacity),
+		order:    list.New(),
+	}
+}
+
+// get returns a copy of the cached row and the current generation, to be
+// passed to add if the row has to be read from the database.
+func (c *rowCache[T]) get(id uint64) (*T, uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[id]; ok {
+		c.order.MoveToFront(el)
+		cacheRequests.WithLabelValues(c.table, "hit").Inc()
+		row := el.Value.(*cacheEntry[T]).row
+		return &row, c.generation
+	}
+	cacheRequests.WithLabelValues(c.table, "miss").Inc()
+	return nil, c.generation
+}
+
+// add caches a row read at generation, evicting the least recently used
+// row when full. Rows read before a purge are dropped.
+func (c *rowCache[T]) add(id uint64, row T, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+	if el, ok := c.entries[id]; ok {
+		el.Value.(*cacheEntry[T]).row = row
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[id] = c.order.PushFront(&cacheEntry[T]{id: id, row: row})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry[T]).id)
+	}
+}
+
+// purgeBlocks drops the cached rows of an inclusive block range.
+func (c *rowCache[T]) purgeBlocks(fromBlock, toBlock uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	for id, el := range c.entries {
+		if block := c.block(&el.Value.(*cacheEntry[T]).row); block >= fromBlock && block <= toBlock {
+			c.order.Remove(el)
+			delete(c.entries, id)
+		}
+	}
+}
+
+// len returns the number of cached rows.
+func (c *rowCache[T]) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// storeCaches are the row caches of the open stores, purged together:
+// the API and the engine may use different Store instances.
+var (
+	storeCachesMu sync.Mutex
+	storeCaches   []*Store
+)
+
+// registerCaches adds a store with caches to those purged by PurgeCachedBlocks.
+func registerCaches(s *Store) {
+	storeCachesMu.Lock()
+	defer storeCachesMu.Unlock()
+
+	storeCaches = append(storeCaches, s)
+}
+
+// unregisterCaches removes a closed store from those purged.
+func unregisterCaches(s *Store) {
+	storeCachesMu.Lock()
+	defer storeCachesMu.Unlock()
+
+	storeCaches = slices.DeleteFunc(storeCaches, func(other *Store) bool { return other == s })
+}
+
+// PurgeCachedBlocks drops the cached rows of an inclusive block range
+// from every store's row cache. Callers deleting or rewriting rows with
+// DeleteBlockRangeTx or UpdateEventDataTx call it once the transaction
+// has committed.
+//
+// Parameters:
+//   - fromBlock (uint64): first block (inclusive)
+//   - toBlock (uint64): last block (inclusive)
+func PurgeCachedBlocks(fromBlock, toBlock uint64) {
+	storeCachesMu.Lock()
+	stores := slices.Clone(storeCaches)
+	storeCachesMu.Unlock()
+
+	for _, s := range stores {
+		s.eventCache.purgeBlocks(fromBlock, toBlock)
+		s.transferCache.purgeBlocks(fromBlock, toBlock)
+	}
+}
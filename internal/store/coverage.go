@@ -0,0 +1,92 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BlockRange is an inclusive range of block numbers.
+type BlockRange struct {
+	From uint64
+	To   uint64
+}
+
+// UpsertBlockCoverageTx records every block of an inclusive range as
+// processed, with its matched log count, using an existing transaction so
+// coverage commits atomically with the batch it describes.
+//
+// Parameters:
+//   - tx (*gorm.DB): database transaction
+//   - fromBlock (uint64): first block (inclusive)
+//   - toBlock (uint64): last block (inclusive)
+//   - counts (map[uint64]int): matched logs per block; missing blocks count zero
+//
+// Returns:
+//   - error: nil on success, write error on failure
+func UpsertBlockCoverageTx(tx *gorm.DB, fromBlock, toBlock uint64, counts map[uint64]int) error {
+	if fromBlock > toBlock {
+		return nil
+	}
+
+	rows := make([]BlockCoverage, 0, toBlock-fromBlock+1)
+	for block := fromBlock; block <= toBlock; block++ {
+		rows = append(rows, BlockCoverage{BlockNumber: block, LogCount: counts[block]})
+	}
+
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "block_number"}},
+		DoUpdates: clause.AssignmentColumns([]string{"log_count", "created_at"}),
+	}).CreateInBatches(rows, 1000).Error; err != nil {
+		return fmt.Errorf("recording coverage for blocks %d-%d: %w", fromBlock, toBlock, err)
+	}
+	return nil
+}
+
+// FindBlockGaps returns the ranges of blocks within [fromBlock, toBlock]
+// that have no coverage row, i.e. were never processed. Blocks processed
+// without matching logs have a zero-count row and are not gaps.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - fromBlock (uint64): first block (inclusive)
+//   - toBlock (uint64): last block (inclusive)
+//
+// Returns:
+//   - []BlockRange: missing ranges in ascending order
+//   - error: nil on success, query error on failure
+func (s *Store) FindBlockGaps(ctx context.Context, fromBlock, toBlock uint64) ([]BlockRange, error) {
+	if fromBlock > toBlock {
+		return nil, nil
+	}
+
+	// Islands of consecutive covered blocks: block_number - row_number is
+	// constant within an island.
+	var islands []BlockRange
+	if err := s.db.WithContext(ctx).Raw(`
+		SELECT MIN(block_number) AS "from", MAX(block_number) AS "to" FROM (
+			SELECT block_number, block_number - ROW_NUMBER() OVER (ORDER BY block_number) AS island
+			FROM block_coverage
+			WHERE block_number BETWEEN ? AND ?
+		) c
+		GROUP BY island
+		ORDER BY 1
+	`, fromBlock, toBlock).Scan(&islands).Error; err != nil {
+		return nil, fmt.Errorf("finding block gaps %d-%d: %w", fromBlock, toBlock, err)
+	}
+
+	var gaps []BlockRange
+	next := fromBlock
+	for _, island := range islands {
+		if island.From > next {
+			gaps = append(gaps, BlockRange{From: next, To: island.From - 1})
+		}
+		next = island.To + 1
+	}
+	if next <= toBlock {
+		gaps = append(gaps, BlockRange{From: next, To: toBlock})
+	}
+	return gaps, nil
+}
@@ -0,0 +1,80 @@
+package store
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// EncodeCursor encodes keyset values into an opaque cursor string. A
+// single row ID encodes as in GraphQL pagination, so every read API shares
+// one cursor format.
+//
+// Parameters:
+//   - parts (...string): keyset values, in key order
+//
+// Returns:
+//   - string: opaque cursor
+func EncodeCursor(parts ...string) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Join(parts, ":")))
+}
+
+// DecodeCursor decodes an opaque cursor into its keyset values.
+//
+// Parameters:
+//   - cursor (string): cursor from EncodeCursor
+//   - n (int): expected number of values
+//
+// Returns:
+//   - []string: keyset values
+//   - error: nil on success, error if the cursor is malformed
+func DecodeCursor(cursor string, n int) ([]string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("decoding cursor: %w", err)
+	}
+	parts := strings.Split(string(decoded), ":")
+	if len(parts) != n {
+		return nil, fmt.Errorf("decoding cursor: want %d values, got %d", n, len(parts))
+	}
+	return parts, nil
+}
+
+// encodeStreamCursor encodes the keyset position of a streamed row.
+func encodeStreamCursor(ev UnifiedEvent) string {
+	return EncodeCursor(
+		strconv.FormatUint(ev.BlockNumber, 10),
+		strconv.FormatUint(uint64(ev.TxIndex), 10),
+		strconv.FormatUint(uint64(ev.LogIndex), 10),
+		ev.Type,
+		strconv.FormatUint(ev.ID, 10),
+	)
+}
+
+// decodeStreamCursor decodes a cursor from encodeStreamCursor into the
+// keyset fields of a UnifiedEvent.
+func decodeStreamCursor(cursor string) (UnifiedEvent, error) {
+	parts, err := DecodeCursor(cursor, 5)
+	if err != nil {
+		return UnifiedEvent{}, err
+	}
+
+	var nums [4]uint64
+	for i, part := range []string{parts[0], parts[1], parts[2], parts[4]} {
+		if nums[i], err = strconv.ParseUint(part, 10, 64); err != nil {
+			return UnifiedEvent{}, fmt.Errorf("parsing cursor: %w", err)
+		}
+	}
+	if parts[3] != UnifiedTypeEvent && parts[3] != UnifiedTypeTransfer {
+		return UnifiedEvent{}, fmt.Errorf("parsing cursor: unknown row type %q", parts[3])
+	}
+
+	return UnifiedEvent{
+		BlockNumber: nums[0],
+		TxIndex:     uint(nums[1]),
+		LogIndex:    uint(nums[2]),
+		Type:        parts[3],
+		ID:          nums[3],
+	}, nil
+}
@@ -0,0 +1,134 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+)
+
+// MaxDecimals is the largest token decimals value: uint256 has 78 digits.
+const MaxDecimals = 77
+
+// contractDecimals holds the token decimals of contracts, for formatting
+// transfer values.
+var (
+	contractDecimalsMu sync.RWMutex
+	contractDecimals   = make(map[string]int) // lowercased contract address -> decimals
+)
+
+// RegisterDecimals sets the token decimals used to format the values of a
+// contract's transfers (see FormatDecimal).
+//
+// Parameters:
+//   - contractAddr (string): contract address (any case)
+//   - decimals (int): token decimals (0 to MaxDecimals)
+func RegisterDecimals(contractAddr string, decimals int) {
+	contractDecimalsMu.Lock()
+	defer contractDecimalsMu.Unlock()
+
+	contractDecimals[strings.ToLower(contractAddr)] = decimals
+}
+
+// Decimals returns the token decimals registered for a contract.
+//
+// Parameters:
+//   - contractAddr (string): contract address (any case)
+//
+// Returns:
+//   - int: token decimals
+//   - bool: false if none are registered
+func Decimals(contractAddr string) (int, bool) {
+	contractDecimalsMu.RLock()
+	defer contractDecimalsMu.RUnlock()
+
+	decimals, ok := contractDecimals[strings.ToLower(contractAddr)]
+	return decimals, ok
+}
+
+// FormatDecimal scales a raw integer amount by 10^decimals and renders it
+// exactly, without trailing zeros (e.g. "1500000" with 6 decimals is "1.5").
+//
+// Parameters:
+//   - value (string): raw base-10 integer amount
+//   - decimals (int): token decimals
+//
+// Returns:
+//   - string: decimal amount
+//   - error: nil on success, error if value isn't an integer
+func FormatDecimal(value string, decimals int) (string, error) {
+	n, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return "", fmt.Errorf("invalid amount %q", value)
+	}
+	if decimals <= 0 {
+		return n.String(), nil
+	}
+
+	sign := ""
+	if n.Sign() < 0 {
+		sign = "-"
+		n.Neg(n)
+	}
+	digits := n.String()
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+	whole, frac := digits[:len(digits)-decimals], strings.TrimRight(digits[len(digits)-decimals:], "0")
+	if frac == "" {
+		return sign + whole, nil
+	}
+	return sign + whole + "." + frac, nil
+}
+
+// valueDecimal formats a transfer value with its contract's decimals.
+//
+// Returns:
+//   - string: decimal amount, "" if the contract has no decimals or the value is invalid
+func valueDecimal(contractAddr, value string) string {
+	decimals, ok := Decimals(contractAddr)
+	if !ok {
+		return ""
+	}
+	formatted, err := FormatDecimal(value, decimals)
+	if err != nil {
+		return ""
+	}
+	return formatted
+}
+
+// formatTransferDecimals sets ValueDecimal on transfers whose contract has
+// registered decimals.
+func formatTransferDecimals(transfers []Transfer) {
+	for i := range transfers {
+		transfers[i].ValueDecimal = valueDecimal(transfers[i].ContractAddr, transfers[i].Value)
+	}
+}
+
+// withValueDecimal adds a value_decimal field to the JSON data of an
+// exported transfer, next to its raw value.
+//
+// Returns:
+//   - json.RawMessage: data with value_decimal, unchanged if it can't be formatted
+func withValueDecimal(data json.RawMessage, contractAddr string) json.RawMessage {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return data
+	}
+	var value string
+	if err := json.Unmarshal(fields["value"], &value); err != nil {
+		return data
+	}
+	formatted := valueDecimal(contractAddr, value)
+	if formatted == "" {
+		return data
+	}
+
+	fields["value_decimal"], _ = json.Marshal(formatted)
+	out, err := json.Marshal(fields)
+	if err != nil {
+		return data
+	}
+	return out
+}
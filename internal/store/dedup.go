@@ -0,0 +1,80 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// dedupIndexPrefix names the unique index deduplicating the events table.
+const dedupIndexPrefix = "idx_events_dedup_"
+
+// EnsureEventDedupIndex creates the unique index deduplicating events on
+// columns, dropping a dedup index built on other columns. The timestamp
+// column is appended because TimescaleDB requires the partitioning column
+// in unique indexes on hypertables; a log's timestamp is fixed by its block.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - columns ([]string): events columns identifying a log
+//
+// Returns:
+//   - error: nil on success, error if existing rows are duplicates under the key
+func (s *Store) EnsureEventDedupIndex(ctx context.Context, columns []string) error {
+	name := dedupIndexPrefix + strings.Join(columns, "_")
+	db := s.db.WithContext(ctx)
+
+	var stale []string
+	if err := db.Raw(
+		`SELECT indexname FROM pg_indexes WHERE tablename = 'events' AND indexname LIKE ? AND indexname <> ?`,
+		dedupIndexPrefix+"%", name,
+	).Scan(&stale).Error; err != nil {
+		return fmt.Errorf("listing dedup indexes: %w", err)
+	}
+	for _, index := range stale {
+		if err := db.Exec(fmt.Sprintf(`DROP INDEX IF EXISTS %q`, index)).Error; err != nil {
+			return fmt.Errorf("dropping dedup index %s: %w", index, err)
+		}
+		log.Info().Str("index", index).Msg("dropped previous events dedup index")
+	}
+
+	quoted := make([]string, 0, len(columns)+1)
+	for _, column := range dedupTarget(columns) {
+		quoted = append(quoted, fmt.Sprintf("%q", column))
+	}
+	if err := db.Exec(fmt.Sprintf(
+		`CREATE UNIQUE INDEX IF NOT EXISTS %q ON events (%s)`, name, strings.Join(quoted, ", "),
+	)).Error; err != nil {
+		return fmt.Errorf("creating dedup index on events (%s) (remove duplicate rows first): %w", strings.Join(columns, ", "), err)
+	}
+	return nil
+}
+
+// dedupTarget returns the dedup index columns for a key: the key columns
+// plus timestamp.
+func dedupTarget(columns []string) []string {
+	return append(append(make([]string, 0, len(columns)+1), columns...), "timestamp")
+}
+
+// CreateEventTx inserts an event using an existing transaction. With
+// dedup columns, an event already stored under the same key is left as is
+// (see skipConflicts).
+//
+// Parameters:
+//   - tx (*gorm.DB): database transaction
+//   - event (*Event): event to insert
+//   - dedupColumns ([]string): columns of the dedup index (nil = plain insert)
+//
+// Returns:
+//   - error: nil on success, insert error on failure
+func CreateEventTx(tx *gorm.DB, event *Event, dedupColumns []string) error {
+	if len(dedupColumns) > 0 {
+		tx = tx.Clauses(skipConflicts(dedupTarget(dedupColumns)))
+	}
+	if err := tx.Create(event).Error; err != nil {
+		return fmt.Errorf("inserting event: %w", err)
+	}
+	return nil
+}
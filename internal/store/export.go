@@ -0,0 +1,362 @@
+package store
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Time partition granularities for ExportPartitioned.
+const (
+	PartitionDay  = "day"
+	PartitionHour = "hour"
+)
+
+// ExportRange selects the rows to export. Block bounds take precedence;
+// a time range without block bounds is resolved to the blocks it covers.
+type ExportRange struct {
+	FromBlock *uint64
+	ToBlock   *uint64
+	FromTime  *time.Time
+	ToTime    *time.Time
+
+	// After resumes an export: only rows after this cursor (the Cursor of
+	// a previous ExportProgress) are exported.
+	After string
+
+	// FormatDecimals adds a value_decimal field next to the raw value in
+	// the data of transfers whose contract's decimals are known.
+	FormatDecimals bool
+}
+
+// ExportProgress reports how far an export got. On failure it describes
+// the last checkpoint, so the export can resume by passing Cursor back as
+// ExportRange.After.
+type ExportProgress struct {
+	// Rows is the number of rows written up to Cursor.
+	Rows int64
+
+	// Bytes is the output size up to Cursor (ExportNDJSON only). Output
+	// past it may hold a partial row; truncate to Bytes before appending.
+	Bytes int64
+
+	// Cursor is the position of the last row written ("" if none).
+	Cursor string
+
+	// Partitions is the number of rows written per partition up to
+	// Cursor (ExportPartitioned only).
+	Partitions map[string]int64
+}
+
+// exportCheckpointRows is how many rows ExportNDJSON writes between
+// checkpoints.
+const exportCheckpointRows = streamPageSize
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+// Write writes p to the underlying writer.
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// ExportRecord is the NDJSON shape of an exported event or transfer.
+type ExportRecord struct {
+	Type        string          `json:"type"`
+	ID          uint64          `json:"id"`
+	BlockNumber uint64          `json:"blockNumber"`
+	TxHash      string          `json:"txHash"`
+	TxIndex     uint            `json:"txIndex"`
+	LogIndex    uint            `json:"logIndex"`
+	Timestamp   time.Time       `json:"timestamp"`
+	Contract    string          `json:"contract,omitempty"`
+	Event       string          `json:"event"`
+	Data        json.RawMessage `json:"data"`
+}
+
+// newExportRecord converts a streamed row into its export shape.
+func newExportRecord(ev UnifiedEvent, formatDecimals bool) ExportRecord {
+	data := json.RawMessage(ev.Data)
+	if formatDecimals && ev.Type == "transfer" {
+		data = withValueDecimal(data, ev.ContractAddr)
+	}
+	return ExportRecord{
+		Type:        ev.Type,
+		ID:          ev.ID,
+		BlockNumber: ev.BlockNumber,
+		TxHash:      ev.TxHash,
+		TxIndex:     ev.TxIndex,
+		LogIndex:    ev.LogIndex,
+		Timestamp:   ev.Timestamp.UTC(),
+		Contract:    ev.ContractName,
+		Event:       ev.EventName,
+		Data:        data,
+	}
+}
+
+// PartitionOpener opens the output for a partition path such as
+// "dt=2024-01-01". It may be called again for a partition already written
+// (if rows arrive out of time order) and must then append.
+type PartitionOpener func(partition string) (io.WriteCloser, error)
+
+// DirPartitionOpener writes Hive-style partitions under dir, one file per
+// partition (dir/dt=2024-01-01/part-00000.<ext>). Files are truncated on
+// first open within an export and appended to afterwards.
+//
+// Parameters:
+//   - dir (string): output root directory
+//   - ext (string): file extension without dot (e.g. "ndjson")
+//
+// Returns:
+//   - PartitionOpener: opener creating partition directories as needed
+func DirPartitionOpener(dir, ext string) PartitionOpener {
+	opened := make(map[string]bool)
+	return func(partition string) (io.WriteCloser, error) {
+		partDir := filepath.Join(dir, filepath.FromSlash(partition))
+		if err := os.MkdirAll(partDir, 0o755); err != nil {
+			return nil, fmt.Errorf("creating partition %s: %w", partition, err)
+		}
+
+		flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+		if !opened[partition] {
+			flags |= os.O_TRUNC
+			opened[partition] = true
+		}
+
+		f, err := os.OpenFile(filepath.Join(partDir, "part-00000."+ext), flags, 0o644) //nolint:gosec // G304: path built from caller's output dir
+		if err != nil {
+			return nil, fmt.Errorf("opening partition %s: %w", partition, err)
+		}
+		return f, nil
+	}
+}
+
+// partitionKey returns the Hive-style partition path of a timestamp (UTC).
+func partitionKey(ts time.Time, granularity string) string {
+	ts = ts.UTC()
+	if granularity == PartitionHour {
+		return fmt.Sprintf("dt=%s/hr=%02d", ts.Format("2006-01-02"), ts.Hour())
+	}
+	return "dt=" + ts.Format("2006-01-02")
+}
+
+// ExportNDJSON streams events and transfers in a range to w as
+// newline-delimited JSON, in canonical block/log order. Output is flushed
+// at checkpoints every few thousand rows; to resume a failed export,
+// truncate the output to the returned Bytes and export again with After
+// set to the returned Cursor, appending to it.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - w (io.Writer): destination
+//   - r (ExportRange): rows to export
+//
+// Returns:
+//   - ExportProgress: rows and bytes written up to the last checkpoint
+//   - error: nil on success, cursor, query or write error on failure
+func (s *Store) ExportNDJSON(ctx context.Context, w io.Writer, r ExportRange) (ExportProgress, error) {
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	enc := json.NewEncoder(bw)
+
+	progress := ExportProgress{Cursor: r.After}
+	var rows int64
+	var last UnifiedEvent
+	checkpoint := func() error {
+		if err := bw.Flush(); err != nil {
+			return fmt.Errorf("flushing export: %w", err)
+		}
+		progress = ExportProgress{Rows: rows, Bytes: cw.n, Cursor: encodeStreamCursor(last)}
+		return nil
+	}
+
+	err := s.streamExport(ctx, r, func(ev UnifiedEvent) error {
+		if err := enc.Encode(newExportRecord(ev, r.FormatDecimals)); err != nil {
+			return fmt.Errorf("writing row: %w", err)
+		}
+		rows++
+		last = ev
+		if rows%exportCheckpointRows == 0 {
+			return checkpoint()
+		}
+		return nil
+	})
+	if err != nil {
+		return progress, err
+	}
+	if rows == 0 {
+		return ExportProgress{Cursor: r.After}, nil
+	}
+	if err := checkpoint(); err != nil {
+		return progress, err
+	}
+	return progress, nil
+}
+
+// ExportPartitioned streams events and transfers in a range as NDJSON,
+// routing each row to the partition of its timestamp (UTC). Only one
+// partition is open at a time: rows arrive in block order, so partitions
+// are finished and closed as time moves on.
+//
+// Checkpoints are taken as partitions are closed: a failed export resumes
+// with After set to the returned Cursor and rewrites the partition it
+// failed in (DirPartitionOpener truncates it on first open).
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - r (ExportRange): rows to export
+//   - granularity (string): PartitionDay or PartitionHour
+//   - open (PartitionOpener): opens each partition's output
+//
+// Returns:
+//   - ExportProgress: rows written per partition up to the last checkpoint
+//   - error: nil on success, cursor, query, open or write error on failure
+func (s *Store) ExportPartitioned(ctx context.Context, r ExportRange, granularity string, open PartitionOpener) (progress ExportProgress, err error) {
+	if granularity != PartitionDay && granularity != PartitionHour {
+		return ExportProgress{}, fmt.Errorf("unknown partition granularity %q", granularity)
+	}
+
+	counts := make(map[string]int64)
+	progress = ExportProgress{Cursor: r.After, Partitions: map[string]int64{}}
+	var (
+		current string
+		out     io.WriteCloser
+		bw      *bufio.Writer
+		enc     *json.Encoder
+		rows    int64
+		last    UnifiedEvent
+	)
+
+	// closeCurrent finishes the open partition, recording a checkpoint
+	// unless the export failed: the failed partition is rewritten on resume.
+	closeCurrent := func(checkpoint bool) error {
+		if out == nil {
+			return nil
+		}
+		flushErr := bw.Flush()
+		closeErr := out.Close()
+		out = nil
+		if err := errors.Join(flushErr, closeErr); err != nil {
+			return fmt.Errorf("closing partition %s: %w", current, err)
+		}
+		if checkpoint {
+			progress = ExportProgress{Rows: rows, Cursor: encodeStreamCursor(last), Partitions: maps.Clone(counts)}
+		}
+		return nil
+	}
+	defer func() {
+		if cerr := closeCurrent(err == nil); err == nil {
+			err = cerr
+		}
+	}()
+
+	err = s.streamExport(ctx, r, func(ev UnifiedEvent) error {
+		key := partitionKey(ev.Timestamp, granularity)
+		if key != current || out == nil {
+			if err := closeCurrent(true); err != nil {
+				return err
+			}
+			w, err := open(key)
+			if err != nil {
+				return err
+			}
+			current, out = key, w
+			bw = bufio.NewWriter(w)
+			enc = json.NewEncoder(bw)
+		}
+
+		if err := enc.Encode(newExportRecord(ev, r.FormatDecimals)); err != nil {
+			return fmt.Errorf("writing row to %s: %w", key, err)
+		}
+		counts[key]++
+		rows++
+		last = ev
+		return nil
+	})
+	return progress, err
+}
+
+// streamExport resolves an export range and streams its rows in canonical
+// order, after r.After if set, dropping rows outside an explicit time range.
+func (s *Store) streamExport(ctx context.Context, r ExportRange, fn func(UnifiedEvent) error) error {
+	fromBlock, toBlock, ok, err := s.resolveExportRange(ctx, r)
+	if err != nil || !ok {
+		return err
+	}
+
+	cursor := UnifiedEvent{BlockNumber: fromBlock}
+	if r.After != "" {
+		after, err := decodeStreamCursor(r.After)
+		if err != nil {
+			return fmt.Errorf("resuming export: %w", err)
+		}
+		if after.BlockNumber >= fromBlock {
+			cursor = after
+		}
+	}
+
+	return s.streamAfter(ctx, fromBlock, toBlock, cursor, func(ev UnifiedEvent) error {
+		if r.FromTime != nil && ev.Timestamp.Before(*r.FromTime) {
+			return nil
+		}
+		if r.ToTime != nil && ev.Timestamp.After(*r.ToTime) {
+			return nil
+		}
+		return fn(ev)
+	})
+}
+
+// resolveExportRange turns an ExportRange into an inclusive block range,
+// using the stored timestamps for missing block bounds.
+//
+// Returns:
+//   - uint64: first block
+//   - uint64: last block
+//   - bool: false if the range matches no rows
+//   - error: nil on success, query error on failure
+func (s *Store) resolveExportRange(ctx context.Context, r ExportRange) (uint64, uint64, bool, error) {
+	var bounds struct {
+		MinBlock *uint64
+		MaxBlock *uint64
+	}
+	if r.FromBlock == nil || r.ToBlock == nil {
+		if err := s.db.WithContext(ctx).Raw(fmt.Sprintf(`
+			SELECT MIN(block_number) AS min_block, MAX(block_number) AS max_block FROM (
+				SELECT block_number, timestamp FROM events
+				UNION ALL%s
+			) u
+			WHERE (@from::timestamptz IS NULL OR timestamp >= @from)
+				AND (@to::timestamptz IS NULL OR timestamp <= @to)
+		`, unionTransferTables(`
+				SELECT block_number, timestamp FROM %[1]q`)), map[string]any{"from": r.FromTime, "to": r.ToTime}).Scan(&bounds).Error; err != nil {
+			return 0, 0, false, fmt.Errorf("resolving export range: %w", err)
+		}
+		if bounds.MinBlock == nil {
+			return 0, 0, false, nil
+		}
+	}
+
+	fromBlock, toBlock := bounds.MinBlock, bounds.MaxBlock
+	if r.FromBlock != nil {
+		fromBlock = r.FromBlock
+	}
+	if r.ToBlock != nil {
+		toBlock = r.ToBlock
+	}
+	if *fromBlock > *toBlock {
+		return 0, 0, false, nil
+	}
+	return *fromBlock, *toBlock, true, nil
+}
@@ -0,0 +1,139 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// HealthStats reports connection pool state and replication status.
+type HealthStats struct {
+	// MaxOpenConnections is the configured pool limit.
+	MaxOpenConnections int
+
+	// OpenConnections is the number of established connections (in use + idle).
+	OpenConnections int
+
+	// InUse is the number of connections currently in use.
+	InUse int
+
+	// Idle is the number of idle connections.
+	Idle int
+
+	// WaitCount is the total number of waits for a connection.
+	WaitCount int64
+
+	// WaitDuration is the total time spent waiting for connections.
+	WaitDuration time.Duration
+
+	// IsReplica is true when connected to a standby server.
+	IsReplica bool
+
+	// ReplicationLag is the replay delay on a standby; nil on a primary.
+	ReplicationLag *time.Duration
+}
+
+// Ping verifies the database is usable by running a lightweight query
+// bounded by the context deadline.
+//
+// Parameters:
+//   - ctx (context.Context): request context (deadline applies)
+//
+// Returns:
+//   - error: nil if the database responded, error otherwise
+func (s *Store) Ping(ctx context.Context) error {
+	var one int
+	if err := s.db.WithContext(ctx).Raw("SELECT 1").Scan(&one).Error; err != nil {
+		return fmt.Errorf("pinging database: %w", err)
+	}
+	return nil
+}
+
+// DatabaseSize returns the disk space used by the current database.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//
+// Returns:
+//   - int64: size in bytes
+//   - error: nil on success, query error on failure
+func (s *Store) DatabaseSize(ctx context.Context) (int64, error) {
+	var size int64
+	if err := s.db.WithContext(ctx).Raw("SELECT pg_database_size(current_database())").Scan(&size).Error; err != nil {
+		return 0, fmt.Errorf("getting database size: %w", err)
+	}
+	return size, nil
+}
+
+// AnalyzeTables refreshes planner statistics on the events and transfer
+// tables, contract tables included. Without TimescaleDB the tables are also vacuumed; hypertable
+// chunks are left to autovacuum, as a full VACUUM across them is costly.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//
+// Returns:
+//   - error: nil on success, error on failure
+func (s *Store) AnalyzeTables(ctx context.Context) error {
+	command := "VACUUM ANALYZE"
+	if s.hasTimescaleDB {
+		command = "ANALYZE"
+	}
+
+	db := s.db.WithContext(ctx)
+	for _, table := range append([]string{"events"}, transferTableNames()...) {
+		if !db.Migrator().HasTable(table) {
+			continue
+		}
+		if err := db.Exec(fmt.Sprintf("%s %q", command, table)).Error; err != nil {
+			return fmt.Errorf("analyzing %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// HealthStats returns connection pool statistics and, when connected to a
+// standby, its replication lag.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//
+// Returns:
+//   - HealthStats: pool and replication stats
+//   - error: nil on success, query error on failure
+func (s *Store) HealthStats(ctx context.Context) (HealthStats, error) {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return HealthStats{}, fmt.Errorf("getting underlying DB: %w", err)
+	}
+
+	stats := sqlDB.Stats()
+	health := HealthStats{
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDuration:       stats.WaitDuration,
+	}
+
+	if err := s.db.WithContext(ctx).Raw("SELECT pg_is_in_recovery()").Scan(&health.IsReplica).Error; err != nil {
+		return health, fmt.Errorf("checking recovery status: %w", err)
+	}
+
+	if health.IsReplica {
+		// NULL until the standby has replayed a transaction
+		var lagSeconds *float64
+		if err := s.db.WithContext(ctx).
+			Raw("SELECT EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp())").
+			Scan(&lagSeconds).Error; err != nil {
+			return health, fmt.Errorf("getting replication lag: %w", err)
+		}
+		if lagSeconds != nil {
+			lag := time.Duration(*lagSeconds * float64(time.Second))
+			health.ReplicationLag = &lag
+		}
+	}
+
+	return health, nil
+}
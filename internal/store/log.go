@@ -0,0 +1,55 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm/logger"
+
+	"github.com/0xredeth/Rafale/internal/logging"
+)
+
+// log is the store subsystem's logger (level set by log.levels.store).
+var log = logging.New("store")
+
+// gormLogger is GORM's logger at a level following log.levels.store, so
+// a reload applies to it: a configured store level maps to GORM's
+// (GormLogLevel), otherwise fallback applies. The default level alone
+// (e.g. --verbose) doesn't turn on logging of every SQL statement.
+type gormLogger struct {
+	fallback logger.LogLevel
+}
+
+// level returns the effective GORM log level.
+func (l gormLogger) level() logger.LogLevel {
+	if storeLevel, ok := logging.ConfiguredLevel("store"); ok {
+		return GormLogLevel(storeLevel)
+	}
+	return l.fallback
+}
+
+// current returns GORM's default logger at the effective level.
+func (l gormLogger) current() logger.Interface {
+	return logger.Default.LogMode(l.level())
+}
+
+// LogMode returns a logger fixed at level, as for db.Debug().
+func (l gormLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return logger.Default.LogMode(level)
+}
+
+func (l gormLogger) Info(ctx context.Context, msg string, args ...any) {
+	l.current().Info(ctx, msg, args...)
+}
+
+func (l gormLogger) Warn(ctx context.Context, msg string, args ...any) {
+	l.current().Warn(ctx, msg, args...)
+}
+
+func (l gormLogger) Error(ctx context.Context, msg string, args ...any) {
+	l.current().Error(ctx, msg, args...)
+}
+
+func (l gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	l.current().Trace(ctx, begin, fc, err)
+}
@@ -32,9 +32,15 @@ func (b *BaseEvent) BeforeCreate(_ *gorm.DB) error {
 // Stores USDC transfers on Linea with indexed fields for efficient queries.
 type Transfer struct {
 	BaseEvent
-	From  string `gorm:"type:varchar(42);index;not null"`
-	To    string `gorm:"type:varchar(42);index;not null"`
-	Value string `gorm:"type:numeric(78);not null"` // uint256 max is 78 digits
+	ContractAddr string `gorm:"type:varchar(42);index"` // emitting contract; empty on rows stored before it was recorded
+	From         string `gorm:"type:varchar(42);index;not null"`
+	To           string `gorm:"type:varchar(42);index;not null"`
+	Value        string `gorm:"type:numeric(78);not null"` // uint256 max is 78 digits
+
+	// ValueDecimal is Value scaled by the contract's decimals (see
+	// RegisterDecimals). Populated only when requested via
+	// TransferQuery.FormatDecimals and the decimals are known; not persisted.
+	ValueDecimal string `gorm:"-"`
 }
 
 // TableName returns the table name for Transfer.
@@ -51,10 +57,75 @@ type Event struct {
 	ContractAddr string         `gorm:"type:varchar(42);index:idx_events_address;not null"`
 	EventName    string         `gorm:"type:varchar(100);index:idx_events_event;not null"`
 	EventSig     string         `gorm:"type:varchar(66);index;not null"` // Topic[0] hash
+	BlockHash    string         `gorm:"type:varchar(66)"`                // empty on rows stored before it was recorded
 	Data         datatypes.JSON `gorm:"type:jsonb;not null"`
+
+	// RawTopics and RawData hold the undecoded log (topics as a JSON array
+	// of hex strings). Stored only when sync.store_raw_log is enabled, so
+	// Data can be re-decoded later without RPC.
+	RawTopics datatypes.JSON `gorm:"type:jsonb"`
+	RawData   []byte         `gorm:"type:bytea"`
+
+	// DataMap is Data unmarshaled into a map, with numbers as json.Number.
+	// Populated only when requested via EventQuery.DecodeData; not persisted.
+	DataMap map[string]any `gorm:"-"`
 }
 
 // TableName returns the table name for Event.
 func (Event) TableName() string {
 	return "events"
 }
+
+// SyncStatusAll is the SyncStatus key for the engine-wide cursor.
+const SyncStatusAll = "*"
+
+// SyncStatus is a sync cursor: the last block whose events are fully
+// committed. The engine updates it in the same transaction as each batch.
+type SyncStatus struct {
+	Contract      string    `gorm:"primaryKey;type:varchar(100)"` // SyncStatusAll for the engine-wide cursor
+	LastBlock     uint64    `gorm:"not null"`
+	LastBlockHash string    `gorm:"type:varchar(66)"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName returns the table name for SyncStatus.
+func (SyncStatus) TableName() string {
+	return "sync_statuses"
+}
+
+// BlockCoverage records that a block was processed and how many logs
+// matched the filter in it (possibly zero). A block without a row was
+// never processed.
+type BlockCoverage struct {
+	BlockNumber uint64    `gorm:"primaryKey;autoIncrement:false"`
+	LogCount    int       `gorm:"not null"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName returns the table name for BlockCoverage.
+func (BlockCoverage) TableName() string {
+	return "block_coverage"
+}
+
+// RevertedTx is a failed transaction sent to an indexed contract, stored
+// when sync.track_reverts is enabled. Failed transactions emit no logs,
+// so they are found by scanning block transactions.
+type RevertedTx struct {
+	ID           uint64    `gorm:"primaryKey;autoIncrement"`
+	Timestamp    time.Time `gorm:"index;not null"`
+	BlockNumber  uint64    `gorm:"index;not null"`
+	TxHash       string    `gorm:"type:varchar(66);uniqueIndex;not null"`
+	TxIndex      uint      `gorm:"not null"`
+	ContractName string    `gorm:"type:varchar(100);index;not null"`
+	From         string    `gorm:"type:varchar(42);index"` // empty if the sender couldn't be recovered
+	To           string    `gorm:"type:varchar(42);not null"`
+	GasUsed      uint64    `gorm:"not null"`
+	Reason       string    `gorm:"type:text"` // decoded revert reason; empty if the replay didn't revert
+	RevertData   []byte    `gorm:"type:bytea"`
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName returns the table name for RevertedTx.
+func (RevertedTx) TableName() string {
+	return "reverted_txs"
+}
@@ -0,0 +1,202 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+	"gorm.io/gorm"
+)
+
+// parquetRowGroupSize bounds the rows buffered in memory before a row
+// group is flushed to the output.
+const parquetRowGroupSize = 16 * 1024 * 1024
+
+// transferParquetRow is the Parquet schema of an exported transfer. Value
+// is a decimal string: uint256 exceeds Parquet's 38-digit DECIMAL precision.
+type transferParquetRow struct {
+	ID           int64  `parquet:"name=id, type=INT64, convertedtype=UINT_64"`
+	BlockNumber  int64  `parquet:"name=block_number, type=INT64, convertedtype=UINT_64"`
+	TxHash       string `parquet:"name=tx_hash, type=BYTE_ARRAY, convertedtype=UTF8"`
+	TxIndex      int32  `parquet:"name=tx_index, type=INT32, convertedtype=UINT_32"`
+	LogIndex     int32  `parquet:"name=log_index, type=INT32, convertedtype=UINT_32"`
+	Timestamp    int64  `parquet:"name=timestamp, type=INT64, convertedtype=TIMESTAMP_MICROS"`
+	ContractAddr string `parquet:"name=contract_addr, type=BYTE_ARRAY, convertedtype=UTF8"`
+	From         string `parquet:"name=from, type=BYTE_ARRAY, convertedtype=UTF8"`
+	To           string `parquet:"name=to, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Value        string `parquet:"name=value, type=BYTE_ARRAY, convertedtype=UTF8"`
+
+	// ValueDecimal is set with TransferQuery.FormatDecimals when the
+	// contract's decimals are known.
+	ValueDecimal *string `parquet:"name=value_decimal, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+}
+
+// eventParquetRow is the Parquet schema of an exported event. Data is the
+// decoded event as a JSON string.
+type eventParquetRow struct {
+	ID           int64  `parquet:"name=id, type=INT64, convertedtype=UINT_64"`
+	BlockNumber  int64  `parquet:"name=block_number, type=INT64, convertedtype=UINT_64"`
+	TxHash       string `parquet:"name=tx_hash, type=BYTE_ARRAY, convertedtype=UTF8"`
+	TxIndex      int32  `parquet:"name=tx_index, type=INT32, convertedtype=UINT_32"`
+	LogIndex     int32  `parquet:"name=log_index, type=INT32, convertedtype=UINT_32"`
+	Timestamp    int64  `parquet:"name=timestamp, type=INT64, convertedtype=TIMESTAMP_MICROS"`
+	ContractName string `parquet:"name=contract_name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ContractAddr string `parquet:"name=contract_addr, type=BYTE_ARRAY, convertedtype=UTF8"`
+	EventName    string `parquet:"name=event_name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	EventSig     string `parquet:"name=event_sig, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Data         string `parquet:"name=data, type=BYTE_ARRAY, convertedtype=UTF8"`
+}
+
+// ExportTransfersParquet streams transfers matching q into a Parquet file
+// written to w. Rows are read through a database cursor and flushed in row
+// groups, so memory stays bounded on large exports. Pagination fields
+// (AfterID, BeforeID, AfterKey, BeforeKey, Limit) apply as in QueryTransfers.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - w (io.Writer): destination
+//   - q (TransferQuery): query parameters
+//
+// Returns:
+//   - int64: number of rows written
+//   - error: nil on success, query or write error on failure
+func (s *Store) ExportTransfersParquet(ctx context.Context, w io.Writer, q TransferQuery) (int64, error) {
+	if err := s.checkIDCursors(q.AfterID, q.BeforeID); err != nil {
+		return 0, err
+	}
+	query := s.filterTransfers(s.transfersQuery(ctx, q), q)
+	query = keyPage(query, TransferTable(q.Contract), q.AfterKey, q.BeforeKey)
+	query = exportPage(query, q.AfterID, q.BeforeID, q.Limit).Order(queryOrder(q.OrderBy, q.OrderDir, s.tiebreak()))
+
+	return exportParquet(s.db, query, w, func(t *Transfer) transferParquetRow {
+		var formatted *string
+		if q.FormatDecimals {
+			if v := valueDecimal(t.ContractAddr, t.Value); v != "" {
+				formatted = &v
+			}
+		}
+		return transferParquetRow{
+			ID:           int64(t.ID),          //nolint:gosec // G115: ids fit in int64
+			BlockNumber:  int64(t.BlockNumber), //nolint:gosec // G115: block numbers fit in int64
+			TxHash:       t.TxHash,
+			TxIndex:      int32(t.TxIndex),  //nolint:gosec // G115: tx index fits in int32
+			LogIndex:     int32(t.LogIndex), //nolint:gosec // G115: log index fits in int32
+			Timestamp:    t.Timestamp.UnixMicro(),
+			ContractAddr: t.ContractAddr,
+			From:         t.From,
+			To:           t.To,
+			Value:        t.Value,
+			ValueDecimal: formatted,
+		}
+	})
+}
+
+// ExportEventsParquet streams events matching q into a Parquet file
+// written to w, like ExportTransfersParquet. DecodeData is ignored: the
+// data column holds the stored JSON.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - w (io.Writer): destination
+//   - q (EventQuery): query parameters
+//
+// Returns:
+//   - int64: number of rows written
+//   - error: nil on success, invalid filter, query or write error on failure
+func (s *Store) ExportEventsParquet(ctx context.Context, w io.Writer, q EventQuery) (int64, error) {
+	if err := validateNameFilter("contract names", q.ContractNames); err != nil {
+		return 0, err
+	}
+	if err := validateNameFilter("event names", q.EventNames); err != nil {
+		return 0, err
+	}
+	if err := validateDataFilters(q.DataFilters); err != nil {
+		return 0, err
+	}
+	if err := s.checkIDCursors(q.AfterID, q.BeforeID); err != nil {
+		return 0, err
+	}
+
+	query := filterEvents(s.db.WithContext(ctx).Model(&Event{}), q)
+	query = keyPage(query, "events", q.AfterKey, q.BeforeKey)
+	query = exportPage(query, q.AfterID, q.BeforeID, q.Limit).Order(queryOrder(q.OrderBy, q.OrderDir, s.tiebreak()))
+
+	return exportParquet(s.db, query, w, func(e *Event) eventParquetRow {
+		return eventParquetRow{
+			ID:           int64(e.ID),          //nolint:gosec // G115: ids fit in int64
+			BlockNumber:  int64(e.BlockNumber), //nolint:gosec // G115: block numbers fit in int64
+			TxHash:       e.TxHash,
+			TxIndex:      int32(e.TxIndex),  //nolint:gosec // G115: tx index fits in int32
+			LogIndex:     int32(e.LogIndex), //nolint:gosec // G115: log index fits in int32
+			Timestamp:    e.Timestamp.UnixMicro(),
+			ContractName: e.ContractName,
+			ContractAddr: e.ContractAddr,
+			EventName:    e.EventName,
+			EventSig:     e.EventSig,
+			Data:         string(e.Data),
+		}
+	})
+}
+
+// exportPage applies cursor pagination and a limit to an export query.
+func exportPage(query *gorm.DB, afterID, beforeID *uint64, limit int) *gorm.DB {
+	if afterID != nil {
+		query = query.Where("id > ?", *afterID)
+	}
+	if beforeID != nil {
+		query = query.Where("id < ?", *beforeID)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	return query
+}
+
+// exportParquet iterates query with a cursor, converting each model M to
+// a Parquet row R and writing it through a streaming Parquet writer.
+//
+// Parameters:
+//   - db (*gorm.DB): database used to scan rows into M
+//   - query (*gorm.DB): filtered and ordered query
+//   - w (io.Writer): destination
+//   - toRow (func(*M) R): model to row conversion
+//
+// Returns:
+//   - int64: number of rows written
+//   - error: nil on success, query or write error on failure
+func exportParquet[M, R any](db, query *gorm.DB, w io.Writer, toRow func(*M) R) (int64, error) {
+	pw, err := writer.NewParquetWriterFromWriter(w, new(R), 4)
+	if err != nil {
+		return 0, fmt.Errorf("creating parquet writer: %w", err)
+	}
+	pw.RowGroupSize = parquetRowGroupSize
+	pw.CompressionType = parquet.CompressionCodec_SNAPPY
+
+	rows, err := query.Rows()
+	if err != nil {
+		return 0, fmt.Errorf("querying export rows: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var count int64
+	for rows.Next() {
+		var m M
+		if err := db.ScanRows(rows, &m); err != nil {
+			return count, fmt.Errorf("scanning export row: %w", err)
+		}
+		if err := pw.Write(toRow(&m)); err != nil {
+			return count, fmt.Errorf("writing parquet row: %w", err)
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return count, fmt.Errorf("iterating export rows: %w", err)
+	}
+
+	if err := pw.WriteStop(); err != nil {
+		return count, fmt.Errorf("finishing parquet file: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,69 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// TransferValuePercentiles returns percentiles of the values of transfers
+// matching q's range filters. Percentiles are nearest-rank
+// (percentile_disc), so each result is an actual transfer value and keeps
+// full uint256 precision; interpolated percentiles would be fractional.
+// Pagination and ordering fields are ignored.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - q (TransferQuery): range filters
+//   - pcts ([]float64): percentiles as fractions in [0, 1] (e.g. 0.99)
+//
+// Returns:
+//   - map[float64]*big.Int: value per requested percentile (empty if no transfer matches)
+//   - error: nil on success, error for an invalid percentile or query failure
+func (s *Store) TransferValuePercentiles(ctx context.Context, q TransferQuery, pcts []float64) (map[float64]*big.Int, error) {
+	if len(pcts) == 0 {
+		return nil, fmt.Errorf("transfer value percentiles: at least one percentile is required")
+	}
+
+	columns := make([]string, len(pcts))
+	args := make([]interface{}, len(pcts))
+	for i, p := range pcts {
+		if p < 0 || p > 1 {
+			return nil, fmt.Errorf("transfer value percentiles: %v is not in [0, 1]", p)
+		}
+		columns[i] = "percentile_disc(?::float8) WITHIN GROUP (ORDER BY value)::text"
+		args[i] = p
+	}
+
+	start := time.Now()
+
+	query := s.filterTransfers(s.transfersQuery(ctx, q), q).
+		Select(strings.Join(columns, ", "), args...)
+
+	values := make([]sql.NullString, len(pcts))
+	dest := make([]interface{}, len(pcts))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := query.Row().Scan(dest...); err != nil {
+		return nil, fmt.Errorf("querying transfer value percentiles: %w", err)
+	}
+
+	result := make(map[float64]*big.Int, len(pcts))
+	for i, v := range values {
+		if !v.Valid {
+			continue // no matching transfers
+		}
+		n, ok := new(big.Int).SetString(v.String, 10)
+		if !ok {
+			return nil, fmt.Errorf("parsing percentile value %q", v.String)
+		}
+		result[pcts[i]] = n
+	}
+
+	dbQueryDuration.WithLabelValues("transfer_value_percentiles").Observe(time.Since(start).Seconds())
+	return result, nil
+}
@@ -0,0 +1,237 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Primary key strategies for Config.PrimaryKey.
+const (
+	// PrimaryKeyID keys event rows by the auto-increment id (with
+	// timestamp, required by TimescaleDB).
+	PrimaryKeyID = "id"
+
+	// PrimaryKeyNatural keys event rows by (tx_hash, log_index, timestamp),
+	// so rows from several instances or shards merge without id clashes
+	// and inserts don't all land on the right edge of one index. The id
+	// column is still filled but no longer indexed: read rows by EventKey
+	// (id lookups and cursors return ErrIDNotIndexed).
+	PrimaryKeyNatural = "natural"
+)
+
+// ErrIDNotIndexed is returned for lookups and cursors by id under
+// PrimaryKeyNatural, where the id column isn't indexed (and isn't unique
+// across merged instances); use the EventKey variants instead.
+var ErrIDNotIndexed = errors.New("ids aren't indexed under natural primary keys, use the event key")
+
+// primaryKeyColumns returns the primary key columns of a strategy.
+func primaryKeyColumns(strategy string) []string {
+	if strategy == PrimaryKeyNatural {
+		return []string{"tx_hash", "log_index", "timestamp"}
+	}
+	return []string{"id", "timestamp"}
+}
+
+// EventKey is the natural key of an event or transfer row.
+type EventKey struct {
+	TxHash   string
+	LogIndex uint
+}
+
+// String returns the key as "txHash:logIndex".
+func (k EventKey) String() string {
+	return k.TxHash + ":" + strconv.FormatUint(uint64(k.LogIndex), 10)
+}
+
+// Cursor returns the key as an opaque pagination cursor.
+func (k EventKey) Cursor() string {
+	return EncodeCursor(k.TxHash, strconv.FormatUint(uint64(k.LogIndex), 10))
+}
+
+// ParseEventKey parses a key formatted by EventKey.String.
+//
+// Parameters:
+//   - s (string): "txHash:logIndex"
+//
+// Returns:
+//   - EventKey: the key
+//   - error: nil on success, error if s is malformed
+func ParseEventKey(s string) (EventKey, error) {
+	txHash, logIndex, ok := strings.Cut(s, ":")
+	if !ok {
+		return EventKey{}, fmt.Errorf("parsing event key %q: want txHash:logIndex", s)
+	}
+	return newEventKey(txHash, logIndex)
+}
+
+// DecodeEventKeyCursor decodes a cursor made by EventKey.Cursor.
+//
+// Parameters:
+//   - cursor (string): opaque cursor
+//
+// Returns:
+//   - EventKey: the key
+//   - error: nil on success, error if the cursor is malformed
+func DecodeEventKeyCursor(cursor string) (EventKey, error) {
+	parts, err := DecodeCursor(cursor, 2)
+	if err != nil {
+		return EventKey{}, err
+	}
+	return newEventKey(parts[0], parts[1])
+}
+
+// newEventKey validates and builds an EventKey.
+func newEventKey(txHash, logIndex string) (EventKey, error) {
+	if !strings.HasPrefix(txHash, "0x") || len(txHash) != 66 {
+		return EventKey{}, fmt.Errorf("invalid event key tx hash %q", txHash)
+	}
+	index, err := strconv.ParseUint(logIndex, 10, 32)
+	if err != nil {
+		return EventKey{}, fmt.Errorf("invalid event key log index %q: %w", logIndex, err)
+	}
+	return EventKey{TxHash: txHash, LogIndex: uint(index)}, nil
+}
+
+// NaturalKeys reports whether event tables are keyed by PrimaryKeyNatural.
+//
+// Returns:
+//   - bool: true with natural keys, false with ids
+func (s *Store) NaturalKeys() bool {
+	return s.primaryKey == PrimaryKeyNatural
+}
+
+// EnsurePrimaryKey sets the primary key of an event table (events,
+// transfers or a table with their schema) to the configured strategy,
+// replacing the key it was created with. Switching rebuilds the key
+// index; TimescaleDB refuses it on compressed hypertables.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - table (string): table name
+//
+// Returns:
+//   - error: nil on success, catalog or DDL error on failure
+func (s *Store) EnsurePrimaryKey(ctx context.Context, table string) error {
+	want := primaryKeyColumns(s.primaryKey)
+	db := s.db.WithContext(ctx)
+
+	var current struct {
+		Name    string
+		Columns string
+	}
+	if err := db.Raw(`
+		SELECT c.conname AS name, string_agg(a.attname, ',' ORDER BY k.ord) AS columns
+		FROM pg_constraint c
+		CROSS JOIN LATERAL unnest(c.conkey) WITH ORDINALITY AS k(attnum, ord)
+		JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = k.attnum
+		WHERE c.conrelid = ?::regclass AND c.contype = 'p'
+		GROUP BY c.conname
+	`, table).Scan(&current).Error; err != nil {
+		return fmt.Errorf("reading primary key of %s: %w", table, err)
+	}
+	if current.Name != "" && slices.Equal(strings.Split(current.Columns, ","), want) {
+		return nil
+	}
+
+	quoted := make([]string, len(want))
+	for i, column := range want {
+		quoted[i] = fmt.Sprintf("%q", column)
+	}
+	stmt := fmt.Sprintf(`ALTER TABLE %q ADD PRIMARY KEY (%s)`, table, strings.Join(quoted, ", "))
+	if current.Name != "" {
+		stmt = fmt.Sprintf(`ALTER TABLE %q DROP CONSTRAINT %q, ADD PRIMARY KEY (%s)`, table, current.Name, strings.Join(quoted, ", "))
+	}
+	if err := db.Exec(stmt).Error; err != nil {
+		return fmt.Errorf("setting primary key of %s to (%s) (duplicate keys must be removed first): %w", table, strings.Join(want, ", "), err)
+	}
+	log.Info().Str("table", table).Strs("columns", want).Msg("primary key changed")
+	return nil
+}
+
+// checkIDCursors rejects id cursors under natural keys.
+//
+// Parameters:
+//   - afterID (*uint64): after cursor, or nil
+//   - beforeID (*uint64): before cursor, or nil
+//
+// Returns:
+//   - error: ErrIDNotIndexed if a cursor is set under natural keys, else nil
+func (s *Store) checkIDCursors(afterID, beforeID *uint64) error {
+	if s.NaturalKeys() && (afterID != nil || beforeID != nil) {
+		return fmt.Errorf("paginating by id: %w", ErrIDNotIndexed)
+	}
+	return nil
+}
+
+// tiebreak returns the column ordering rows within a block: id with id
+// keys, log_index with natural keys (unique within a block).
+func (s *Store) tiebreak() string {
+	if s.NaturalKeys() {
+		return "log_index"
+	}
+	return "id"
+}
+
+// keyPage applies key cursors to a query on table: rows after or before
+// the keyed row in (block_number, log_index) order. A key matching no row
+// matches nothing.
+func keyPage(query *gorm.DB, table string, after, before *EventKey) *gorm.DB {
+	position := fmt.Sprintf(`(SELECT block_number, log_index FROM %q WHERE tx_hash = ? AND log_index = ? LIMIT 1)`, table)
+	if after != nil {
+		query = query.Where("(block_number, log_index) > "+position, after.TxHash, after.LogIndex)
+	}
+	if before != nil {
+		query = query.Where("(block_number, log_index) < "+position, before.TxHash, before.LogIndex)
+	}
+	return query
+}
+
+// GetEventByKey retrieves a single generic event by its natural key.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - key (EventKey): event key
+//
+// Returns:
+//   - *Event: the event or nil if not found
+//   - error: nil on success, query error on failure
+func (s *Store) GetEventByKey(ctx context.Context, key EventKey) (*Event, error) {
+	var event Event
+	if err := s.db.WithContext(ctx).
+		Where("tx_hash = ? AND log_index = ?", key.TxHash, key.LogIndex).
+		First(&event).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("getting event %s: %w", key, err)
+	}
+	return &event, nil
+}
+
+// GetTransferByKey retrieves a single transfer by its natural key.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - key (EventKey): transfer key
+//
+// Returns:
+//   - *Transfer: the transfer or nil if not found
+//   - error: nil on success, query error on failure
+func (s *Store) GetTransferByKey(ctx context.Context, key EventKey) (*Transfer, error) {
+	var transfer Transfer
+	if err := s.db.WithContext(ctx).
+		Where("tx_hash = ? AND log_index = ?", key.TxHash, key.LogIndex).
+		First(&transfer).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("getting transfer %s: %w", key, err)
+	}
+	return &transfer, nil
+}
@@ -0,0 +1,193 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrUnboundedQuery is returned by ReadOnlyStore queries lacking a range
+// or exceeding the allowed span.
+var ErrUnboundedQuery = errors.New("query range not allowed")
+
+// ReadOnlyConfig bounds the queries a ReadOnlyStore runs on behalf of
+// untrusted callers.
+type ReadOnlyConfig struct {
+	// MaxLimit caps the rows returned; zero or larger limits are clamped to it.
+	MaxLimit int
+
+	// RequireRange rejects queries without both block bounds or both time bounds.
+	RequireRange bool
+
+	// MaxBlockSpan caps the blocks between FromBlock and ToBlock (0 = unlimited).
+	MaxBlockSpan uint64
+
+	// MaxTimeSpan caps the time between FromTime and ToTime (0 = unlimited).
+	MaxTimeSpan time.Duration
+
+	// StatementTimeout aborts each query running longer (0 = server default).
+	StatementTimeout time.Duration
+}
+
+// DefaultReadOnlyConfig returns the read-only defaults for a public API.
+//
+// Returns:
+//   - ReadOnlyConfig: 1000 rows max, a required range of at most 100k blocks
+//     or 31 days, 5s statement timeout
+func DefaultReadOnlyConfig() ReadOnlyConfig {
+	return ReadOnlyConfig{
+		MaxLimit:         1000,
+		RequireRange:     true,
+		MaxBlockSpan:     100_000,
+		MaxTimeSpan:      31 * 24 * time.Hour,
+		StatementTimeout: 5 * time.Second,
+	}
+}
+
+// ReadOnlyStore is the hardened query path for untrusted callers: it
+// clamps limits and validates ranges before delegating to the Store, and
+// runs each query in a read-only transaction with a statement timeout.
+type ReadOnlyStore struct {
+	store *Store
+	cfg   ReadOnlyConfig
+}
+
+// ReadOnly returns a read-only view of the store enforcing cfg.
+//
+// Parameters:
+//   - cfg (ReadOnlyConfig): query bounds
+//
+// Returns:
+//   - *ReadOnlyStore: the read-only view
+func (s *Store) ReadOnly(cfg ReadOnlyConfig) *ReadOnlyStore {
+	return &ReadOnlyStore{store: s, cfg: cfg}
+}
+
+// QueryTransfers is Store.QueryTransfers with the read-only bounds applied.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - q (TransferQuery): query parameters (Limit is clamped)
+//
+// Returns:
+//   - []Transfer: matching transfers
+//   - int64: total count matching filters (before pagination)
+//   - error: nil on success, ErrUnboundedQuery for a disallowed range, query error on failure
+func (r *ReadOnlyStore) QueryTransfers(ctx context.Context, q TransferQuery) ([]Transfer, int64, error) {
+	if err := r.checkRange(q.FromBlock, q.ToBlock, q.FromTime, q.ToTime); err != nil {
+		return nil, 0, err
+	}
+	q.Limit = r.clampLimit(q.Limit)
+
+	var (
+		transfers []Transfer
+		total     int64
+	)
+	err := r.within(ctx, func(s *Store) (err error) {
+		transfers, total, err = s.QueryTransfers(ctx, q)
+		return err
+	})
+	return transfers, total, err
+}
+
+// GetTransfersByAddresses is Store.GetTransfersByAddresses with the
+// read-only bounds applied.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - addrs ([]string): addresses to match against from and to
+//   - q (TransferQuery): query parameters (Limit is clamped)
+//
+// Returns:
+//   - []Transfer: matching transfers
+//   - int64: total count matching filters (before pagination)
+//   - error: nil on success, ErrUnboundedQuery or ErrTooManyAddresses, query error on failure
+func (r *ReadOnlyStore) GetTransfersByAddresses(ctx context.Context, addrs []string, q TransferQuery) ([]Transfer, int64, error) {
+	if err := r.checkRange(q.FromBlock, q.ToBlock, q.FromTime, q.ToTime); err != nil {
+		return nil, 0, err
+	}
+	q.Limit = r.clampLimit(q.Limit)
+
+	var (
+		transfers []Transfer
+		total     int64
+	)
+	err := r.within(ctx, func(s *Store) (err error) {
+		transfers, total, err = s.GetTransfersByAddresses(ctx, addrs, q)
+		return err
+	})
+	return transfers, total, err
+}
+
+// QueryEvents is Store.QueryEvents with the read-only bounds applied.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - q (EventQuery): query parameters (Limit is clamped)
+//
+// Returns:
+//   - []Event: matching events
+//   - int64: total count matching filters (before pagination)
+//   - error: nil on success, ErrUnboundedQuery for a disallowed range, query error on failure
+func (r *ReadOnlyStore) QueryEvents(ctx context.Context, q EventQuery) ([]Event, int64, error) {
+	if err := r.checkRange(q.FromBlock, q.ToBlock, q.FromTime, q.ToTime); err != nil {
+		return nil, 0, err
+	}
+	q.Limit = r.clampLimit(q.Limit)
+
+	var (
+		events []Event
+		total  int64
+	)
+	err := r.within(ctx, func(s *Store) (err error) {
+		events, total, err = s.QueryEvents(ctx, q)
+		return err
+	})
+	return events, total, err
+}
+
+// clampLimit returns the row limit to use for a requested limit.
+func (r *ReadOnlyStore) clampLimit(limit int) int {
+	if r.cfg.MaxLimit > 0 && (limit <= 0 || limit > r.cfg.MaxLimit) {
+		return r.cfg.MaxLimit
+	}
+	return limit
+}
+
+// checkRange validates a query's block and time bounds against the config.
+func (r *ReadOnlyStore) checkRange(fromBlock, toBlock *uint64, fromTime, toTime *time.Time) error {
+	blocks := fromBlock != nil && toBlock != nil
+	times := fromTime != nil && toTime != nil
+	if r.cfg.RequireRange && !blocks && !times {
+		return fmt.Errorf("%w: both block bounds or both time bounds are required", ErrUnboundedQuery)
+	}
+
+	if blocks && r.cfg.MaxBlockSpan > 0 && *toBlock >= *fromBlock && *toBlock-*fromBlock+1 > r.cfg.MaxBlockSpan {
+		return fmt.Errorf("%w: %d blocks exceeds limit of %d", ErrUnboundedQuery, *toBlock-*fromBlock+1, r.cfg.MaxBlockSpan)
+	}
+	if times && r.cfg.MaxTimeSpan > 0 && toTime.Sub(*fromTime) > r.cfg.MaxTimeSpan {
+		return fmt.Errorf("%w: %s exceeds limit of %s", ErrUnboundedQuery, toTime.Sub(*fromTime), r.cfg.MaxTimeSpan)
+	}
+	return nil
+}
+
+// within runs fn against a store bound to a read-only transaction with the
+// configured statement timeout.
+func (r *ReadOnlyStore) within(ctx context.Context, fn func(s *Store) error) error {
+	return r.store.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SET TRANSACTION READ ONLY").Error; err != nil {
+			return fmt.Errorf("starting read-only transaction: %w", err)
+		}
+		if r.cfg.StatementTimeout > 0 {
+			if err := tx.Exec(fmt.Sprintf("SET LOCAL statement_timeout = %d", r.cfg.StatementTimeout.Milliseconds())).Error; err != nil {
+				return fmt.Errorf("setting statement timeout: %w", err)
+			}
+		}
+		clone := *r.store
+		clone.db = tx
+		return fn(&clone)
+	})
+}
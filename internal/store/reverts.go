@@ -0,0 +1,31 @@
+package store
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateRevertedTxsTx inserts reverted transactions using an existing
+// transaction. Transactions already stored (e.g. by a reprocessed batch)
+// are left as is.
+//
+// Parameters:
+//   - tx (*gorm.DB): database transaction
+//   - reverts ([]RevertedTx): reverted transactions to insert
+//
+// Returns:
+//   - error: nil on success, insert error on failure
+func CreateRevertedTxsTx(tx *gorm.DB, reverts []RevertedTx) error {
+	if len(reverts) == 0 {
+		return nil
+	}
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tx_hash"}},
+		DoNothing: true,
+	}).CreateInBatches(reverts, 1000).Error; err != nil {
+		return fmt.Errorf("inserting reverted transactions: %w", err)
+	}
+	return nil
+}
@@ -2,15 +2,26 @@
 package store
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"math"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
-	"github.com/rs/zerolog/log"
+	"github.com/rs/zerolog"
+	"gorm.io/datatypes"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
 )
 
@@ -37,8 +48,21 @@ var (
 type Store struct {
 	db             *gorm.DB
 	hasTimescaleDB bool
+	maxAddressSet  int
+	primaryKey     string              // PrimaryKeyID or PrimaryKeyNatural
+	addressCase    string              // AddressCaseLower or AddressCaseChecksum
+	eventCache     *rowCache[Event]    // nil when Config.CacheSize is 0
+	transferCache  *rowCache[Transfer] // nil when Config.CacheSize is 0
 }
 
+// ErrUnboundedDelete is returned by DeleteEvents when no block bound is
+// given and allowUnbounded is false.
+var ErrUnboundedDelete = errors.New("unbounded delete requires explicit confirmation")
+
+// ErrTooManyAddresses is returned when an address-set query exceeds
+// Config.MaxAddressSet.
+var ErrTooManyAddresses = errors.New("too many addresses")
+
 // Config holds database configuration.
 type Config struct {
 	// DSN is the PostgreSQL connection string.
@@ -53,8 +77,36 @@ type Config struct {
 	// ConnMaxLifetime is the maximum connection lifetime.
 	ConnMaxLifetime time.Duration
 
-	// LogLevel is the GORM log level.
+	// ConnMaxIdleTime is the maximum time a connection may sit idle before
+	// being closed. Keep it below the server's idle timeout (managed/serverless
+	// Postgres often kills idle connections, surfacing as "unexpected EOF").
+	ConnMaxIdleTime time.Duration
+
+	// LogLevel is the GORM log level while log.levels.store is unset;
+	// once set, the store level maps to GORM's (GormLogLevel).
 	LogLevel logger.LogLevel
+
+	// MaxAddressSet caps the addresses accepted by GetTransfersByAddresses
+	// (0 = unlimited).
+	MaxAddressSet int
+
+	// TimeZone is the session time zone set on every connection, so
+	// timestamp rendering and time bucketing in SQL don't depend on the
+	// server's default ("" = server default).
+	TimeZone string
+
+	// PrimaryKey is the primary key strategy of the event tables:
+	// PrimaryKeyID or PrimaryKeyNatural (see EnsurePrimaryKey).
+	PrimaryKey string
+
+	// AddressCase is the case addresses are stored in, which address
+	// filters match: AddressCaseLower or AddressCaseChecksum.
+	AddressCase string
+
+	// CacheSize is the number of events and of transfers kept in the
+	// in-memory LRU caches of GetEventByID and GetTransferByID
+	// (0 = no caching).
+	CacheSize int
 }
 
 // DefaultConfig returns default store configuration.
@@ -66,7 +118,34 @@ func DefaultConfig() Config {
 		MaxOpenConns:    25,
 		MaxIdleConns:    5,
 		ConnMaxLifetime: 5 * time.Minute,
+		ConnMaxIdleTime: 2 * time.Minute,
 		LogLevel:        logger.Warn,
+		MaxAddressSet:   1000,
+		TimeZone:        "UTC",
+		PrimaryKey:      PrimaryKeyID,
+		AddressCase:     AddressCaseLower,
+	}
+}
+
+// GormLogLevel maps the store subsystem's log level to GORM's: SQL
+// statements are logged at debug and below, errors and slow queries at
+// info and warn, errors only at error.
+//
+// Parameters:
+//   - level (zerolog.Level): store log level
+//
+// Returns:
+//   - logger.LogLevel: GORM log level
+func GormLogLevel(level zerolog.Level) logger.LogLevel {
+	switch {
+	case level <= zerolog.DebugLevel:
+		return logger.Info
+	case level <= zerolog.WarnLevel:
+		return logger.Warn
+	case level <= zerolog.PanicLevel:
+		return logger.Error
+	default:
+		return logger.Silent
 	}
 }
 
@@ -80,10 +159,16 @@ func DefaultConfig() Config {
 //   - error: nil on success, connection error on failure
 func New(cfg Config) (*Store, error) {
 	gormConfig := &gorm.Config{
-		Logger: logger.Default.LogMode(cfg.LogLevel),
+		Logger:  gormLogger{fallback: cfg.LogLevel},
+		NowFunc: func() time.Time { return time.Now().UTC() },
+	}
+
+	dsn, err := withSessionTimeZone(cfg.DSN, cfg.TimeZone)
+	if err != nil {
+		return nil, err
 	}
 
-	db, err := gorm.Open(postgres.Open(cfg.DSN), gormConfig)
+	db, err := gorm.Open(postgres.Open(dsn), gormConfig)
 	if err != nil {
 		return nil, fmt.Errorf("connecting to database: %w", err)
 	}
@@ -97,6 +182,7 @@ func New(cfg Config) (*Store, error) {
 	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
 	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
 	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 
 	// Verify connection
 	if err := sqlDB.Ping(); err != nil {
@@ -121,7 +207,48 @@ func New(cfg Config) (*Store, error) {
 		Int("maxIdleConns", cfg.MaxIdleConns).
 		Msg("connected to PostgreSQL")
 
-	return &Store{db: db, hasTimescaleDB: extExists}, nil
+	s := &Store{db: db, hasTimescaleDB: extExists, maxAddressSet: cfg.MaxAddressSet, primaryKey: cfg.PrimaryKey, addressCase: cfg.AddressCase}
+	if cfg.CacheSize > 0 {
+		s.eventCache = newRowCache("events", cfg.CacheSize, func(e *Event) uint64 { return e.BlockNumber })
+		s.transferCache = newRowCache("transfers", cfg.CacheSize, func(t *Transfer) uint64 { return t.BlockNumber })
+		registerCaches(s)
+	}
+	return s, nil
+}
+
+// withSessionTimeZone sets the timezone run-time parameter in a DSN,
+// overriding any set there. Both URL and keyword/value DSNs are supported.
+//
+// Parameters:
+//   - dsn (string): PostgreSQL connection string
+//   - tz (string): time zone name ("" = leave the DSN unchanged)
+//
+// Returns:
+//   - string: connection string with the time zone
+//   - error: nil on success, error if a URL DSN can't be parsed
+func withSessionTimeZone(dsn, tz string) (string, error) {
+	if tz == "" {
+		return dsn, nil
+	}
+
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			return "", fmt.Errorf("parsing database URL: %w", err)
+		}
+		q := u.Query()
+		for key := range q {
+			if strings.EqualFold(key, "timezone") {
+				q.Del(key)
+			}
+		}
+		q.Set("timezone", tz)
+		u.RawQuery = q.Encode()
+		return u.String(), nil
+	}
+
+	// Keyword/value DSN: the last occurrence of a keyword wins
+	return strings.TrimSpace(dsn + " timezone=" + tz), nil
 }
 
 // Close closes the database connection.
@@ -129,6 +256,9 @@ func New(cfg Config) (*Store, error) {
 // Returns:
 //   - error: nil on success, close error on failure
 func (s *Store) Close() error {
+	if s.eventCache != nil {
+		unregisterCaches(s)
+	}
 	sqlDB, err := s.db.DB()
 	if err != nil {
 		return fmt.Errorf("getting underlying DB: %w", err)
@@ -250,6 +380,34 @@ func (s *Store) GetMaxBlockNumber(ctx context.Context, tableName string) (uint64
 	return *maxBlock, nil
 }
 
+// GetMaxContractBlockNumber returns the highest block with a stored event
+// for a contract.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - contractName (string): contract name
+//
+// Returns:
+//   - uint64: maximum block number (0 if the contract has no events)
+//   - error: nil on success, query error on failure
+func (s *Store) GetMaxContractBlockNumber(ctx context.Context, contractName string) (uint64, error) {
+	var maxBlock *uint64
+
+	if err := s.db.WithContext(ctx).
+		Model(&Event{}).
+		Where("contract_name = ?", contractName).
+		Select("MAX(block_number)").
+		Scan(&maxBlock).Error; err != nil {
+		return 0, fmt.Errorf("getting max block for contract %s: %w", contractName, err)
+	}
+
+	if maxBlock == nil {
+		return 0, nil
+	}
+
+	return *maxBlock, nil
+}
+
 // UpdateStats updates database connection statistics.
 func (s *Store) UpdateStats() {
 	sqlDB, err := s.db.DB()
@@ -261,7 +419,10 @@ func (s *Store) UpdateStats() {
 	dbConnectionsOpen.Set(float64(stats.OpenConnections))
 }
 
-// Reset truncates all event tables, clearing indexed data.
+// Reset truncates all event tables, clearing indexed data: events,
+// transfers, and every table registered with RegisterBlockTable
+// (per-contract transfer tables, block coverage, reverted transactions,
+// rule tables). Registered tables that don't exist yet are skipped.
 // This is a destructive operation requiring explicit confirmation.
 //
 // Parameters:
@@ -270,10 +431,28 @@ func (s *Store) UpdateStats() {
 // Returns:
 //   - error: nil on success, truncate error on failure
 func (s *Store) Reset(ctx context.Context) error {
-	// Truncate transfers table
-	if err := s.db.WithContext(ctx).Exec("TRUNCATE TABLE transfers RESTART IDENTITY CASCADE").Error; err != nil {
-		return fmt.Errorf("truncating transfers: %w", err)
+	var tables []string
+	for _, table := range append([]string{"events", transfersTable}, BlockTables()...) {
+		if slices.Contains(tables, table) {
+			continue
+		}
+		var exists bool
+		if err := s.db.WithContext(ctx).Raw("SELECT to_regclass(?) IS NOT NULL", fmt.Sprintf("%q", table)).Scan(&exists).Error; err != nil {
+			return fmt.Errorf("checking table %s: %w", table, err)
+		}
+		if exists {
+			tables = append(tables, table)
+		}
+	}
+	quoted := make([]string, len(tables))
+	for i, table := range tables {
+		quoted[i] = fmt.Sprintf("%q", table)
+	}
+	if err := s.db.WithContext(ctx).Exec("TRUNCATE TABLE " + strings.Join(quoted, ", ") + " RESTART IDENTITY CASCADE").Error; err != nil {
+		return fmt.Errorf("truncating %s: %w", strings.Join(tables, ", "), err)
 	}
+	// RESTART IDENTITY reuses IDs
+	PurgeCachedBlocks(0, math.MaxUint64)
 
 	// Truncate sync_statuses if it exists
 	if err := s.db.WithContext(ctx).Exec("TRUNCATE TABLE sync_statuses RESTART IDENTITY CASCADE").Error; err != nil {
@@ -281,7 +460,439 @@ func (s *Store) Reset(ctx context.Context) error {
 		log.Warn().Err(err).Msg("failed to truncate sync_statuses (may not exist)")
 	}
 
-	log.Info().Msg("database reset complete")
+	log.Info().Strs("tables", tables).Msg("database reset complete")
+	return nil
+}
+
+// DeleteEvents removes one event type of a contract from the events table,
+// optionally limited to an inclusive block range. Typed tables written by
+// handlers are not touched.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - contract (string): contract name
+//   - eventName (string): stored event name
+//   - fromBlock (*uint64): first block (inclusive), nil for no lower bound
+//   - toBlock (*uint64): last block (inclusive), nil for no upper bound
+//   - allowUnbounded (bool): permit deleting across all blocks when both bounds are nil
+//
+// Returns:
+//   - int64: number of rows deleted
+//   - error: nil on success, ErrUnboundedDelete without bounds or confirmation, delete error on failure
+func (s *Store) DeleteEvents(ctx context.Context, contract, eventName string, fromBlock, toBlock *uint64, allowUnbounded bool) (int64, error) {
+	if contract == "" || eventName == "" {
+		return 0, fmt.Errorf("contract and event name are required")
+	}
+	if fromBlock == nil && toBlock == nil && !allowUnbounded {
+		return 0, fmt.Errorf("deleting %s:%s: %w", contract, eventName, ErrUnboundedDelete)
+	}
+
+	query := s.db.WithContext(ctx).Where("contract_name = ? AND event_name = ?", contract, eventName)
+	if fromBlock != nil {
+		query = query.Where("block_number >= ?", *fromBlock)
+	}
+	if toBlock != nil {
+		query = query.Where("block_number <= ?", *toBlock)
+	}
+
+	result := query.Delete(&Event{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("deleting %s:%s events: %w", contract, eventName, result.Error)
+	}
+
+	from, to := uint64(0), uint64(math.MaxUint64)
+	if fromBlock != nil {
+		from = *fromBlock
+	}
+	if toBlock != nil {
+		to = *toBlock
+	}
+	PurgeCachedBlocks(from, to)
+
+	log.Info().
+		Str("contract", contract).
+		Str("event", eventName).
+		Int64("deleted", result.RowsAffected).
+		Msg("deleted events")
+
+	return result.RowsAffected, nil
+}
+
+// DeleteBlockRange removes indexed data for an inclusive block range from
+// the events and transfers tables in a single transaction. Used to clear a
+// range before re-indexing it.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - fromBlock (uint64): first block to delete (inclusive)
+//   - toBlock (uint64): last block to delete (inclusive)
+//
+// Returns:
+//   - int64: total number of rows deleted
+//   - error: nil on success, delete error on failure
+func (s *Store) DeleteBlockRange(ctx context.Context, fromBlock, toBlock uint64) (int64, error) {
+	var deleted int64
+
+	err := s.Transaction(ctx, func(tx *gorm.DB) error {
+		var err error
+		deleted, err = DeleteBlockRangeTx(tx, fromBlock, toBlock)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	PurgeCachedBlocks(fromBlock, toBlock)
+
+	return deleted, nil
+}
+
+// DeleteFromBlock removes a model's rows at or above a block, e.g. the
+// first reorged block, in a transaction. To purge several tables
+// atomically, call DeleteFromBlockTx for each inside one Transaction.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - model (interface{}): model whose table to delete from (e.g. &Event{})
+//   - fromBlock (uint64): first block to delete (inclusive)
+//
+// Returns:
+//   - int64: number of rows deleted
+//   - error: nil on success, delete error on failure
+func (s *Store) DeleteFromBlock(ctx context.Context, model interface{}, fromBlock uint64) (int64, error) {
+	var deleted int64
+
+	err := s.Transaction(ctx, func(tx *gorm.DB) error {
+		var err error
+		deleted, err = DeleteFromBlockTx(tx, model, fromBlock)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	PurgeCachedBlocks(fromBlock, math.MaxUint64)
+
+	return deleted, nil
+}
+
+// DeleteFromBlockTx removes a model's rows at or above a block using an
+// existing transaction. Callers purge the row caches with PurgeCachedBlocks
+// once the transaction has committed.
+//
+// Parameters:
+//   - tx (*gorm.DB): database transaction
+//   - model (interface{}): model whose table to delete from (e.g. &Event{})
+//   - fromBlock (uint64): first block to delete (inclusive)
+//
+// Returns:
+//   - int64: number of rows deleted
+//   - error: nil on success, delete error on failure
+func DeleteFromBlockTx(tx *gorm.DB, model interface{}, fromBlock uint64) (int64, error) {
+	result := tx.Where("block_number >= ?", fromBlock).Delete(model)
+	if result.Error != nil {
+		return 0, fmt.Errorf("deleting from block %d: %w", fromBlock, result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// DeleteBlockRangeTx removes indexed data for an inclusive block range using
+// an existing transaction, so the delete can be combined with re-inserting
+// the range atomically. Callers purge the row caches with PurgeCachedBlocks
+// once the transaction has committed.
+//
+// Parameters:
+//   - tx (*gorm.DB): database transaction
+//   - fromBlock (uint64): first block to delete (inclusive)
+//   - toBlock (uint64): last block to delete (inclusive)
+//
+// Returns:
+//   - int64: total number of rows deleted
+//   - error: nil on success, delete error on failure
+func DeleteBlockRangeTx(tx *gorm.DB, fromBlock, toBlock uint64) (int64, error) {
+	var deleted int64
+	for _, model := range []interface{}{&Event{}, &Transfer{}} {
+		result := tx.Where("block_number BETWEEN ? AND ?", fromBlock, toBlock).Delete(model)
+		if result.Error != nil {
+			return 0, fmt.Errorf("deleting blocks %d-%d: %w", fromBlock, toBlock, result.Error)
+		}
+		deleted += result.RowsAffected
+	}
+
+	for _, table := range BlockTables() {
+		result := tx.Exec(fmt.Sprintf(`DELETE FROM %q WHERE block_number BETWEEN ? AND ?`, table), fromBlock, toBlock)
+		if result.Error != nil {
+			return 0, fmt.Errorf("deleting blocks %d-%d from %s: %w", fromBlock, toBlock, table, result.Error)
+		}
+		deleted += result.RowsAffected
+	}
+	return deleted, nil
+}
+
+// blockTables lists extra tables with a block_number column that block
+// range deletes (reorg rollback, reindex) must also clear.
+var (
+	blockTablesMu sync.RWMutex
+	blockTables   []string
+)
+
+// RegisterBlockTable adds a table with a block_number column to those
+// cleared by DeleteBlockRange, so its rows roll back with reorgs.
+//
+// Parameters:
+//   - table (string): table name
+func RegisterBlockTable(table string) {
+	blockTablesMu.Lock()
+	defer blockTablesMu.Unlock()
+
+	if !slices.Contains(blockTables, table) {
+		blockTables = append(blockTables, table)
+	}
+}
+
+// UnregisterBlockTable removes a table added by RegisterBlockTable, so
+// block range deletes stop touching it.
+//
+// Parameters:
+//   - table (string): table name
+func UnregisterBlockTable(table string) {
+	blockTablesMu.Lock()
+	defer blockTablesMu.Unlock()
+
+	blockTables = slices.DeleteFunc(blockTables, func(t string) bool { return t == table })
+}
+
+// BlockTables returns the tables registered with RegisterBlockTable.
+//
+// Returns:
+//   - []string: registered table names, in registration order
+func BlockTables() []string {
+	blockTablesMu.RLock()
+	defer blockTablesMu.RUnlock()
+
+	return slices.Clone(blockTables)
+}
+
+// UpsertSyncStatusTx writes a sync cursor using an existing transaction, so
+// it commits atomically with the batch it describes.
+//
+// Parameters:
+//   - tx (*gorm.DB): database transaction
+//   - status (SyncStatus): cursor to write (keyed by Contract)
+//
+// Returns:
+//   - error: nil on success, write error on failure
+func UpsertSyncStatusTx(tx *gorm.DB, status SyncStatus) error {
+	status.UpdatedAt = time.Now()
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "contract"}},
+		DoUpdates: clause.AssignmentColumns([]string{"last_block", "last_block_hash", "updated_at"}),
+	}).Create(&status).Error; err != nil {
+		return fmt.Errorf("upserting sync status %s: %w", status.Contract, err)
+	}
+	return nil
+}
+
+// UpsertSyncStatus writes a sync cursor, keyed by Contract, outside a
+// batch transaction (see UpsertSyncStatusTx).
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - status (SyncStatus): cursor to write, with its block hash
+//
+// Returns:
+//   - error: nil on success, write error on failure
+func (s *Store) UpsertSyncStatus(ctx context.Context, status SyncStatus) error {
+	return UpsertSyncStatusTx(s.db.WithContext(ctx), status)
+}
+
+// GetSyncStatus returns a sync cursor.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - contract (string): cursor key (SyncStatusAll for the engine-wide cursor)
+//
+// Returns:
+//   - *SyncStatus: the cursor or nil if not written yet
+//   - error: nil on success, query error on failure
+func (s *Store) GetSyncStatus(ctx context.Context, contract string) (*SyncStatus, error) {
+	var status SyncStatus
+	if err := s.db.WithContext(ctx).Where("contract = ?", contract).First(&status).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("getting sync status %s: %w", contract, err)
+	}
+	return &status, nil
+}
+
+// UpdateSyncStatusCAS advances a sync cursor only if it is still at
+// expectedLast, so two writers can't clobber each other's progress. A
+// missing cursor counts as being at block 0.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - contract (string): cursor key (SyncStatusAll for the engine-wide cursor)
+//   - expectedLast (uint64): last block the caller read from the cursor
+//   - newLast (uint64): new last block
+//   - newHash (string): hash of newLast
+//
+// Returns:
+//   - bool: true if updated, false if another writer moved the cursor
+//   - error: nil on success, write error on failure
+func (s *Store) UpdateSyncStatusCAS(ctx context.Context, contract string, expectedLast, newLast uint64, newHash string) (bool, error) {
+	now := time.Now()
+
+	if expectedLast == 0 {
+		result := s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&SyncStatus{
+			Contract:      contract,
+			LastBlock:     newLast,
+			LastBlockHash: newHash,
+			UpdatedAt:     now,
+		})
+		if result.Error != nil {
+			return false, fmt.Errorf("creating sync status %s: %w", contract, result.Error)
+		}
+		if result.RowsAffected == 1 {
+			return true, nil
+		}
+	}
+
+	result := s.db.WithContext(ctx).Model(&SyncStatus{}).
+		Where("contract = ? AND last_block = ?", contract, expectedLast).
+		Updates(map[string]any{"last_block": newLast, "last_block_hash": newHash, "updated_at": now})
+	if result.Error != nil {
+		return false, fmt.Errorf("updating sync status %s: %w", contract, result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ListBlockHashes returns the block hashes recorded on stored events in an
+// inclusive block range. Blocks without events, or whose events predate
+// block hash recording, are absent.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - fromBlock (uint64): first block (inclusive)
+//   - toBlock (uint64): last block (inclusive)
+//
+// Returns:
+//   - map[uint64]string: block hash by block number
+//   - error: nil on success, query error on failure
+func (s *Store) ListBlockHashes(ctx context.Context, fromBlock, toBlock uint64) (map[uint64]string, error) {
+	var rows []struct {
+		BlockNumber uint64
+		BlockHash   string
+	}
+	if err := s.db.WithContext(ctx).Model(&Event{}).
+		Distinct("block_number", "block_hash").
+		Where("block_number BETWEEN ? AND ? AND block_hash <> ''", fromBlock, toBlock).
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("listing block hashes %d-%d: %w", fromBlock, toBlock, err)
+	}
+
+	hashes := make(map[uint64]string, len(rows))
+	for _, row := range rows {
+		hashes[row.BlockNumber] = row.BlockHash
+	}
+	return hashes, nil
+}
+
+// ListRawEventsTx returns events in an inclusive block range that have a
+// stored raw log, ordered by block and log index.
+//
+// Parameters:
+//   - tx (*gorm.DB): database transaction
+//   - fromBlock (uint64): first block (inclusive)
+//   - toBlock (uint64): last block (inclusive)
+//
+// Returns:
+//   - []Event: events with RawTopics set
+//   - error: nil on success, query error on failure
+func ListRawEventsTx(tx *gorm.DB, fromBlock, toBlock uint64) ([]Event, error) {
+	var events []Event
+	if err := tx.
+		Where("block_number BETWEEN ? AND ? AND raw_topics IS NOT NULL", fromBlock, toBlock).
+		Order("block_number ASC, log_index ASC").
+		Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("listing raw events %d-%d: %w", fromBlock, toBlock, err)
+	}
+	return events, nil
+}
+
+// ListSuspectTimestampBlocksTx returns blocks in an inclusive range with
+// events or transfers (in any transfer table) whose timestamp is within a
+// second of their insertion time, which suggests it was defaulted to
+// time.Now() rather than set to the block time.
+//
+// Parameters:
+//   - tx (*gorm.DB): database transaction
+//   - fromBlock (uint64): first block (inclusive)
+//   - toBlock (uint64): last block (inclusive)
+//
+// Returns:
+//   - []uint64: distinct block numbers, ascending
+//   - error: nil on success, query error on failure
+func ListSuspectTimestampBlocksTx(tx *gorm.DB, fromBlock, toBlock uint64) ([]uint64, error) {
+	var blocks []uint64
+	if err := tx.Raw(fmt.Sprintf(`
+		SELECT DISTINCT block_number FROM (
+			SELECT block_number FROM events
+			WHERE block_number BETWEEN @from AND @to AND ABS(EXTRACT(EPOCH FROM created_at - timestamp)) < 1
+			UNION ALL%s
+		) u
+		ORDER BY block_number
+	`, unionTransferTables(`
+			SELECT block_number FROM %[1]q
+			WHERE block_number BETWEEN @from AND @to AND ABS(EXTRACT(EPOCH FROM created_at - timestamp)) < 1`)), map[string]any{"from": fromBlock, "to": toBlock}).Scan(&blocks).Error; err != nil {
+		return nil, fmt.Errorf("listing suspect timestamps %d-%d: %w", fromBlock, toBlock, err)
+	}
+	return blocks, nil
+}
+
+// SetBlockTimestampTx sets the timestamp of all indexed rows in a block,
+// in the events and every transfer table, to blockTime, leaving rows that
+// already match untouched. Callers purge the
+// row caches with PurgeCachedBlocks once the transaction has committed.
+//
+// Parameters:
+//   - tx (*gorm.DB): database transaction
+//   - block (uint64): block number
+//   - blockTime (time.Time): the block's header timestamp
+//
+// Returns:
+//   - int64: total number of rows corrected
+//   - error: nil on success, update error on failure
+func SetBlockTimestampTx(tx *gorm.DB, block uint64, blockTime time.Time) (int64, error) {
+	var updated int64
+	for _, table := range append([]string{"events"}, transferTableNames()...) {
+		result := tx.Table(table).
+			Where("block_number = ? AND timestamp <> ?", block, blockTime).
+			Update("timestamp", blockTime)
+		if result.Error != nil {
+			return 0, fmt.Errorf("setting block %d timestamp in %s: %w", block, table, result.Error)
+		}
+		updated += result.RowsAffected
+	}
+	return updated, nil
+}
+
+// UpdateEventDataTx replaces the Data column of a stored event. Callers
+// purge the row caches with PurgeCachedBlocks once the transaction has
+// committed.
+//
+// Parameters:
+//   - tx (*gorm.DB): database transaction
+//   - event (*Event): event to update (ID, TxHash, LogIndex and Timestamp
+//     identify the row, so either primary key strategy's index applies)
+//   - data ([]byte): new JSON data
+//
+// Returns:
+//   - error: nil on success, update error on failure
+func UpdateEventDataTx(tx *gorm.DB, event *Event, data []byte) error {
+	if err := tx.Model(&Event{}).
+		Where("id = ? AND tx_hash = ? AND log_index = ? AND timestamp = ?", event.ID, event.TxHash, event.LogIndex, event.Timestamp).
+		Update("data", datatypes.JSON(data)).Error; err != nil {
+		return fmt.Errorf("updating event %d data: %w", event.ID, err)
+	}
 	return nil
 }
 
@@ -303,15 +914,23 @@ func (s *Store) GetTransferCount(ctx context.Context) (int64, error) {
 
 // TransferQuery holds query parameters for transfers.
 type TransferQuery struct {
-	FromBlock *uint64
-	ToBlock   *uint64
-	FromTime  *time.Time
-	ToTime    *time.Time
-	OrderBy   string // "block_number" or "timestamp"
-	OrderDir  string // "ASC" or "DESC"
-	Limit     int
-	AfterID   *uint64 // cursor-based pagination
-	BeforeID  *uint64
+	Contract       string  // contract whose transfer table to query ("" = transfers); see RegisterTransferTable
+	ContractAddr   *string // emitting contract, matched lowercased as stored by default
+	Address        *string // sender or recipient (from OR to), matched lowercased
+	From           *string // sender, matched lowercased
+	To             *string // recipient, matched lowercased
+	FromBlock      *uint64
+	ToBlock        *uint64
+	FromTime       *time.Time
+	ToTime         *time.Time
+	OrderBy        string // "block_number" or "timestamp"
+	OrderDir       string // "ASC" or "DESC"
+	Limit          int
+	AfterID        *uint64 // cursor-based pagination (id primary keys only)
+	BeforeID       *uint64
+	AfterKey       *EventKey // cursor-based pagination by natural key
+	BeforeKey      *EventKey
+	FormatDecimals bool // populate Transfer.ValueDecimal from the contract's decimals
 }
 
 // QueryTransfers queries transfers with filtering, ordering, and pagination.
@@ -327,21 +946,71 @@ type TransferQuery struct {
 func (s *Store) QueryTransfers(ctx context.Context, q TransferQuery) ([]Transfer, int64, error) {
 	start := time.Now()
 
-	// Build base query with filters
-	query := s.db.WithContext(ctx).Model(&Transfer{})
+	transfers, totalCount, err := s.queryTransfers(s.transfersQuery(ctx, q), q)
+	if err != nil {
+		return nil, 0, err
+	}
 
-	if q.FromBlock != nil {
-		query = query.Where("block_number >= ?", *q.FromBlock)
+	dbQueryDuration.WithLabelValues("query_transfers").Observe(time.Since(start).Seconds())
+	return transfers, totalCount, nil
+}
+
+// GetTransfersByAddresses queries transfers sent or received by any of
+// addrs, with the same filtering, ordering, and pagination as QueryTransfers.
+// Addresses are matched lowercased, as stored by default.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - addrs ([]string): addresses to match against from and to
+//   - q (TransferQuery): query parameters
+//
+// Returns:
+//   - []Transfer: matching transfers
+//   - int64: total count matching filters (before pagination)
+//   - error: nil on success, ErrTooManyAddresses over the cap, query error on failure
+func (s *Store) GetTransfersByAddresses(ctx context.Context, addrs []string, q TransferQuery) ([]Transfer, int64, error) {
+	if s.maxAddressSet > 0 && len(addrs) > s.maxAddressSet {
+		return nil, 0, fmt.Errorf("%w: %d exceeds limit of %d", ErrTooManyAddresses, len(addrs), s.maxAddressSet)
 	}
-	if q.ToBlock != nil {
-		query = query.Where("block_number <= ?", *q.ToBlock)
+	if len(addrs) == 0 {
+		return []Transfer{}, 0, nil
 	}
-	if q.FromTime != nil {
-		query = query.Where("timestamp >= ?", *q.FromTime)
+
+	start := time.Now()
+
+	formatted := make([]string, len(addrs))
+	for i, addr := range addrs {
+		formatted[i] = s.formatAddress(addr)
 	}
-	if q.ToTime != nil {
-		query = query.Where("timestamp <= ?", *q.ToTime)
+
+	base := s.transfersQuery(ctx, q).
+		Where(`("from" IN ? OR "to" IN ?)`, formatted, formatted)
+
+	transfers, totalCount, err := s.queryTransfers(base, q)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	dbQueryDuration.WithLabelValues("transfers_by_addresses").Observe(time.Since(start).Seconds())
+	return transfers, totalCount, nil
+}
+
+// queryTransfers applies TransferQuery filters, ordering, and pagination to
+// a transfers query.
+//
+// Parameters:
+//   - query (*gorm.DB): base query on the transfers model
+//   - q (TransferQuery): query parameters
+//
+// Returns:
+//   - []Transfer: matching transfers
+//   - int64: total count matching filters (before pagination)
+//   - error: nil on success, query error on failure
+func (s *Store) queryTransfers(query *gorm.DB, q TransferQuery) ([]Transfer, int64, error) {
+	if err := s.checkIDCursors(q.AfterID, q.BeforeID); err != nil {
+		return nil, 0, err
 	}
+	query = s.filterTransfers(query, q)
 
 	// Get total count
 	var totalCount int64
@@ -356,17 +1025,10 @@ func (s *Store) QueryTransfers(ctx context.Context, q TransferQuery) ([]Transfer
 	if q.BeforeID != nil {
 		query = query.Where("id < ?", *q.BeforeID)
 	}
+	query = keyPage(query, TransferTable(q.Contract), q.AfterKey, q.BeforeKey)
 
 	// Apply ordering
-	orderBy := "block_number"
-	if q.OrderBy == "timestamp" {
-		orderBy = "timestamp"
-	}
-	orderDir := "ASC"
-	if q.OrderDir == "DESC" {
-		orderDir = "DESC"
-	}
-	query = query.Order(fmt.Sprintf("%s %s, id %s", orderBy, orderDir, orderDir))
+	query = query.Order(queryOrder(q.OrderBy, q.OrderDir, s.tiebreak()))
 
 	// Apply limit
 	if q.Limit > 0 {
@@ -378,11 +1040,97 @@ func (s *Store) QueryTransfers(ctx context.Context, q TransferQuery) ([]Transfer
 	if err := query.Find(&transfers).Error; err != nil {
 		return nil, 0, fmt.Errorf("querying transfers: %w", err)
 	}
+	if q.FormatDecimals {
+		formatTransferDecimals(transfers)
+	}
 
-	dbQueryDuration.WithLabelValues("query_transfers").Observe(time.Since(start).Seconds())
 	return transfers, totalCount, nil
 }
 
+// filterTransfers applies TransferQuery address and range filters to a
+// transfers query. Addresses are matched in the stored case.
+func (s *Store) filterTransfers(query *gorm.DB, q TransferQuery) *gorm.DB {
+	if q.ContractAddr != nil {
+		query = query.Where("contract_addr = ?", s.formatAddress(*q.ContractAddr))
+	}
+	if q.Address != nil {
+		// Parenthesized so cursor and range conditions apply to both sides
+		addr := s.formatAddress(*q.Address)
+		query = query.Where(`("from" = ? OR "to" = ?)`, addr, addr)
+	}
+	if q.From != nil {
+		query = query.Where(`"from" = ?`, s.formatAddress(*q.From))
+	}
+	if q.To != nil {
+		query = query.Where(`"to" = ?`, s.formatAddress(*q.To))
+	}
+	if q.FromBlock != nil {
+		query = query.Where("block_number >= ?", *q.FromBlock)
+	}
+	if q.ToBlock != nil {
+		query = query.Where("block_number <= ?", *q.ToBlock)
+	}
+	if q.FromTime != nil {
+		query = query.Where("timestamp >= ?", *q.FromTime)
+	}
+	if q.ToTime != nil {
+		query = query.Where("timestamp <= ?", *q.ToTime)
+	}
+	return query
+}
+
+// filterEvents applies EventQuery name and range filters to an events query.
+func filterEvents(query *gorm.DB, q EventQuery) *gorm.DB {
+	if q.ContractName != nil {
+		query = query.Where("contract_name = ?", *q.ContractName)
+	}
+	if q.EventName != nil {
+		query = query.Where("event_name = ?", *q.EventName)
+	}
+	if len(q.ContractNames) > 0 {
+		query = query.Where("contract_name IN ?", q.ContractNames)
+	}
+	if len(q.EventNames) > 0 {
+		query = query.Where("event_name IN ?", q.EventNames)
+	}
+	if q.FromBlock != nil {
+		query = query.Where("block_number >= ?", *q.FromBlock)
+	}
+	if q.ToBlock != nil {
+		query = query.Where("block_number <= ?", *q.ToBlock)
+	}
+	if q.FromTime != nil {
+		query = query.Where("timestamp >= ?", *q.FromTime)
+	}
+	if q.ToTime != nil {
+		query = query.Where("timestamp <= ?", *q.ToTime)
+	}
+
+	// Sorted so the statement is stable across calls
+	for _, key := range slices.Sorted(maps.Keys(q.DataFilters)) {
+		query = query.Where("data ->> ? = ?", key, q.DataFilters[key])
+	}
+	return query
+}
+
+// queryOrder returns the ORDER BY clause for a query's OrderBy/OrderDir,
+// with a tiebreaker column: id, or block_number then log_index with
+// natural keys (timestamps aren't unique to a block).
+func queryOrder(orderBy, orderDir, tiebreak string) string {
+	column := "block_number"
+	if orderBy == "timestamp" {
+		column = "timestamp"
+	}
+	dir := "ASC"
+	if orderDir == "DESC" {
+		dir = "DESC"
+	}
+	if tiebreak == "log_index" && column != "block_number" {
+		return fmt.Sprintf("%s %s, block_number %s, log_index %s", column, dir, dir, dir)
+	}
+	return fmt.Sprintf("%s %s, %s %s", column, dir, tiebreak, dir)
+}
+
 // GetTransferByID retrieves a single transfer by ID.
 //
 // Parameters:
@@ -391,8 +1139,21 @@ func (s *Store) QueryTransfers(ctx context.Context, q TransferQuery) ([]Transfer
 //
 // Returns:
 //   - *Transfer: the transfer or nil if not found
-//   - error: nil on success, query error on failure
+//   - error: nil on success, ErrIDNotIndexed under natural keys (see
+//     GetTransferByKey), query error on failure
 func (s *Store) GetTransferByID(ctx context.Context, id uint64) (*Transfer, error) {
+	if s.NaturalKeys() {
+		return nil, fmt.Errorf("getting transfer %d: %w", id, ErrIDNotIndexed)
+	}
+
+	var generation uint64
+	if s.transferCache != nil {
+		var cached *Transfer
+		if cached, generation = s.transferCache.get(id); cached != nil {
+			return cached, nil
+		}
+	}
+
 	var transfer Transfer
 	if err := s.db.WithContext(ctx).First(&transfer, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -400,6 +1161,9 @@ func (s *Store) GetTransferByID(ctx context.Context, id uint64) (*Transfer, erro
 		}
 		return nil, fmt.Errorf("getting transfer %d: %w", id, err)
 	}
+	if s.transferCache != nil {
+		s.transferCache.add(id, transfer, generation)
+	}
 	return &transfer, nil
 }
 
@@ -422,17 +1186,23 @@ func (s *Store) GetTransfersByTxHash(ctx context.Context, txHash string) ([]Tran
 
 // EventQuery holds query parameters for generic events.
 type EventQuery struct {
-	ContractName *string
-	EventName    *string
-	FromBlock    *uint64
-	ToBlock      *uint64
-	FromTime     *time.Time
-	ToTime       *time.Time
-	OrderBy      string // "block_number" or "timestamp"
-	OrderDir     string // "ASC" or "DESC"
-	Limit        int
-	AfterID      *uint64 // cursor-based pagination
-	BeforeID     *uint64
+	ContractName  *string
+	EventName     *string
+	ContractNames []string // matches any (IN); combined with ContractName if both set
+	EventNames    []string // matches any (IN); combined with EventName if both set
+	FromBlock     *uint64
+	ToBlock       *uint64
+	FromTime      *time.Time
+	ToTime        *time.Time
+	OrderBy       string // "block_number" or "timestamp"
+	OrderDir      string // "ASC" or "DESC"
+	Limit         int
+	AfterID       *uint64 // cursor-based pagination (id primary keys only)
+	BeforeID      *uint64
+	AfterKey      *EventKey // cursor-based pagination by natural key
+	BeforeKey     *EventKey
+	DecodeData    bool              // populate Event.DataMap from the JSON data
+	DataFilters   map[string]string // data->>key = value, all must match; a missing key matches nothing
 }
 
 // QueryEvents queries generic events with filtering, ordering, and pagination.
@@ -448,28 +1218,22 @@ type EventQuery struct {
 func (s *Store) QueryEvents(ctx context.Context, q EventQuery) ([]Event, int64, error) {
 	start := time.Now()
 
-	// Build base query with filters
-	query := s.db.WithContext(ctx).Model(&Event{})
-
-	if q.ContractName != nil {
-		query = query.Where("contract_name = ?", *q.ContractName)
+	if err := validateNameFilter("contract names", q.ContractNames); err != nil {
+		return nil, 0, err
 	}
-	if q.EventName != nil {
-		query = query.Where("event_name = ?", *q.EventName)
+	if err := validateNameFilter("event names", q.EventNames); err != nil {
+		return nil, 0, err
 	}
-	if q.FromBlock != nil {
-		query = query.Where("block_number >= ?", *q.FromBlock)
+	if err := validateDataFilters(q.DataFilters); err != nil {
+		return nil, 0, err
 	}
-	if q.ToBlock != nil {
-		query = query.Where("block_number <= ?", *q.ToBlock)
-	}
-	if q.FromTime != nil {
-		query = query.Where("timestamp >= ?", *q.FromTime)
-	}
-	if q.ToTime != nil {
-		query = query.Where("timestamp <= ?", *q.ToTime)
+	if err := s.checkIDCursors(q.AfterID, q.BeforeID); err != nil {
+		return nil, 0, err
 	}
 
+	// Build base query with filters
+	query := filterEvents(s.db.WithContext(ctx).Model(&Event{}), q)
+
 	// Get total count
 	var totalCount int64
 	if err := query.Count(&totalCount).Error; err != nil {
@@ -483,17 +1247,10 @@ func (s *Store) QueryEvents(ctx context.Context, q EventQuery) ([]Event, int64,
 	if q.BeforeID != nil {
 		query = query.Where("id < ?", *q.BeforeID)
 	}
+	query = keyPage(query, "events", q.AfterKey, q.BeforeKey)
 
 	// Apply ordering
-	orderBy := "block_number"
-	if q.OrderBy == "timestamp" {
-		orderBy = "timestamp"
-	}
-	orderDir := "ASC"
-	if q.OrderDir == "DESC" {
-		orderDir = "DESC"
-	}
-	query = query.Order(fmt.Sprintf("%s %s, id %s", orderBy, orderDir, orderDir))
+	query = query.Order(queryOrder(q.OrderBy, q.OrderDir, s.tiebreak()))
 
 	// Apply limit
 	if q.Limit > 0 {
@@ -506,10 +1263,86 @@ func (s *Store) QueryEvents(ctx context.Context, q EventQuery) ([]Event, int64,
 		return nil, 0, fmt.Errorf("querying events: %w", err)
 	}
 
+	if q.DecodeData {
+		if err := decodeEventData(events); err != nil {
+			return nil, 0, err
+		}
+	}
+
 	dbQueryDuration.WithLabelValues("query_events").Observe(time.Since(start).Seconds())
 	return events, totalCount, nil
 }
 
+// maxNameFilterValues caps the number of values in an IN filter.
+const maxNameFilterValues = 100
+
+// maxDataFilters caps the number of JSON data field filters in a query.
+const maxDataFilters = 10
+
+// validateDataFilters checks JSON data field filters before they reach the
+// database. Keys and values are bound as parameters, never interpolated.
+//
+// Parameters:
+//   - filters (map[string]string): data field to value
+//
+// Returns:
+//   - error: nil if valid, validation error otherwise
+func validateDataFilters(filters map[string]string) error {
+	if len(filters) > maxDataFilters {
+		return fmt.Errorf("too many data filters: %d (max %d)", len(filters), maxDataFilters)
+	}
+	for key := range filters {
+		if key == "" || len(key) > 100 {
+			return fmt.Errorf("invalid data filter key %q: must be 1-100 characters", key)
+		}
+	}
+	return nil
+}
+
+// validateNameFilter checks a multi-value name filter against the column
+// limits so oversized or empty values are rejected before hitting the database.
+//
+// Parameters:
+//   - field (string): filter name for error messages
+//   - names ([]string): filter values
+//
+// Returns:
+//   - error: nil if valid, validation error otherwise
+func validateNameFilter(field string, names []string) error {
+	if len(names) > maxNameFilterValues {
+		return fmt.Errorf("too many %s: %d (max %d)", field, len(names), maxNameFilterValues)
+	}
+	for _, name := range names {
+		if name == "" || len(name) > 100 {
+			return fmt.Errorf("invalid %s value %q: must be 1-100 characters", field, name)
+		}
+	}
+	return nil
+}
+
+// decodeEventData populates DataMap on each event from its JSON data.
+// Numbers are decoded as json.Number, so uint256 values stored as JSON
+// numbers keep their precision above 2^53.
+//
+// Parameters:
+//   - events ([]Event): events to decode in place
+//
+// Returns:
+//   - error: nil on success, unmarshal error on failure
+func decodeEventData(events []Event) error {
+	for i := range events {
+		if len(events[i].Data) == 0 {
+			continue
+		}
+		dec := json.NewDecoder(bytes.NewReader(events[i].Data))
+		dec.UseNumber()
+		if err := dec.Decode(&events[i].DataMap); err != nil {
+			return fmt.Errorf("decoding data for event %d: %w", events[i].ID, err)
+		}
+	}
+	return nil
+}
+
 // GetEventByID retrieves a single generic event by ID.
 //
 // Parameters:
@@ -518,8 +1351,21 @@ func (s *Store) QueryEvents(ctx context.Context, q EventQuery) ([]Event, int64,
 //
 // Returns:
 //   - *Event: the event or nil if not found
-//   - error: nil on success, query error on failure
+//   - error: nil on success, ErrIDNotIndexed under natural keys (see
+//     GetEventByKey), query error on failure
 func (s *Store) GetEventByID(ctx context.Context, id uint64) (*Event, error) {
+	if s.NaturalKeys() {
+		return nil, fmt.Errorf("getting event %d: %w", id, ErrIDNotIndexed)
+	}
+
+	var generation uint64
+	if s.eventCache != nil {
+		var cached *Event
+		if cached, generation = s.eventCache.get(id); cached != nil {
+			return cached, nil
+		}
+	}
+
 	var event Event
 	if err := s.db.WithContext(ctx).First(&event, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -527,6 +1373,9 @@ func (s *Store) GetEventByID(ctx context.Context, id uint64) (*Event, error) {
 		}
 		return nil, fmt.Errorf("getting event %d: %w", id, err)
 	}
+	if s.eventCache != nil {
+		s.eventCache.add(id, event, generation)
+	}
 	return &event, nil
 }
 
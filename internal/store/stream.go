@@ -0,0 +1,185 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Unified event type tags.
+const (
+	UnifiedTypeEvent    = "event"
+	UnifiedTypeTransfer = "transfer"
+)
+
+// streamPageSize is the number of rows fetched per keyset page.
+const streamPageSize = 1000
+
+// UnifiedEvent normalizes rows from the transfers and events tables into a
+// single shape for ordered streaming.
+type UnifiedEvent struct {
+	// Type is the source table tag ("event" or "transfer").
+	Type string
+
+	// ID is the row ID within its source table.
+	ID uint64
+
+	BlockNumber uint64
+	TxHash      string
+	TxIndex     uint
+	LogIndex    uint
+	Timestamp   time.Time
+
+	// ContractName is set for generic events; empty for transfers.
+	ContractName string
+
+	// ContractAddr is the emitting contract (empty on transfers stored
+	// before it was recorded).
+	ContractAddr string
+
+	// EventName is the event name ("Transfer" for typed transfers).
+	EventName string
+
+	// Data holds the event payload as JSON.
+	Data datatypes.JSON
+}
+
+// streamTransfersSQL is the stream branch of one transfer table.
+const streamTransfersSQL = `
+	SELECT 'transfer' AS type, id, block_number, tx_hash, tx_index, log_index, timestamp,
+		'' AS contract_name, COALESCE(contract_addr, '') AS contract_addr, 'Transfer' AS event_name,
+		jsonb_build_object('from', "from", 'to', "to", 'value', value::text) AS data
+	FROM %[1]q
+	WHERE block_number BETWEEN @from AND @to`
+
+// streamUnionSQL merges the transfer tables (see streamTransfersSQL) and
+// the events table into one canonical ordering. The type tag and row ID
+// break ties when the same log is stored in both the generic and typed
+// tables.
+const streamUnionSQL = `
+SELECT * FROM (%s
+	UNION ALL
+	SELECT 'event' AS type, id, block_number, tx_hash, tx_index, log_index, timestamp,
+		contract_name, contract_addr, event_name, data
+	FROM events
+	WHERE block_number BETWEEN @from AND @to
+) u
+WHERE (block_number, tx_index, log_index, type, id) > (@cb, @ct, @cl, @cty, @cid)
+ORDER BY block_number, tx_index, log_index, type, id
+LIMIT @limit`
+
+// StreamAllEvents streams events and transfers in an inclusive block range in
+// canonical (block_number, tx_index, log_index) order, calling fn for each.
+// Transfers come from the shared table and every contract table.
+// Rows are fetched with keyset pagination so memory use stays bounded
+// regardless of range size. Returning an error from fn stops the stream.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - fromBlock (uint64): first block (inclusive)
+//   - toBlock (uint64): last block (inclusive)
+//   - fn (func(UnifiedEvent) error): callback invoked per event
+//
+// Returns:
+//   - error: nil on success, query or callback error on failure
+func (s *Store) StreamAllEvents(ctx context.Context, fromBlock, toBlock uint64, fn func(UnifiedEvent) error) error {
+	// Cursor starts just before the first possible row; "" sorts before both type tags.
+	return s.streamAfter(ctx, fromBlock, toBlock, UnifiedEvent{BlockNumber: fromBlock}, fn)
+}
+
+// streamAfter streams the rows of an inclusive block range that sort after
+// cursor, in canonical order.
+func (s *Store) streamAfter(ctx context.Context, fromBlock, toBlock uint64, cursor UnifiedEvent, fn func(UnifiedEvent) error) error {
+	start := time.Now()
+	defer func() {
+		dbQueryDuration.WithLabelValues("stream_all_events").Observe(time.Since(start).Seconds())
+	}()
+
+	query := fmt.Sprintf(streamUnionSQL, unionTransferTables(streamTransfersSQL))
+	for {
+		args := map[string]interface{}{
+			"from":  fromBlock,
+			"to":    toBlock,
+			"cb":    cursor.BlockNumber,
+			"ct":    cursor.TxIndex,
+			"cl":    cursor.LogIndex,
+			"cty":   cursor.Type,
+			"cid":   cursor.ID,
+			"limit": streamPageSize,
+		}
+
+		var page []UnifiedEvent
+		if err := s.db.WithContext(ctx).Raw(query, args).Scan(&page).Error; err != nil {
+			return fmt.Errorf("streaming events %d-%d: %w", fromBlock, toBlock, err)
+		}
+
+		for _, ev := range page {
+			if err := fn(ev); err != nil {
+				return err
+			}
+		}
+
+		if len(page) < streamPageSize {
+			return nil
+		}
+
+		cursor = page[len(page)-1]
+	}
+}
+
+// maxRecentActivity caps the rows RecentActivity returns.
+const maxRecentActivity = 1000
+
+// recentTransfersSQL is the recent activity branch of one transfer table.
+const recentTransfersSQL = `
+	(SELECT 'transfer' AS type, id, block_number, tx_hash, tx_index, log_index, timestamp,
+		'' AS contract_name, COALESCE(contract_addr, '') AS contract_addr, 'Transfer' AS event_name,
+		jsonb_build_object('from', "from", 'to', "to", 'value', value::text) AS data
+	FROM %[1]q
+	ORDER BY block_number DESC, log_index DESC
+	LIMIT @limit)`
+
+// recentActivitySQL takes the newest rows of each table via its
+// block_number index, then merges them; each branch is limited so no
+// table is scanned past what the final LIMIT can use. The transfer tables
+// come from recentTransfersSQL.
+const recentActivitySQL = `
+SELECT * FROM (%s
+	UNION ALL
+	(SELECT 'event' AS type, id, block_number, tx_hash, tx_index, log_index, timestamp,
+		contract_name, contract_addr, event_name, data
+	FROM events
+	ORDER BY block_number DESC, log_index DESC
+	LIMIT @limit)
+) u
+ORDER BY block_number DESC, log_index DESC, type, id DESC
+LIMIT @limit`
+
+// RecentActivity returns the most recent events and transfers across all
+// contracts, newest first by (block_number, log_index).
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - limit (int): number of rows, capped at 1000
+//
+// Returns:
+//   - []UnifiedEvent: the newest rows
+//   - error: nil on success, error for a non-positive limit or query failure
+func (s *Store) RecentActivity(ctx context.Context, limit int) ([]UnifiedEvent, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("recent activity: limit must be positive, got %d", limit)
+	}
+	limit = min(limit, maxRecentActivity)
+
+	start := time.Now()
+
+	var rows []UnifiedEvent
+	if err := s.db.WithContext(ctx).Raw(fmt.Sprintf(recentActivitySQL, unionTransferTables(recentTransfersSQL)), map[string]interface{}{"limit": limit}).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("querying recent activity: %w", err)
+	}
+
+	dbQueryDuration.WithLabelValues("recent_activity").Observe(time.Since(start).Seconds())
+	return rows, nil
+}
@@ -3,11 +3,14 @@ package store
 
 import (
 	"context"
+	"errors"
 	"fmt"
-
-	"github.com/rs/zerolog/log"
 )
 
+// ErrTimescaleDBUnavailable is returned by SetupTimescaleDB when the
+// database has no timescaledb extension; tables stay regular tables.
+var ErrTimescaleDBUnavailable = errors.New("TimescaleDB extension not installed")
+
 // TimescaleConfig holds TimescaleDB optimization settings.
 type TimescaleConfig struct {
 	// ChunkInterval is the time interval for hypertable chunks (e.g., "1 day").
@@ -35,6 +38,10 @@ func DefaultTimescaleConfig() TimescaleConfig {
 }
 
 // SetupTimescaleDB configures a table as an optimized TimescaleDB hypertable.
+// Call it after Migrate. It is idempotent: an existing hypertable only gets
+// its chunk interval (for new chunks) and policies updated, and compression
+// settings are left alone once enabled, as TimescaleDB refuses to change
+// them while compressed chunks exist.
 //
 // Parameters:
 //   - ctx (context.Context): request context
@@ -43,24 +50,35 @@ func DefaultTimescaleConfig() TimescaleConfig {
 //   - cfg (TimescaleConfig): optimization settings
 //
 // Returns:
-//   - error: nil on success, setup error on failure
+//   - error: nil on success, ErrTimescaleDBUnavailable without the extension, setup error on failure
 func (s *Store) SetupTimescaleDB(ctx context.Context, tableName, timeColumn string, cfg TimescaleConfig) error {
 	if !s.hasTimescaleDB {
-		log.Debug().
-			Str("table", tableName).
-			Msg("skipping TimescaleDB setup (extension not available)")
-		return nil
+		return fmt.Errorf("setting up %s: %w", tableName, ErrTimescaleDBUnavailable)
 	}
 
-	// 1. Create hypertable
-	if err := s.CreateHypertable(tableName, timeColumn, cfg.ChunkInterval); err != nil {
-		return fmt.Errorf("creating hypertable: %w", err)
+	exists, compressed, err := s.hypertableState(ctx, tableName)
+	if err != nil {
+		return err
+	}
+
+	// 1. Create hypertable, or update the interval of future chunks
+	if !exists {
+		if err := s.CreateHypertable(tableName, timeColumn, cfg.ChunkInterval); err != nil {
+			return fmt.Errorf("creating hypertable: %w", err)
+		}
+	} else if cfg.ChunkInterval != "" {
+		sql := fmt.Sprintf("SELECT set_chunk_time_interval('%s', INTERVAL '%s')", tableName, cfg.ChunkInterval)
+		if err := s.db.WithContext(ctx).Exec(sql).Error; err != nil {
+			return fmt.Errorf("setting chunk interval on %s: %w", tableName, err)
+		}
 	}
 
 	// 2. Enable compression if configured
 	if cfg.CompressAfter != "" {
-		if err := s.EnableCompression(ctx, tableName, timeColumn); err != nil {
-			return fmt.Errorf("enabling compression: %w", err)
+		if !compressed {
+			if err := s.EnableCompression(ctx, tableName, timeColumn); err != nil {
+				return fmt.Errorf("enabling compression: %w", err)
+			}
 		}
 
 		if err := s.AddCompressionPolicy(ctx, tableName, cfg.CompressAfter); err != nil {
@@ -85,6 +103,31 @@ func (s *Store) SetupTimescaleDB(ctx context.Context, tableName, timeColumn stri
 	return nil
 }
 
+// hypertableState reports whether a table is already a hypertable and
+// whether compression is enabled on it.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - tableName (string): table name
+//
+// Returns:
+//   - bool: true if the table is a hypertable
+//   - bool: true if compression is enabled
+//   - error: nil on success, query error on failure
+func (s *Store) hypertableState(ctx context.Context, tableName string) (bool, bool, error) {
+	var row struct {
+		CompressionEnabled bool
+	}
+	result := s.db.WithContext(ctx).Raw(
+		"SELECT compression_enabled FROM timescaledb_information.hypertables WHERE hypertable_name = ?",
+		tableName,
+	).Scan(&row)
+	if result.Error != nil {
+		return false, false, fmt.Errorf("checking hypertable %s: %w", tableName, result.Error)
+	}
+	return result.RowsAffected > 0, row.CompressionEnabled, nil
+}
+
 // EnableCompression enables compression on a hypertable.
 //
 // Parameters:
@@ -0,0 +1,110 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// transfersTable is the shared transfers table.
+const transfersTable = "transfers"
+
+// transferTables routes contracts' transfers to their own tables.
+var (
+	transferTablesMu sync.RWMutex
+	transferTables   = make(map[string]string) // contract name -> table
+)
+
+// RegisterTransferTable routes a contract's transfers to table instead of
+// the shared transfers table. The table must exist (MigrateTransferTable).
+//
+// Parameters:
+//   - contract (string): contract name
+//   - table (string): table name
+func RegisterTransferTable(contract, table string) {
+	transferTablesMu.Lock()
+	defer transferTablesMu.Unlock()
+
+	transferTables[contract] = table
+}
+
+// TransferTable returns the table holding a contract's transfers.
+//
+// Parameters:
+//   - contract (string): contract name ("" = the shared table)
+//
+// Returns:
+//   - string: the contract's table, or "transfers" if it has none
+func TransferTable(contract string) string {
+	transferTablesMu.RLock()
+	defer transferTablesMu.RUnlock()
+
+	if table, ok := transferTables[contract]; ok {
+		return table
+	}
+	return transfersTable
+}
+
+// transferTableNames returns every table holding transfers: the shared
+// table first, then the contract tables in name order.
+//
+// Returns:
+//   - []string: table names, each once
+func transferTableNames() []string {
+	transferTablesMu.RLock()
+	defer transferTablesMu.RUnlock()
+
+	seen := map[string]bool{transfersTable: true}
+	var tables []string
+	for _, table := range transferTables {
+		if !seen[table] {
+			seen[table] = true
+			tables = append(tables, table)
+		}
+	}
+	sort.Strings(tables)
+	return append([]string{transfersTable}, tables...)
+}
+
+// unionTransferTables repeats a query branch for every transfer table and
+// joins the branches with UNION ALL. The branch names its table with %[1]q.
+//
+// Parameters:
+//   - branch (string): query over one transfer table
+//
+// Returns:
+//   - string: the union over all transfer tables
+func unionTransferTables(branch string) string {
+	tables := transferTableNames()
+	branches := make([]string, len(tables))
+	for i, table := range tables {
+		branches[i] = fmt.Sprintf(branch, table)
+	}
+	return strings.Join(branches, "\n\tUNION ALL\n")
+}
+
+// MigrateTransferTable creates or updates a table with the transfers
+// schema, and registers it to roll back with reorgs like transfers.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - table (string): table name
+//
+// Returns:
+//   - error: nil on success, migration error on failure
+func (s *Store) MigrateTransferTable(ctx context.Context, table string) error {
+	if err := s.db.WithContext(ctx).Table(table).AutoMigrate(&Transfer{}); err != nil {
+		return fmt.Errorf("migrating transfer table %s: %w", table, err)
+	}
+	RegisterBlockTable(table)
+	return nil
+}
+
+// transfersQuery starts a query on the transfers table selected by q.Contract.
+func (s *Store) transfersQuery(ctx context.Context, q TransferQuery) *gorm.DB {
+	return s.db.WithContext(ctx).Model(&Transfer{}).Table(TransferTable(q.Contract))
+}
@@ -0,0 +1,122 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// naturalKeyColumns identify a log in an event table. The timestamp column
+// is included because TimescaleDB requires the partitioning column in
+// unique indexes on hypertables; a log's timestamp is fixed by its block.
+var naturalKeyColumns = []string{"tx_hash", "log_index", "timestamp"}
+
+// skipConflicts is the ON CONFLICT clause of every keyed insert into an
+// event table: a row already stored under the key is left as is, so
+// re-processing a block is a no-op and stored (possibly compressed) rows
+// are never rewritten.
+//
+// Parameters:
+//   - columns ([]string): columns of the unique index conflicted on
+//
+// Returns:
+//   - clause.OnConflict: the clause
+func skipConflicts(columns []string) clause.OnConflict {
+	target := make([]clause.Column, len(columns))
+	for i, column := range columns {
+		target[i] = clause.Column{Name: column}
+	}
+	return clause.OnConflict{Columns: target, DoNothing: true}
+}
+
+// EnsureNaturalKeyIndex creates the unique (tx_hash, log_index, timestamp)
+// index upserts on for a table with the BaseEvent columns. With natural
+// primary keys the primary key is that index and nothing is created.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - table (string): table name
+//
+// Returns:
+//   - error: nil on success, error if existing rows are duplicates under the key
+func (s *Store) EnsureNaturalKeyIndex(ctx context.Context, table string) error {
+	if s.NaturalKeys() {
+		return nil
+	}
+
+	quoted := make([]string, len(naturalKeyColumns))
+	for i, column := range naturalKeyColumns {
+		quoted[i] = fmt.Sprintf("%q", column)
+	}
+	name := "idx_" + table + "_natural_key"
+	if err := s.db.WithContext(ctx).Exec(fmt.Sprintf(
+		`CREATE UNIQUE INDEX IF NOT EXISTS %q ON %q (%s)`, name, table, strings.Join(quoted, ", "),
+	)).Error; err != nil {
+		return fmt.Errorf("creating natural key index on %s (remove duplicate rows first): %w", table, err)
+	}
+	return nil
+}
+
+// UpsertInBatches inserts event rows in batches, skipping rows already
+// stored under the same (tx_hash, log_index), so re-processing a block
+// doesn't duplicate them. The table needs EnsureNaturalKeyIndex.
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - records (interface{}): slice of records embedding BaseEvent
+//   - batchSize (int): number of records per batch
+//
+// Returns:
+//   - error: nil on success, upsert error on failure
+func (s *Store) UpsertInBatches(ctx context.Context, records interface{}, batchSize int) error {
+	start := time.Now()
+
+	if err := s.db.WithContext(ctx).Clauses(skipConflicts(naturalKeyColumns)).CreateInBatches(records, batchSize).Error; err != nil {
+		return fmt.Errorf("batch upsert: %w", err)
+	}
+
+	dbQueryDuration.WithLabelValues("batch_upsert").Observe(time.Since(start).Seconds())
+	return nil
+}
+
+// UpsertTransfers inserts transfers into the shared transfers table,
+// skipping transfers already stored under the same (tx_hash, log_index).
+//
+// Parameters:
+//   - ctx (context.Context): request context
+//   - transfers ([]Transfer): transfers to store
+//
+// Returns:
+//   - error: nil on success, upsert error on failure
+func (s *Store) UpsertTransfers(ctx context.Context, transfers []Transfer) error {
+	if len(transfers) == 0 {
+		return nil
+	}
+	return s.Transaction(ctx, func(tx *gorm.DB) error {
+		return UpsertTransfersTx(tx, Transfer{}.TableName(), transfers)
+	})
+}
+
+// UpsertTransfersTx upserts transfers into table using an existing
+// transaction, as UpsertTransfers does.
+//
+// Parameters:
+//   - tx (*gorm.DB): database transaction
+//   - table (string): transfers table (see TransferTable)
+//   - transfers ([]Transfer): transfers to store
+//
+// Returns:
+//   - error: nil on success, upsert error on failure
+func UpsertTransfersTx(tx *gorm.DB, table string, transfers []Transfer) error {
+	if len(transfers) == 0 {
+		return nil
+	}
+	if err := tx.Table(table).Clauses(skipConflicts(naturalKeyColumns)).CreateInBatches(transfers, 1000).Error; err != nil {
+		return fmt.Errorf("upserting transfers into %s: %w", table, err)
+	}
+	return nil
+}
@@ -0,0 +1,6 @@
+package watcher
+
+import "github.com/0xredeth/Rafale/internal/logging"
+
+// log is the watcher subsystem's logger (level set by log.levels.watcher).
+var log = logging.New("watcher")
@@ -3,9 +3,16 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/viper"
 )
 
@@ -14,7 +21,8 @@ type Config struct {
 	// Name is the indexer instance name.
 	Name string `mapstructure:"name"`
 
-	// Network is the target network (linea-mainnet, linea-sepolia).
+	// Network is the target network (linea-mainnet, linea-sepolia,
+	// ethereum-mainnet or a name declared in Networks).
 	Network string `mapstructure:"network"`
 
 	// Database is the PostgreSQL connection string.
@@ -23,15 +31,57 @@ type Config struct {
 	// RPCURL is the Linea RPC endpoint (overrides network preset).
 	RPCURL string `mapstructure:"rpc_url"`
 
+	// WSURL is a ws:// or wss:// endpoint the engine subscribes to for new
+	// heads instead of polling ("" = poll).
+	WSURL string `mapstructure:"ws_url"`
+
+	// RPC holds RPC client configuration.
+	RPC RPCConfig `mapstructure:"rpc"`
+
 	// Contracts defines the contracts to index.
 	Contracts map[string]ContractConfig `mapstructure:"contracts"`
 
+	// Networks declares custom networks (e.g. a local devnet) by name,
+	// overriding built-in presets of the same name.
+	Networks map[string]NetworkPreset `mapstructure:"networks"`
+
+	// Templates defines reusable ABI + events sets referenced by contracts.
+	Templates map[string]TemplateConfig `mapstructure:"templates"`
+
+	// Rules defines declarative handlers applied to decoded events.
+	Rules []RuleConfig `mapstructure:"rules"`
+
+	// EventSchemas declares the expected shape of decoded event data.
+	EventSchemas []EventSchemaConfig `mapstructure:"event_schemas"`
+
 	// Server holds API server configuration.
 	Server ServerConfig `mapstructure:"server"`
 
 	// Sync holds synchronization configuration.
 	Sync SyncConfig `mapstructure:"sync"`
 
+	// Log holds logging configuration.
+	Log LogConfig `mapstructure:"log"`
+
+	// Explorer configures the Etherscan-compatible API that contracts with
+	// abi: "etherscan" fetch their ABI from.
+	Explorer ExplorerConfig `mapstructure:"explorer"`
+
+	// ExpectedChainID is the chain ID the RPC must report (0 = not asserted).
+	// Defaults to the preset chain ID when the preset RPC is used; with a
+	// custom RPC and no chain_id, the chain ID is detected from the RPC.
+	ExpectedChainID uint64 `mapstructure:"chain_id"`
+
+	// AllowedNetworks restricts the networks this deployment may target
+	// (empty = any). Hosted deployments should set it via the
+	// RAFALE_ALLOWED_NETWORKS env var (comma-separated) so tenant config
+	// can't loosen it.
+	AllowedNetworks []string `mapstructure:"allowed_networks"`
+
+	// AllowedRPCPattern restricts the RPC host to a glob pattern such as
+	// "*.infura.io" (empty = any). Overridden by RAFALE_ALLOWED_RPC_PATTERN.
+	AllowedRPCPattern string `mapstructure:"allowed_rpc_pattern"`
+
 	// Derived fields (populated from network preset).
 	ChainID      uint64
 	PollInterval time.Duration
@@ -39,17 +89,78 @@ type Config struct {
 
 // ContractConfig defines a contract to index.
 type ContractConfig struct {
-	// ABI is the path to the ABI JSON file.
+	// Template is the name of a template providing ABI and Events.
+	// Fields set on the contract override the template.
+	Template string `mapstructure:"template"`
+
+	// ABI is the path to the ABI JSON file, or ABIExplorer to fetch the
+	// verified ABI of Address from the explorer at startup.
 	ABI string `mapstructure:"abi"`
 
+	// ABIs lists several ABI files merged for the address, e.g. the facets
+	// of an EIP-2535 diamond. An event signature found in more than one
+	// file is decoded with the first. Mutually exclusive with ABI.
+	ABIs []string `mapstructure:"abis"`
+
 	// Address is the contract address.
 	Address string `mapstructure:"address"`
 
 	// StartBlock is the block to start indexing from.
 	StartBlock uint64 `mapstructure:"start_block"`
 
+	// EndBlock is the last block to index (0 = follow the head). Sync stops
+	// once every contract has reached its end block.
+	EndBlock uint64 `mapstructure:"end_block"`
+
+	// Events is the list of event names to index.
+	Events []string `mapstructure:"events"`
+
+	// IndexAllEvents indexes every event in the ABI; Events must then be
+	// empty. The event list is resolved from the ABI at startup.
+	IndexAllEvents bool `mapstructure:"index_all_events"`
+
+	// EventAliases maps ABI event names to the name events are stored and
+	// dispatched under (e.g. Transfer: USDCTransfer).
+	EventAliases map[string]string `mapstructure:"event_aliases"`
+
+	// PollInterval polls this contract less often than the global poll
+	// interval (0 = global). Must be at least the network block time and
+	// the global poll interval: contracts can't be polled more often.
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+
+	// Table stores the contract's transfers in their own table, with the
+	// transfers schema, instead of the shared transfers table ("" = shared).
+	Table string `mapstructure:"table"`
+
+	// Decimals is the token's decimals, used to add a value_decimal next
+	// to raw transfer values in queries and exports (nil = unknown).
+	Decimals *int `mapstructure:"decimals"`
+}
+
+// ReservedTables are tables a contract's Table must not name.
+var ReservedTables = []string{"events", "transfers", "sync_statuses", "block_coverage", "reverted_txs"}
+
+// ABIPaths returns the contract's ABI files: ABIs, or ABI alone.
+//
+// Returns:
+//   - []string: ABI file paths in order of precedence
+func (c ContractConfig) ABIPaths() []string {
+	if len(c.ABIs) > 0 {
+		return c.ABIs
+	}
+	return []string{c.ABI}
+}
+
+// TemplateConfig is a reusable ABI and event list shared by contracts.
+type TemplateConfig struct {
+	// ABI is the path to the ABI JSON file.
+	ABI string `mapstructure:"abi"`
+
 	// Events is the list of event names to index.
 	Events []string `mapstructure:"events"`
+
+	// IndexAllEvents indexes every event in the ABI.
+	IndexAllEvents bool `mapstructure:"index_all_events"`
 }
 
 // ServerConfig holds API server configuration.
@@ -59,28 +170,329 @@ type ServerConfig struct {
 
 	// MetricsPort is the Prometheus metrics port.
 	MetricsPort int `mapstructure:"metrics_port"`
+
+	// AdminPort is the admin API port (0 disables the admin API).
+	AdminPort int `mapstructure:"admin_port"`
+
+	// AdminToken is the bearer token required by the admin API.
+	AdminToken string `mapstructure:"admin_token"`
+
+	// CacheSize is the number of events and of transfers the API keeps in
+	// in-memory LRU caches for lookups by ID (0 = no caching).
+	CacheSize int `mapstructure:"cache_size"`
+
+	// OrderedEvents delivers subscription events in strict (block number,
+	// log index) order, holding each batch's events until it completes.
+	OrderedEvents bool `mapstructure:"ordered_events"`
+
+	// OrderTimeout publishes events held by OrderedEvents once held this
+	// long, even if their batch never completes.
+	OrderTimeout time.Duration `mapstructure:"order_timeout"`
+
+	// HealthMaxLag is the most blocks sync may trail the confirmed head
+	// while /readyz still reports ready. /healthz ignores the lag.
+	HealthMaxLag uint64 `mapstructure:"health_max_lag"`
+
+	// SubscriptionBuffer is the number of events each GraphQL event
+	// subscriber may fall behind by; past it, its oldest events are
+	// dropped (0 = the default of 100).
+	SubscriptionBuffer int `mapstructure:"subscription_buffer"`
+}
+
+// RPCConfig holds RPC client configuration.
+type RPCConfig struct {
+	// MaxConcurrentPerEndpoint caps simultaneous in-flight requests to the
+	// RPC endpoint (0 = unlimited). Callers beyond the cap wait for a slot.
+	MaxConcurrentPerEndpoint int `mapstructure:"max_concurrent_per_endpoint"`
+
+	// MaxResponseBytes caps the size of a single RPC response (0 =
+	// transport default). Oversized getLogs responses are re-fetched in
+	// smaller block ranges.
+	MaxResponseBytes int64 `mapstructure:"max_response_bytes"`
 }
 
+// ExplorerConfig holds the block explorer API used to fetch ABIs.
+type ExplorerConfig struct {
+	// APIURL is an Etherscan-compatible API endpoint, e.g.
+	// https://api.lineascan.build/api or a Blockscout /api URL.
+	APIURL string `mapstructure:"api_url"`
+
+	// APIKey is the explorer API key. Overridden by RAFALE_EXPLORER_API_KEY.
+	APIKey string `mapstructure:"api_key"`
+
+	// CacheDir stores fetched ABIs, per chain ID, so restarts don't
+	// refetch them ("" = no cache).
+	CacheDir string `mapstructure:"cache_dir"`
+}
+
+// ABIExplorer is the ContractConfig.ABI value that fetches the contract's
+// verified ABI from the explorer instead of reading a file.
+const ABIExplorer = "etherscan"
+
+// UsesExplorer reports whether any contract fetches its ABI from the explorer.
+//
+// Returns:
+//   - bool: true if a contract has abi: etherscan
+func (c *Config) UsesExplorer() bool {
+	for _, contract := range c.Contracts {
+		if slices.Contains(contract.ABIPaths(), ABIExplorer) {
+			return true
+		}
+	}
+	return false
+}
+
+// LogConfig holds logging configuration.
+type LogConfig struct {
+	// Levels sets the log level of subsystems (LogSubsystems) independently
+	// of the global level, e.g. {rpc: debug, store: warn}. The store level
+	// also sets the GORM SQL log level.
+	Levels map[string]string `mapstructure:"levels"`
+}
+
+// LogSubsystems are the subsystems LogConfig.Levels may name.
+var LogSubsystems = []string{"engine", "rpc", "store", "api", "pubsub", "watcher", "handler"}
+
+// LogLevels are the level names LogConfig.Levels accepts.
+var LogLevels = []string{"trace", "debug", "info", "warn", "error", "fatal", "panic", "disabled"}
+
 // SyncConfig holds synchronization configuration.
 type SyncConfig struct {
 	// BatchSize is the number of blocks to fetch per batch.
 	BatchSize uint64 `mapstructure:"batch_size"`
 
+	// Concurrency is the number of batches fetched from the RPC at once
+	// while catching up (0 or 1 = one at a time). Batches are still
+	// committed one by one in block order. Contracts on their own poll
+	// interval and filter_mode fetch one batch at a time.
+	Concurrency int `mapstructure:"concurrency"`
+
 	// MaxRetries is the maximum RPC retry attempts.
 	MaxRetries int `mapstructure:"max_retries"`
 
 	// RetryDelay is the initial retry delay.
 	RetryDelay time.Duration `mapstructure:"retry_delay"`
+
+	// MaxReorgDepth caps how many blocks a reorg rollback may delete.
+	// Deeper reorgs halt the engine instead of deleting data. 0 keeps no
+	// history below the tip, so any reorg halts the engine.
+	MaxReorgDepth uint64 `mapstructure:"max_reorg_depth"`
+
+	// Confirmations is how many blocks must be built on a block before it
+	// is indexed: sync treats head - confirmations as the head, including
+	// for lag and synced status. 0 indexes up to the head.
+	Confirmations uint64 `mapstructure:"confirmations"`
+
+	// MaxDataBytes caps the serialized size of a decoded event's data
+	// (0 = unlimited). Larger events are handled per DataOverflowPolicy.
+	MaxDataBytes int `mapstructure:"max_data_bytes"`
+
+	// DataOverflowPolicy is applied to events exceeding MaxDataBytes:
+	// "truncate", "hash" or "skip".
+	DataOverflowPolicy string `mapstructure:"data_overflow_policy"`
+
+	// SchemaViolationPolicy is applied to events whose data doesn't match
+	// their event_schemas entry: "skip" or "halt".
+	SchemaViolationPolicy string `mapstructure:"schema_violation_policy"`
+
+	// StoreRawLog keeps each event's raw topics and data alongside the
+	// decoded Data so it can be re-decoded without RPC.
+	StoreRawLog bool `mapstructure:"store_raw_log"`
+
+	// StoreAddressCase is the case of addresses stored in DB columns:
+	// "lower" (default) or "checksum". Address filters match in that case,
+	// and rows stored in the other one are rewritten at startup. Decoded
+	// JSON data keeps checksums.
+	StoreAddressCase string `mapstructure:"store_address_case"`
+
+	// StallTimeout restarts the sync loop when no tick succeeds for this
+	// long (0 = watchdog disabled).
+	StallTimeout time.Duration `mapstructure:"stall_timeout"`
+
+	// MaxDatabaseBytes pauses syncing while the database is at least this
+	// large (pg_database_size), resuming once space is reclaimed
+	// (0 = disabled).
+	MaxDatabaseBytes int64 `mapstructure:"max_database_bytes"`
+
+	// DiskCheckInterval is how often the database size is checked when
+	// MaxDatabaseBytes is set.
+	DiskCheckInterval time.Duration `mapstructure:"disk_check_interval"`
+
+	// AutoAnalyze refreshes planner statistics on the event tables (ANALYZE,
+	// or VACUUM ANALYZE without TimescaleDB) when sync first reaches the
+	// head and every AutoAnalyzeRows stored logs, so queries don't crawl on
+	// stale plans after a backfill. Leave off on managed services that
+	// autovacuum aggressively.
+	AutoAnalyze bool `mapstructure:"auto_analyze"`
+
+	// AutoAnalyzeRows is the number of processed logs between analyzes
+	// when AutoAnalyze is set (0 = only on reaching the head).
+	AutoAnalyzeRows int64 `mapstructure:"auto_analyze_rows"`
+
+	// CheckpointRedisURL mirrors the sync cursor to Redis after each batch
+	// (redis:// or rediss://), so it can be read without a database
+	// round-trip. At startup sync resumes from the mirror at once; the
+	// database stays authoritative: the mirror is checked against it in the
+	// background before the first batch commits, and on a mismatch sync
+	// moves to the database's cursor ("" = disabled).
+	CheckpointRedisURL string `mapstructure:"checkpoint_redis_url"`
+
+	// StartupJitter delays the start by a random duration in [0, jitter)
+	// so a fleet deployed together doesn't hit the RPC at once (0 = none).
+	StartupJitter time.Duration `mapstructure:"startup_jitter"`
+
+	// SkipWarmUp starts syncing without first checking that the RPC node
+	// reports a plausible, advancing head.
+	SkipWarmUp bool `mapstructure:"skip_warm_up"`
+
+	// HandlerWorkers runs typed handlers on this many workers (0 or 1 =
+	// serially). Handlers write in the batch transaction either way; with
+	// workers their statements run one at a time.
+	HandlerWorkers int `mapstructure:"handler_workers"`
+
+	// HandlerPartition selects which events share a worker and keep their
+	// order: "contract" (default) or "address" (first indexed argument,
+	// topic 1). Address mode orders only events sharing that argument: a
+	// Transfer is ordered with others from the same sender, not with those
+	// to its recipient (topic 2), so handlers keeping per-account state
+	// across both sides need contract mode or SetHandlerPartitionKey.
+	HandlerPartition string `mapstructure:"handler_partition"`
+
+	// HandlerScheduling selects how workers pick queued events: "fifo"
+	// (default, submission order) or "fair" (round-robin across event
+	// types, so a flood of one event doesn't delay the others).
+	HandlerScheduling string `mapstructure:"handler_scheduling"`
+
+	// HandlerMaxShare caps, in fair scheduling, the fraction of workers
+	// running handlers of one event type at once (0 = no cap; at least
+	// one worker is always allowed).
+	HandlerMaxShare float64 `mapstructure:"handler_max_share"`
+
+	// BlockCoverage records a row per processed block with its matched log
+	// count, so gaps ("never processed") are told apart from empty blocks.
+	BlockCoverage bool `mapstructure:"block_coverage"`
+
+	// VerifyLogRanges cross-checks getLogs responses for silent
+	// truncation and re-fetches suspect ranges in smaller pieces, at the
+	// cost of an extra request per range.
+	VerifyLogRanges bool `mapstructure:"verify_log_ranges"`
+
+	// VerifyBlockHashes checks each fetched log's block hash against the
+	// canonical header of its block and re-fetches the range on a mismatch,
+	// rejecting logs from a provider serving an orphaned chain view.
+	VerifyBlockHashes bool `mapstructure:"verify_block_hashes"`
+
+	// FilterMode follows new blocks with an installed log filter
+	// (eth_newFilter + eth_getFilterChanges) instead of a getLogs call per
+	// batch. Catch-up ranges still use getLogs; an expired filter is
+	// re-installed and the gap backfilled.
+	FilterMode bool `mapstructure:"filter_mode"`
+
+	// BloomFilter skips getLogs for a single-block batch whose header
+	// logsBloom rules out every watched address or event, as when
+	// following the head block by block. Ignored with filter_mode and
+	// contracts on their own poll_interval.
+	BloomFilter bool `mapstructure:"bloom_filter"`
+
+	// AllowChainIDMismatch downgrades an RPC chain ID differing from
+	// expected_chain_id from a startup error to a warning, for proxied
+	// RPCs or network migrations. The detected chain ID is used.
+	AllowChainIDMismatch bool `mapstructure:"allow_chain_id_mismatch"`
+
+	// TrackReverts stores failed transactions sent to indexed contracts
+	// (table reverted_txs) with their decoded revert reason. Failed
+	// transactions emit no logs, so every block in a batch is fetched with
+	// its transactions, plus a receipt per candidate and an eth_call replay
+	// per revert: expect several times the RPC usage.
+	TrackReverts bool `mapstructure:"track_reverts"`
+
+	// DedupKey selects the unique key events are deduplicated on:
+	// "tx_log" (tx_hash, log_index; default), "block_hash" (block_hash,
+	// log_index) or "position" (block_number, tx_index, log_index). An
+	// event already stored under the key is kept and the new one skipped.
+	DedupKey string `mapstructure:"dedup_key"`
+
+	// PrimaryKey selects the primary key of the events and transfers
+	// tables, applied at migration: "id" (auto-increment id; default) or
+	// "natural" (tx_hash, log_index), for sharded or multi-instance
+	// deployments merging rows. Natural keys require the tx_log dedup key,
+	// and rows are then read by "txHash:logIndex" keys rather than ids.
+	// With either key, a row already stored under (tx_hash, log_index) is
+	// kept on re-processing, as with DedupKey.
+	PrimaryKey string `mapstructure:"primary_key"`
+
+	// BroadcastAfterCommit holds subscription events until their batch
+	// transaction commits and tags them with the committed sync cursor,
+	// so clients can hand off from historical queries to the live stream
+	// without gaps or overlap.
+	BroadcastAfterCommit bool `mapstructure:"broadcast_after_commit"`
 }
 
+// Dedup keys for SyncConfig.DedupKey.
+const (
+	DedupKeyTxLog     = "tx_log"
+	DedupKeyBlockHash = "block_hash"
+	DedupKeyPosition  = "position"
+)
+
+// Primary keys for SyncConfig.PrimaryKey.
+const (
+	PrimaryKeyID      = "id"
+	PrimaryKeyNatural = "natural"
+)
+
+// Handler scheduling modes for SyncConfig.HandlerScheduling.
+const (
+	HandlerSchedulingFIFO = "fifo"
+	HandlerSchedulingFair = "fair"
+)
+
+// Handler partition modes for SyncConfig.HandlerPartition.
+const (
+	HandlerPartitionContract = "contract"
+	HandlerPartitionAddress  = "address"
+)
+
+// Address cases for SyncConfig.StoreAddressCase.
+const (
+	// AddressCaseLower stores addresses lowercased.
+	AddressCaseLower = "lower"
+
+	// AddressCaseChecksum stores addresses EIP-55 checksummed.
+	AddressCaseChecksum = "checksum"
+)
+
+// Data overflow policies for SyncConfig.DataOverflowPolicy.
+const (
+	// DataPolicyTruncate keeps the fields that fit and flags the data as truncated.
+	DataPolicyTruncate = "truncate"
+
+	// DataPolicyHash replaces the data with its SHA-256 and original size,
+	// dropping markers that don't fit max_data_bytes.
+	DataPolicyHash = "hash"
+
+	// DataPolicySkip drops the event entirely.
+	DataPolicySkip = "skip"
+)
+
 // Load reads configuration from file and environment.
 //
+// Parameters:
+//   - paths (...string): optional config files or directories, merged in
+//     order via ReadFiles; when empty, the config already read into viper is used
+//
 // Returns:
 //   - *Config: the loaded configuration
 //   - error: nil on success, configuration error on failure
-func Load() (*Config, error) {
+func Load(paths ...string) (*Config, error) {
 	cfg := &Config{}
 
+	if len(paths) > 0 {
+		if err := ReadFiles(paths...); err != nil {
+			return nil, err
+		}
+	}
+
 	// Set defaults
 	setDefaults()
 
@@ -90,13 +502,13 @@ func Load() (*Config, error) {
 	}
 
 	// Apply network preset
-	preset, ok := NetworkPresets[cfg.Network]
+	preset, ok := cfg.NetworkPreset()
 	if !ok {
-		return nil, fmt.Errorf("unknown network: %s (valid: linea-mainnet, linea-sepolia)", cfg.Network)
+		return nil, fmt.Errorf("unknown network: %s (valid: %s)", cfg.Network, strings.Join(cfg.networkNames(), ", "))
 	}
 
 	cfg.ChainID = preset.ChainID
-	cfg.PollInterval = preset.PollInterval
+	cfg.PollInterval = preset.EffectivePollInterval()
 
 	// Use preset RPC if not overridden
 	if cfg.RPCURL == "" {
@@ -112,6 +524,44 @@ func Load() (*Config, error) {
 	if rpcURL := os.Getenv("LINEA_RPC_URL"); rpcURL != "" {
 		cfg.RPCURL = rpcURL
 	}
+	if wsURL := os.Getenv("LINEA_WS_URL"); wsURL != "" {
+		cfg.WSURL = wsURL
+	}
+
+	// Only the preset's own RPC is known to serve the preset chain
+	if cfg.ExpectedChainID == 0 && cfg.RPCURL == preset.DefaultRPC {
+		cfg.ExpectedChainID = preset.ChainID
+	}
+	if cfg.ExpectedChainID != 0 {
+		cfg.ChainID = cfg.ExpectedChainID
+	}
+
+	// Allow environment variable override for admin token (keeps secrets out of the config file)
+	if token := os.Getenv("RAFALE_ADMIN_TOKEN"); token != "" {
+		cfg.Server.AdminToken = token
+	}
+
+	// Allow environment variable override for the explorer API key
+	if apiKey := os.Getenv("RAFALE_EXPLORER_API_KEY"); apiKey != "" {
+		cfg.Explorer.APIKey = apiKey
+	}
+
+	// Allow environment variable override for the network/RPC allowlist
+	// (operator policy, enforced regardless of the config file)
+	if networks := os.Getenv("RAFALE_ALLOWED_NETWORKS"); networks != "" {
+		cfg.AllowedNetworks = strings.Split(networks, ",")
+		for i := range cfg.AllowedNetworks {
+			cfg.AllowedNetworks[i] = strings.TrimSpace(cfg.AllowedNetworks[i])
+		}
+	}
+	if pattern := os.Getenv("RAFALE_ALLOWED_RPC_PATTERN"); pattern != "" {
+		cfg.AllowedRPCPattern = pattern
+	}
+
+	// Expand contract templates
+	if err := cfg.ExpandTemplates(); err != nil {
+		return nil, err
+	}
 
 	// Validate required fields
 	if err := cfg.Validate(); err != nil {
@@ -121,45 +571,440 @@ func Load() (*Config, error) {
 	return cfg, nil
 }
 
-// Validate checks that all required configuration is present.
+// ReadFiles reads config files into viper, merging each over the previous
+// ones so later files override earlier keys (maps merge, lists replace).
+// A directory expands to its *.yaml and *.yml files in lexical order.
+// Environment overrides are applied afterwards by Load.
+//
+// Parameters:
+//   - paths (...string): config files or directories, base first
+//
+// Returns:
+//   - error: nil on success, read or parse error on failure
+func ReadFiles(paths ...string) error {
+	files, err := expandConfigPaths(paths)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no config files found in %s", strings.Join(paths, ", "))
+	}
+
+	for i, file := range files {
+		viper.SetConfigFile(file)
+
+		read := viper.MergeInConfig
+		if i == 0 {
+			read = viper.ReadInConfig
+		}
+		if err := read(); err != nil {
+			return fmt.Errorf("reading config %s: %w", file, err)
+		}
+	}
+
+	return nil
+}
+
+// expandConfigPaths replaces directories with the config files they contain.
+func expandConfigPaths(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading config %s: %w", path, err)
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading config directory %s: %w", path, err)
+		}
+		// ReadDir returns entries sorted by name
+		for _, entry := range entries {
+			ext := filepath.Ext(entry.Name())
+			if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+				continue
+			}
+			files = append(files, filepath.Join(path, entry.Name()))
+		}
+	}
+	return files, nil
+}
+
+// ExpandTemplates fills ABI and Events of contracts that reference a
+// template. Values set directly on a contract take precedence.
+//
+// Returns:
+//   - error: nil on success, ConfigErrors listing every contract referencing
+//     an undefined template otherwise
+func (c *Config) ExpandTemplates() error {
+	var errs ConfigErrors
+
+	names := make([]string, 0, len(c.Contracts))
+	for name := range c.Contracts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		contract := c.Contracts[name]
+		if contract.Template == "" {
+			continue
+		}
+
+		tmpl, ok := c.Templates[contract.Template]
+		if !ok {
+			errs.addContract(name, "template", "unknown template %q", contract.Template)
+			continue
+		}
+
+		if contract.ABI == "" && len(contract.ABIs) == 0 {
+			contract.ABI = tmpl.ABI
+		}
+		if len(contract.Events) == 0 && !contract.IndexAllEvents {
+			contract.Events = append([]string(nil), tmpl.Events...)
+			contract.IndexAllEvents = tmpl.IndexAllEvents
+		}
+		c.Contracts[name] = contract
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Validate checks that all required configuration is present. All problems
+// are reported at once.
 //
 // Returns:
-//   - error: nil if valid, validation error otherwise
+//   - error: nil if valid, ConfigErrors listing every problem otherwise
 func (c *Config) Validate() error {
+	var errs ConfigErrors
+
 	if c.Name == "" {
-		return fmt.Errorf("name is required")
+		errs.add("name", "name is required")
 	}
 	if c.Network == "" {
-		return fmt.Errorf("network is required")
+		errs.add("network", "network is required")
 	}
 	if c.Database == "" {
-		return fmt.Errorf("database connection string is required (set DATABASE_URL env var or database in config)")
+		errs.add("database", "database connection string is required (set DATABASE_URL env var or database in config)")
 	}
 	if len(c.Contracts) == 0 {
-		return fmt.Errorf("at least one contract must be defined")
+		errs.add("contracts", "at least one contract must be defined")
 	}
 
-	for name, contract := range c.Contracts {
-		if contract.Address == "" {
-			return fmt.Errorf("contract %s: address is required", name)
+	networkNames := make([]string, 0, len(c.Networks))
+	for name := range c.Networks {
+		networkNames = append(networkNames, name)
+	}
+	sort.Strings(networkNames)
+	for _, name := range networkNames {
+		network := c.Networks[name]
+		field := "networks." + name
+		if network.ChainID == 0 {
+			errs.add(field+".chain_id", "chain_id is required")
 		}
-		if contract.ABI == "" {
-			return fmt.Errorf("contract %s: abi path is required", name)
+		if network.PollInterval < 0 || network.BlockTime < 0 {
+			errs.add(field, "poll_interval and block_time must not be negative")
+		} else if network.PollInterval == 0 && network.BlockTime == 0 {
+			errs.add(field+".poll_interval", "poll_interval or block_time is required")
 		}
-		if len(contract.Events) == 0 {
-			return fmt.Errorf("contract %s: at least one event must be specified", name)
+	}
+
+	// Sorted so the report is stable across runs
+	names := make([]string, 0, len(c.Contracts))
+	for name := range c.Contracts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	preset, hasPreset := c.NetworkPreset()
+	for _, name := range names {
+		contract := c.Contracts[name]
+		switch {
+		case contract.Address == "":
+			errs.addContract(name, "address", "address is required")
+		case !common.IsHexAddress(contract.Address):
+			errs.addContract(name, "address", "invalid address %q", contract.Address)
+		default:
+			// Checksum form keeps decoder registration and lookups consistent
+			contract.Address = common.HexToAddress(contract.Address).Hex()
+			c.Contracts[name] = contract
+		}
+		switch {
+		case contract.ABI == "" && len(contract.ABIs) == 0:
+			errs.addContract(name, "abi", "abi path is required")
+		case contract.ABI != "" && len(contract.ABIs) > 0:
+			errs.addContract(name, "abis", "abi and abis are mutually exclusive")
+		case slices.Contains(contract.ABIs, ""):
+			errs.addContract(name, "abis", "abis must not contain empty paths")
+		}
+		if contract.Table != "" {
+			switch {
+			case !sqlIdentifier.MatchString(contract.Table):
+				errs.addContract(name, "table", "invalid table name %q", contract.Table)
+			case slices.Contains(ReservedTables, contract.Table):
+				errs.addContract(name, "table", "table %q is reserved", contract.Table)
+			}
+		}
+		if contract.Decimals != nil && (*contract.Decimals < 0 || *contract.Decimals > 77) {
+			errs.addContract(name, "decimals", "decimals must be between 0 and 77")
+		}
+		if contract.EndBlock > 0 && contract.EndBlock < contract.StartBlock {
+			errs.addContract(name, "end_block", "end_block %d is before start_block %d", contract.EndBlock, contract.StartBlock)
+		}
+		allEvents := contract.IndexAllEvents && len(contract.Events) == 0
+		switch {
+		case len(contract.Events) == 0 && !contract.IndexAllEvents:
+			errs.addContract(name, "events", "at least one event must be specified (or set index_all_events: true)")
+		case len(contract.Events) > 0 && contract.IndexAllEvents:
+			errs.addContract(name, "events", "events must be empty with index_all_events: true")
+		}
+		abiNames := make([]string, 0, len(contract.EventAliases))
+		for abiName := range contract.EventAliases {
+			abiNames = append(abiNames, abiName)
+		}
+		sort.Strings(abiNames)
+		for _, abiName := range abiNames {
+			alias := contract.EventAliases[abiName]
+			// With all events indexed, aliases are checked against the ABI at startup
+			if !allEvents && !slices.ContainsFunc(contract.Events, func(ev string) bool { return strings.EqualFold(ev, abiName) }) {
+				errs.addContract(name, "event_aliases", "event_aliases: %s is not in events", abiName)
+			}
+			if alias == "" || strings.Contains(alias, ":") {
+				errs.addContract(name, "event_aliases", "event_aliases: invalid alias %q for %s", alias, abiName)
+			}
+		}
+		if contract.PollInterval < 0 {
+			errs.addContract(name, "poll_interval", "poll_interval must not be negative")
+		}
+		if hasPreset && contract.PollInterval > 0 {
+			if contract.PollInterval < preset.BlockTime {
+				errs.addContract(name, "poll_interval", "poll_interval %s is shorter than the %s block time", contract.PollInterval, preset.BlockTime)
+			} else if global := preset.EffectivePollInterval(); contract.PollInterval < global {
+				errs.addContract(name, "poll_interval", "poll_interval %s is shorter than the network's %s poll interval", contract.PollInterval, global)
+			}
+		}
+		if c.Sync.FilterMode && contract.PollInterval > 0 {
+			errs.addContract(name, "poll_interval", "poll_interval can't be combined with sync.filter_mode")
 		}
 	}
 
+	c.validateRules(&errs)
+	c.validateEventSchemas(&errs)
+	c.validateAllowlist(&errs)
+
+	if c.Sync.StallTimeout < 0 {
+		errs.add("sync.stall_timeout", "sync.stall_timeout must not be negative")
+	}
+	if c.Sync.MaxDatabaseBytes < 0 {
+		errs.add("sync.max_database_bytes", "sync.max_database_bytes must not be negative")
+	}
+	if c.Sync.MaxDatabaseBytes > 0 && c.Sync.DiskCheckInterval <= 0 {
+		errs.add("sync.disk_check_interval", "sync.disk_check_interval must be positive when sync.max_database_bytes is set")
+	}
+	if c.Sync.AutoAnalyzeRows < 0 {
+		errs.add("sync.auto_analyze_rows", "sync.auto_analyze_rows must not be negative")
+	}
+	if c.Sync.CheckpointRedisURL != "" {
+		if u, err := url.Parse(c.Sync.CheckpointRedisURL); err != nil || (u.Scheme != "redis" && u.Scheme != "rediss") || u.Host == "" {
+			errs.add("sync.checkpoint_redis_url", "sync.checkpoint_redis_url must be a redis:// or rediss:// URL")
+		}
+	}
+	if c.Sync.StartupJitter < 0 {
+		errs.add("sync.startup_jitter", "sync.startup_jitter must not be negative")
+	}
+	if c.Sync.Concurrency < 0 {
+		errs.add("sync.concurrency", "sync.concurrency must not be negative")
+	}
+	if c.Sync.HandlerWorkers < 0 {
+		errs.add("sync.handler_workers", "sync.handler_workers must not be negative")
+	}
+	switch c.Sync.HandlerPartition {
+	case "", HandlerPartitionContract, HandlerPartitionAddress:
+	default:
+		errs.add("sync.handler_partition", "unknown sync.handler_partition %q (want %s or %s)",
+			c.Sync.HandlerPartition, HandlerPartitionContract, HandlerPartitionAddress)
+	}
+	switch c.Sync.HandlerScheduling {
+	case "", HandlerSchedulingFIFO, HandlerSchedulingFair:
+	default:
+		errs.add("sync.handler_scheduling", "unknown sync.handler_scheduling %q (want %s or %s)",
+			c.Sync.HandlerScheduling, HandlerSchedulingFIFO, HandlerSchedulingFair)
+	}
+	if c.Sync.HandlerMaxShare < 0 || c.Sync.HandlerMaxShare > 1 {
+		errs.add("sync.handler_max_share", "sync.handler_max_share must be between 0 and 1")
+	}
+	subsystems := make([]string, 0, len(c.Log.Levels))
+	for subsystem := range c.Log.Levels {
+		subsystems = append(subsystems, subsystem)
+	}
+	sort.Strings(subsystems)
+	for _, subsystem := range subsystems {
+		if !slices.Contains(LogSubsystems, subsystem) {
+			errs.add("log.levels", "log.levels: unknown subsystem %q (valid: %s)", subsystem, strings.Join(LogSubsystems, ", "))
+			continue
+		}
+		if level := c.Log.Levels[subsystem]; !slices.Contains(LogLevels, level) {
+			errs.add("log.levels", "log.levels.%s: unknown level %q (valid: %s)", subsystem, level, strings.Join(LogLevels, ", "))
+		}
+	}
+
+	switch c.Sync.DedupKey {
+	case "", DedupKeyTxLog, DedupKeyBlockHash, DedupKeyPosition:
+	default:
+		errs.add("sync.dedup_key", "unknown sync.dedup_key %q (want %s, %s or %s)",
+			c.Sync.DedupKey, DedupKeyTxLog, DedupKeyBlockHash, DedupKeyPosition)
+	}
+	switch c.Sync.PrimaryKey {
+	case "", PrimaryKeyID:
+	case PrimaryKeyNatural:
+		if c.Sync.DedupKey != "" && c.Sync.DedupKey != DedupKeyTxLog {
+			errs.add("sync.primary_key", "sync.primary_key %s requires sync.dedup_key %s", PrimaryKeyNatural, DedupKeyTxLog)
+		}
+	default:
+		errs.add("sync.primary_key", "unknown sync.primary_key %q (want %s or %s)",
+			c.Sync.PrimaryKey, PrimaryKeyID, PrimaryKeyNatural)
+	}
+	if c.Sync.MaxDataBytes < 0 {
+		errs.add("sync.max_data_bytes", "sync.max_data_bytes must not be negative")
+	} else if c.Sync.MaxDataBytes == 1 {
+		errs.add("sync.max_data_bytes", "sync.max_data_bytes must be 0 or at least 2, the size of an empty JSON object")
+	}
+	switch c.Sync.DataOverflowPolicy {
+	case "", DataPolicyTruncate, DataPolicyHash, DataPolicySkip:
+	default:
+		errs.add("sync.data_overflow_policy", "sync.data_overflow_policy: unknown policy %q (valid: truncate, hash, skip)", c.Sync.DataOverflowPolicy)
+	}
+
+	switch c.Sync.SchemaViolationPolicy {
+	case "", SchemaPolicySkip, SchemaPolicyHalt:
+	default:
+		errs.add("sync.schema_violation_policy", "sync.schema_violation_policy: unknown policy %q (valid: skip, halt)", c.Sync.SchemaViolationPolicy)
+	}
+
+	switch c.Sync.StoreAddressCase {
+	case "", AddressCaseLower, AddressCaseChecksum:
+	default:
+		errs.add("sync.store_address_case", "sync.store_address_case: unknown case %q (valid: lower, checksum)", c.Sync.StoreAddressCase)
+	}
+
+	if c.Server.AdminPort > 0 && c.Server.AdminToken == "" {
+		errs.add("server.admin_token", "server.admin_token is required when admin API is enabled (set RAFALE_ADMIN_TOKEN env var or server.admin_token in config)")
+	}
+
+	if c.Server.CacheSize < 0 {
+		errs.add("server.cache_size", "server.cache_size must not be negative")
+	}
+	if c.Server.SubscriptionBuffer < 0 {
+		errs.add("server.subscription_buffer", "server.subscription_buffer must not be negative")
+	}
+	if c.Server.OrderedEvents && c.Server.OrderTimeout <= 0 {
+		errs.add("server.order_timeout", "server.order_timeout must be positive when server.ordered_events is set")
+	}
+	if c.WSURL != "" {
+		if u, err := url.Parse(c.WSURL); err != nil || (u.Scheme != "ws" && u.Scheme != "wss") || u.Host == "" {
+			errs.add("ws_url", "ws_url must be a ws:// or wss:// URL")
+		}
+	}
+	if c.RPC.MaxConcurrentPerEndpoint < 0 {
+		errs.add("rpc.max_concurrent_per_endpoint", "rpc.max_concurrent_per_endpoint must not be negative")
+	}
+	if c.RPC.MaxResponseBytes < 0 {
+		errs.add("rpc.max_response_bytes", "rpc.max_response_bytes must not be negative")
+	}
+	if c.Explorer.APIURL != "" {
+		if u, err := url.Parse(c.Explorer.APIURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs.add("explorer.api_url", "explorer.api_url must be an http:// or https:// URL")
+		}
+	} else if c.UsesExplorer() {
+		errs.add("explorer.api_url", "explorer.api_url is required when a contract uses abi: %s", ABIExplorer)
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
 	return nil
 }
 
+// validateAllowlist checks the network and RPC hosts against the
+// deployment's allowlist, if any.
+func (c *Config) validateAllowlist(errs *ConfigErrors) {
+	if len(c.AllowedNetworks) > 0 && c.Network != "" && !slices.Contains(c.AllowedNetworks, c.Network) {
+		errs.add("network", "network %s is not allowed (allowed: %s)", c.Network, strings.Join(c.AllowedNetworks, ", "))
+	}
+
+	if c.AllowedRPCPattern == "" {
+		return
+	}
+	for _, endpoint := range []struct{ field, url string }{{"rpc_url", c.RPCURL}, {"ws_url", c.WSURL}} {
+		if endpoint.url == "" {
+			continue
+		}
+		u, err := url.Parse(endpoint.url)
+		if err != nil || u.Hostname() == "" {
+			errs.add(endpoint.field, "%s has no host to check against allowed_rpc_pattern", endpoint.field)
+			continue
+		}
+		matched, err := path.Match(strings.ToLower(c.AllowedRPCPattern), strings.ToLower(u.Hostname()))
+		if err != nil {
+			errs.add("allowed_rpc_pattern", "invalid allowed_rpc_pattern %q: %v", c.AllowedRPCPattern, err)
+			return
+		}
+		if !matched {
+			errs.add(endpoint.field, "rpc host %s is not allowed (must match %s)", u.Hostname(), c.AllowedRPCPattern)
+		}
+	}
+}
+
 // setDefaults sets default configuration values.
 func setDefaults() {
 	viper.SetDefault("network", "linea-mainnet")
 	viper.SetDefault("server.graphql_port", 8080)
 	viper.SetDefault("server.metrics_port", 9090)
+	viper.SetDefault("server.cache_size", 0)
+	viper.SetDefault("server.ordered_events", false)
+	viper.SetDefault("server.order_timeout", "5s")
+	viper.SetDefault("server.health_max_lag", 100)
+	viper.SetDefault("server.subscription_buffer", 100)
 	viper.SetDefault("sync.batch_size", 1000)
+	viper.SetDefault("sync.concurrency", 1)
 	viper.SetDefault("sync.max_retries", 3)
 	viper.SetDefault("sync.retry_delay", "1s")
+	viper.SetDefault("sync.max_reorg_depth", 100)
+	viper.SetDefault("sync.confirmations", 0)
+	viper.SetDefault("sync.max_data_bytes", 0)
+	viper.SetDefault("sync.data_overflow_policy", DataPolicyTruncate)
+	viper.SetDefault("sync.schema_violation_policy", SchemaPolicySkip)
+	viper.SetDefault("sync.store_raw_log", false)
+	viper.SetDefault("sync.stall_timeout", "0s")
+	viper.SetDefault("sync.max_database_bytes", 0)
+	viper.SetDefault("sync.disk_check_interval", "1m")
+	viper.SetDefault("sync.auto_analyze", false)
+	viper.SetDefault("sync.auto_analyze_rows", 1000000)
+	viper.SetDefault("sync.startup_jitter", "0s")
+	viper.SetDefault("sync.skip_warm_up", false)
+	viper.SetDefault("sync.checkpoint_redis_url", "")
+	viper.SetDefault("sync.handler_workers", 0)
+	viper.SetDefault("sync.handler_partition", HandlerPartitionContract)
+	viper.SetDefault("sync.handler_scheduling", HandlerSchedulingFIFO)
+	viper.SetDefault("sync.handler_max_share", 0)
+	viper.SetDefault("sync.block_coverage", false)
+	viper.SetDefault("sync.verify_log_ranges", false)
+	viper.SetDefault("sync.verify_block_hashes", false)
+	viper.SetDefault("sync.filter_mode", false)
+	viper.SetDefault("sync.bloom_filter", false)
+	viper.SetDefault("sync.allow_chain_id_mismatch", false)
+	viper.SetDefault("sync.track_reverts", false)
+	viper.SetDefault("sync.dedup_key", DedupKeyTxLog)
+	viper.SetDefault("sync.primary_key", PrimaryKeyID)
+	viper.SetDefault("sync.broadcast_after_commit", false)
+	viper.SetDefault("sync.store_address_case", AddressCaseLower)
+	viper.SetDefault("rpc.max_concurrent_per_endpoint", 0)
+	viper.SetDefault("rpc.max_response_bytes", 0)
+	viper.SetDefault("explorer.cache_dir", ".rafale/abis")
 }
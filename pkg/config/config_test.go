@@ -2,7 +2,9 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
@@ -23,7 +25,7 @@ func TestValidate(t *testing.T) {
 				Database: "postgres://localhost/test",
 				Contracts: map[string]ContractConfig{
 					"usdc": {
-						Address: "0x1234",
+						Address: "0x1234567890123456789012345678901234567890",
 						ABI:     "abis/erc20.json",
 						Events:  []string{"Transfer"},
 					},
@@ -38,7 +40,7 @@ func TestValidate(t *testing.T) {
 				Database: "postgres://localhost/test",
 				Contracts: map[string]ContractConfig{
 					"usdc": {
-						Address: "0x1234",
+						Address: "0x1234567890123456789012345678901234567890",
 						ABI:     "abis/erc20.json",
 						Events:  []string{"Transfer"},
 					},
@@ -54,7 +56,7 @@ func TestValidate(t *testing.T) {
 				Database: "postgres://localhost/test",
 				Contracts: map[string]ContractConfig{
 					"usdc": {
-						Address: "0x1234",
+						Address: "0x1234567890123456789012345678901234567890",
 						ABI:     "abis/erc20.json",
 						Events:  []string{"Transfer"},
 					},
@@ -70,7 +72,7 @@ func TestValidate(t *testing.T) {
 				Network: "linea-mainnet",
 				Contracts: map[string]ContractConfig{
 					"usdc": {
-						Address: "0x1234",
+						Address: "0x1234567890123456789012345678901234567890",
 						ABI:     "abis/erc20.json",
 						Events:  []string{"Transfer"},
 					},
@@ -114,7 +116,7 @@ func TestValidate(t *testing.T) {
 				Database: "postgres://localhost/test",
 				Contracts: map[string]ContractConfig{
 					"usdc": {
-						Address: "0x1234",
+						Address: "0x1234567890123456789012345678901234567890",
 						Events:  []string{"Transfer"},
 					},
 				},
@@ -130,7 +132,7 @@ func TestValidate(t *testing.T) {
 				Database: "postgres://localhost/test",
 				Contracts: map[string]ContractConfig{
 					"usdc": {
-						Address: "0x1234",
+						Address: "0x1234567890123456789012345678901234567890",
 						ABI:     "abis/erc20.json",
 						Events:  []string{},
 					},
@@ -139,6 +141,41 @@ func TestValidate(t *testing.T) {
 			wantErr:    true,
 			wantErrMsg: "contract usdc: at least one event must be specified",
 		},
+		{
+			name: "contract index all events",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address:        "0x1234567890123456789012345678901234567890",
+						ABI:            "abis/erc20.json",
+						IndexAllEvents: true,
+						EventAliases:   map[string]string{"transfer": "USDCTransfer"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "contract index all events with event list",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address:        "0x1234567890123456789012345678901234567890",
+						ABI:            "abis/erc20.json",
+						Events:         []string{"Transfer"},
+						IndexAllEvents: true,
+					},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "contract usdc: events must be empty with index_all_events: true",
+		},
 		{
 			name: "multiple contracts valid",
 			config: &Config{
@@ -147,14 +184,717 @@ func TestValidate(t *testing.T) {
 				Database: "postgres://localhost/test",
 				Contracts: map[string]ContractConfig{
 					"usdc": {
-						Address: "0x1234",
+						Address: "0x1234567890123456789012345678901234567890",
+						ABI:     "abis/erc20.json",
+						Events:  []string{"Transfer"},
+					},
+					"dai": {
+						Address: "0x5678567856785678567856785678567856785678",
+						ABI:     "abis/erc20.json",
+						Events:  []string{"Transfer", "Approval"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "admin API enabled without token",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address: "0x1234567890123456789012345678901234567890",
+						ABI:     "abis/erc20.json",
+						Events:  []string{"Transfer"},
+					},
+				},
+				Server: ServerConfig{AdminPort: 9091},
+			},
+			wantErr:    true,
+			wantErrMsg: "server.admin_token is required",
+		},
+		{
+			name: "admin API enabled with token",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address: "0x1234567890123456789012345678901234567890",
+						ABI:     "abis/erc20.json",
+						Events:  []string{"Transfer"},
+					},
+				},
+				Server: ServerConfig{AdminPort: 9091, AdminToken: "secret"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown data overflow policy",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address: "0x1234567890123456789012345678901234567890",
+						ABI:     "abis/erc20.json",
+						Events:  []string{"Transfer"},
+					},
+				},
+				Sync: SyncConfig{MaxDataBytes: 4096, DataOverflowPolicy: "compress"},
+			},
+			wantErr:    true,
+			wantErrMsg: `unknown policy "compress"`,
+		},
+		{
+			name: "negative max data bytes",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address: "0x1234567890123456789012345678901234567890",
+						ABI:     "abis/erc20.json",
+						Events:  []string{"Transfer"},
+					},
+				},
+				Sync: SyncConfig{MaxDataBytes: -1},
+			},
+			wantErr:    true,
+			wantErrMsg: "sync.max_data_bytes must not be negative",
+		},
+		{
+			name: "max data bytes below an empty object",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address: "0x1234567890123456789012345678901234567890",
+						ABI:     "abis/erc20.json",
+						Events:  []string{"Transfer"},
+					},
+				},
+				Sync: SyncConfig{MaxDataBytes: 1},
+			},
+			wantErr:    true,
+			wantErrMsg: "sync.max_data_bytes must be 0 or at least 2",
+		},
+		{
+			name: "negative stall timeout",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address: "0x1234567890123456789012345678901234567890",
+						ABI:     "abis/erc20.json",
+						Events:  []string{"Transfer"},
+					},
+				},
+				Sync: SyncConfig{StallTimeout: -time.Second},
+			},
+			wantErr:    true,
+			wantErrMsg: "sync.stall_timeout must not be negative",
+		},
+		{
+			name: "negative cache size",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address: "0x1234567890123456789012345678901234567890",
+						ABI:     "abis/erc20.json",
+						Events:  []string{"Transfer"},
+					},
+				},
+				Server: ServerConfig{CacheSize: -1},
+			},
+			wantErr:    true,
+			wantErrMsg: "server.cache_size must not be negative",
+		},
+		{
+			name: "ordered events without timeout",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address: "0x1234567890123456789012345678901234567890",
+						ABI:     "abis/erc20.json",
+						Events:  []string{"Transfer"},
+					},
+				},
+				Server: ServerConfig{OrderedEvents: true},
+			},
+			wantErr:    true,
+			wantErrMsg: "server.order_timeout must be positive when server.ordered_events is set",
+		},
+		{
+			name: "negative subscription buffer",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address: "0x1234567890123456789012345678901234567890",
+						ABI:     "abis/erc20.json",
+						Events:  []string{"Transfer"},
+					},
+				},
+				Server: ServerConfig{SubscriptionBuffer: -1},
+			},
+			wantErr:    true,
+			wantErrMsg: "server.subscription_buffer must not be negative",
+		},
+		{
+			name: "negative max concurrent per endpoint",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address: "0x1234567890123456789012345678901234567890",
+						ABI:     "abis/erc20.json",
+						Events:  []string{"Transfer"},
+					},
+				},
+				RPC: RPCConfig{MaxConcurrentPerEndpoint: -1},
+			},
+			wantErr:    true,
+			wantErrMsg: "rpc.max_concurrent_per_endpoint must not be negative",
+		},
+		{
+			name: "negative max response bytes",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address: "0x1234567890123456789012345678901234567890",
+						ABI:     "abis/erc20.json",
+						Events:  []string{"Transfer"},
+					},
+				},
+				RPC: RPCConfig{MaxResponseBytes: -1},
+			},
+			wantErr:    true,
+			wantErrMsg: "rpc.max_response_bytes must not be negative",
+		},
+		{
+			name: "negative auto analyze rows",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address: "0x1234567890123456789012345678901234567890",
+						ABI:     "abis/erc20.json",
+						Events:  []string{"Transfer"},
+					},
+				},
+				Sync: SyncConfig{AutoAnalyze: true, AutoAnalyzeRows: -1},
+			},
+			wantErr:    true,
+			wantErrMsg: "sync.auto_analyze_rows must not be negative",
+		},
+		{
+			name: "negative startup jitter",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address: "0x1234567890123456789012345678901234567890",
+						ABI:     "abis/erc20.json",
+						Events:  []string{"Transfer"},
+					},
+				},
+				Sync: SyncConfig{StartupJitter: -time.Second},
+			},
+			wantErr:    true,
+			wantErrMsg: "sync.startup_jitter must not be negative",
+		},
+		{
+			name: "invalid checkpoint redis url",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address: "0x1234567890123456789012345678901234567890",
+						ABI:     "abis/erc20.json",
+						Events:  []string{"Transfer"},
+					},
+				},
+				Sync: SyncConfig{CheckpointRedisURL: "localhost:6379"},
+			},
+			wantErr:    true,
+			wantErrMsg: "sync.checkpoint_redis_url must be a redis:// or rediss:// URL",
+		},
+		{
+			name: "abi and abis",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"diamond": {
+						Address: "0x1234567890123456789012345678901234567890",
+						ABI:     "abis/erc20.json",
+						ABIs:    []string{"abis/facet.json"},
+						Events:  []string{"Transfer"},
+					},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "abi and abis are mutually exclusive",
+		},
+		{
+			name: "reserved contract table",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address: "0x1234567890123456789012345678901234567890",
+						ABI:     "abis/erc20.json",
+						Events:  []string{"Transfer"},
+						Table:   "events",
+					},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: `table "events" is reserved`,
+		},
+		{
+			name: "unknown log subsystem",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address: "0x1234567890123456789012345678901234567890",
+						ABI:     "abis/erc20.json",
+						Events:  []string{"Transfer"},
+					},
+				},
+				Log: LogConfig{Levels: map[string]string{"sql": "debug"}},
+			},
+			wantErr:    true,
+			wantErrMsg: `log.levels: unknown subsystem "sql"`,
+		},
+		{
+			name: "unknown log level",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address: "0x1234567890123456789012345678901234567890",
+						ABI:     "abis/erc20.json",
+						Events:  []string{"Transfer"},
+					},
+				},
+				Log: LogConfig{Levels: map[string]string{"rpc": "verbose"}},
+			},
+			wantErr:    true,
+			wantErrMsg: `log.levels.rpc: unknown level "verbose"`,
+		},
+		{
+			name: "contract decimals out of range",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address:  "0x1234567890123456789012345678901234567890",
+						ABI:      "abis/erc20.json",
+						Events:   []string{"Transfer"},
+						Decimals: func() *int { d := 78; return &d }(),
+					},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "decimals must be between 0 and 77",
+		},
+		{
+			name: "ws url without websocket scheme",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				WSURL:    "https://rpc.linea.build",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address: "0x1234567890123456789012345678901234567890",
+						ABI:     "abis/erc20.json",
+						Events:  []string{"Transfer"},
+					},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "ws_url must be a ws:// or wss:// URL",
+		},
+		{
+			name: "etherscan abi without explorer url",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address: "0x1234567890123456789012345678901234567890",
+						ABI:     ABIExplorer,
+						Events:  []string{"Transfer"},
+					},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "explorer.api_url is required when a contract uses abi: etherscan",
+		},
+		{
+			name: "etherscan abi with explorer url",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Explorer: ExplorerConfig{APIURL: "https://api.lineascan.build/api"},
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address: "0x1234567890123456789012345678901234567890",
+						ABI:     ABIExplorer,
+						Events:  []string{"Transfer"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "contract end block before start block",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address:    "0x1234567890123456789012345678901234567890",
+						ABI:        "abis/erc20.json",
+						Events:     []string{"Transfer"},
+						StartBlock: 2000,
+						EndBlock:   1000,
+					},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "end_block 1000 is before start_block 2000",
+		},
+		{
+			name: "invalid contract table",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address: "0x1234567890123456789012345678901234567890",
+						ABI:     "abis/erc20.json",
+						Events:  []string{"Transfer"},
+						Table:   "usdc-transfers",
+					},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: `invalid table name "usdc-transfers"`,
+		},
+		{
+			name: "abis only",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"diamond": {
+						Address: "0x1234567890123456789012345678901234567890",
+						ABIs:    []string{"abis/loupe.json", "abis/facet.json"},
+						Events:  []string{"Transfer"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown handler partition",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address: "0x1234567890123456789012345678901234567890",
+						ABI:     "abis/erc20.json",
+						Events:  []string{"Transfer"},
+					},
+				},
+				Sync: SyncConfig{HandlerPartition: "sender"},
+			},
+			wantErr:    true,
+			wantErrMsg: `unknown sync.handler_partition "sender"`,
+		},
+		{
+			name: "event schema with unknown type",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address: "0x1234567890123456789012345678901234567890",
+						ABI:     "abis/erc20.json",
+						Events:  []string{"Transfer"},
+					},
+				},
+				EventSchemas: []EventSchemaConfig{
+					{Event: "usdc:Transfer", Fields: map[string]string{"value": "uint256"}},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: `event schema usdc:Transfer: field value has unknown type "uint256"`,
+		},
+		{
+			name: "event schema for unknown event",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address: "0x1234567890123456789012345678901234567890",
+						ABI:     "abis/erc20.json",
+						Events:  []string{"Transfer"},
+					},
+				},
+				EventSchemas: []EventSchemaConfig{
+					{Event: "usdc:Approval", Fields: map[string]string{"value": "uint"}},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: `unknown event "usdc:Approval"`,
+		},
+		{
+			name: "handler max share out of range",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address: "0x1234567890123456789012345678901234567890",
+						ABI:     "abis/erc20.json",
+						Events:  []string{"Transfer"},
+					},
+				},
+				Sync: SyncConfig{HandlerScheduling: HandlerSchedulingFair, HandlerMaxShare: 1.5},
+			},
+			wantErr:    true,
+			wantErrMsg: "sync.handler_max_share must be between 0 and 1",
+		},
+		{
+			name: "unknown dedup key",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address: "0x1234567890123456789012345678901234567890",
+						ABI:     "abis/erc20.json",
+						Events:  []string{"Transfer"},
+					},
+				},
+				Sync: SyncConfig{DedupKey: "tx_hash"},
+			},
+			wantErr:    true,
+			wantErrMsg: `unknown sync.dedup_key "tx_hash"`,
+		},
+		{
+			name: "natural primary key",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address: "0x1234567890123456789012345678901234567890",
+						ABI:     "abis/erc20.json",
+						Events:  []string{"Transfer"},
+					},
+				},
+				Sync: SyncConfig{PrimaryKey: PrimaryKeyNatural, DedupKey: DedupKeyTxLog},
+			},
+			wantErr: false,
+		},
+		{
+			name: "natural primary key with position dedup",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address: "0x1234567890123456789012345678901234567890",
+						ABI:     "abis/erc20.json",
+						Events:  []string{"Transfer"},
+					},
+				},
+				Sync: SyncConfig{PrimaryKey: PrimaryKeyNatural, DedupKey: DedupKeyPosition},
+			},
+			wantErr:    true,
+			wantErrMsg: "sync.primary_key natural requires sync.dedup_key tx_log",
+		},
+		{
+			name: "unknown primary key",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address: "0x1234567890123456789012345678901234567890",
 						ABI:     "abis/erc20.json",
 						Events:  []string{"Transfer"},
 					},
-					"dai": {
-						Address: "0x5678",
+				},
+				Sync: SyncConfig{PrimaryKey: "uuid"},
+			},
+			wantErr:    true,
+			wantErrMsg: `unknown sync.primary_key "uuid"`,
+		},
+		{
+			name: "event alias for unlisted event",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address:      "0x1234567890123456789012345678901234567890",
+						ABI:          "abis/erc20.json",
+						Events:       []string{"Transfer"},
+						EventAliases: map[string]string{"approval": "USDCApproval"},
+					},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "event_aliases: approval is not in events",
+		},
+		{
+			name: "event alias with separator",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address:      "0x1234567890123456789012345678901234567890",
+						ABI:          "abis/erc20.json",
+						Events:       []string{"Transfer"},
+						EventAliases: map[string]string{"transfer": "usdc:Transfer"},
+					},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: `invalid alias "usdc:Transfer" for transfer`,
+		},
+		{
+			name: "unknown address case",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address: "0x1234567890123456789012345678901234567890",
 						ABI:     "abis/erc20.json",
-						Events:  []string{"Transfer", "Approval"},
+						Events:  []string{"Transfer"},
+					},
+				},
+				Sync: SyncConfig{StoreAddressCase: "upper"},
+			},
+			wantErr:    true,
+			wantErrMsg: `sync.store_address_case: unknown case "upper"`,
+		},
+		{
+			name: "contract poll interval below block time",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address:      "0x1234567890123456789012345678901234567890",
+						ABI:          "abis/erc20.json",
+						Events:       []string{"Transfer"},
+						PollInterval: 500 * time.Millisecond,
+					},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "contract usdc: poll_interval 500ms is shorter than the 2s block time",
+		},
+		{
+			name: "contract poll interval below network poll interval",
+			config: &Config{
+				Name:     "test",
+				Network:  "devnet",
+				Database: "postgres://localhost/test",
+				Networks: map[string]NetworkPreset{
+					"devnet": {ChainID: 31337, BlockTime: time.Second, PollInterval: 10 * time.Second},
+				},
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address:      "0x1234567890123456789012345678901234567890",
+						ABI:          "abis/erc20.json",
+						Events:       []string{"Transfer"},
+						PollInterval: 5 * time.Second,
+					},
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "contract usdc: poll_interval 5s is shorter than the network's 10s poll interval",
+		},
+		{
+			name: "contract poll interval override",
+			config: &Config{
+				Name:     "test",
+				Network:  "linea-mainnet",
+				Database: "postgres://localhost/test",
+				Contracts: map[string]ContractConfig{
+					"usdc": {
+						Address:      "0x1234567890123456789012345678901234567890",
+						ABI:          "abis/erc20.json",
+						Events:       []string{"Transfer"},
+						PollInterval: 30 * time.Second,
 					},
 				},
 			},
@@ -176,6 +916,77 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestExpandTemplates(t *testing.T) {
+	cfg := &Config{
+		Templates: map[string]TemplateConfig{
+			"erc20": {ABI: "abis/erc20.json", Events: []string{"Transfer", "Approval"}},
+		},
+		Contracts: map[string]ContractConfig{
+			"usdc": {Template: "erc20", Address: "0x1234567890123456789012345678901234567890", StartBlock: 100},
+			"dai":  {Template: "erc20", Address: "0x5678567856785678567856785678567856785678", Events: []string{"Transfer"}},
+			"pool": {Address: "0x9abc9abc9abc9abc9abc9abc9abc9abc9abc9abc", ABI: "abis/pool.json", Events: []string{"Swap"}},
+		},
+	}
+
+	err := cfg.ExpandTemplates()
+	require.NoError(t, err)
+
+	require.Equal(t, "abis/erc20.json", cfg.Contracts["usdc"].ABI)
+	require.Equal(t, []string{"Transfer", "Approval"}, cfg.Contracts["usdc"].Events)
+	require.Equal(t, uint64(100), cfg.Contracts["usdc"].StartBlock)
+
+	// Contract fields override the template
+	require.Equal(t, []string{"Transfer"}, cfg.Contracts["dai"].Events)
+
+	// Contracts without a template are untouched
+	require.Equal(t, "abis/pool.json", cfg.Contracts["pool"].ABI)
+
+	// Expanded contracts share no backing array with the template
+	cfg.Contracts["usdc"].Events[0] = "Mutated"
+	require.Equal(t, "Transfer", cfg.Templates["erc20"].Events[0])
+}
+
+func TestExpandTemplatesIndexAllEvents(t *testing.T) {
+	cfg := &Config{
+		Templates: map[string]TemplateConfig{
+			"erc20": {ABI: "abis/erc20.json", IndexAllEvents: true},
+		},
+		Contracts: map[string]ContractConfig{
+			"usdc": {Template: "erc20", Address: "0x1234123412341234123412341234123412341234"},
+			"dai":  {Template: "erc20", Address: "0x5678567856785678567856785678567856785678", Events: []string{"Transfer"}},
+		},
+	}
+
+	require.NoError(t, cfg.ExpandTemplates())
+	require.True(t, cfg.Contracts["usdc"].IndexAllEvents)
+	require.Empty(t, cfg.Contracts["usdc"].Events)
+
+	// An explicit event list on the contract wins
+	require.False(t, cfg.Contracts["dai"].IndexAllEvents)
+	require.Equal(t, []string{"Transfer"}, cfg.Contracts["dai"].Events)
+}
+
+func TestExpandTemplatesUnknown(t *testing.T) {
+	cfg := &Config{
+		Contracts: map[string]ContractConfig{
+			"usdc": {Template: "erc721", Address: "0x1234123412341234123412341234123412341234"},
+			"dai":  {Template: "erc1155", Address: "0x5678567856785678567856785678567856785678"},
+		},
+	}
+
+	err := cfg.ExpandTemplates()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `unknown template "erc721"`)
+
+	// Every unknown template is reported, with the field at fault
+	var errs ConfigErrors
+	require.ErrorAs(t, err, &errs)
+	require.Equal(t, ConfigErrors{
+		{Field: "template", ContractName: "dai", Message: `unknown template "erc1155"`},
+		{Field: "template", ContractName: "usdc", Message: `unknown template "erc721"`},
+	}, errs)
+}
+
 func TestSetDefaults(t *testing.T) {
 	// Reset viper for clean state
 	viper.Reset()
@@ -188,6 +999,18 @@ func TestSetDefaults(t *testing.T) {
 	require.Equal(t, 1000, viper.GetInt("sync.batch_size"))
 	require.Equal(t, 3, viper.GetInt("sync.max_retries"))
 	require.Equal(t, "1s", viper.GetString("sync.retry_delay"))
+	require.Equal(t, 100, viper.GetInt("sync.max_reorg_depth"))
+	require.Equal(t, 0, viper.GetInt("sync.max_data_bytes"))
+	require.Equal(t, DataPolicyTruncate, viper.GetString("sync.data_overflow_policy"))
+	require.False(t, viper.GetBool("sync.store_raw_log"))
+	require.Equal(t, time.Duration(0), viper.GetDuration("sync.stall_timeout"))
+	require.Equal(t, time.Duration(0), viper.GetDuration("sync.startup_jitter"))
+	require.False(t, viper.GetBool("sync.auto_analyze"))
+	require.Equal(t, int64(1000000), viper.GetInt64("sync.auto_analyze_rows"))
+	require.Equal(t, 0, viper.GetInt("sync.handler_workers"))
+	require.Equal(t, HandlerPartitionContract, viper.GetString("sync.handler_partition"))
+	require.Equal(t, AddressCaseLower, viper.GetString("sync.store_address_case"))
+	require.Equal(t, DedupKeyTxLog, viper.GetString("sync.dedup_key"))
 }
 
 func TestLoadWithEnvOverrides(t *testing.T) {
@@ -298,3 +1121,463 @@ func TestConfigStructComplete(t *testing.T) {
 	require.True(t, ok)
 	require.Equal(t, "0x176211869cA2b568f2A7D4EE941E073a821EE1ff", usdc.Address)
 }
+
+func TestValidateNormalizesAddress(t *testing.T) {
+	cfg := &Config{
+		Name:     "test",
+		Network:  "linea-mainnet",
+		Database: "postgres://localhost/test",
+		Contracts: map[string]ContractConfig{
+			"usdc": {Address: "0x176211869ca2b568f2a7d4ee941e073a821ee1ff", ABI: "abis/erc20.json", Events: []string{"Transfer"}},
+		},
+	}
+
+	require.NoError(t, cfg.Validate())
+	require.Equal(t, "0x176211869cA2b568f2A7D4EE941E073a821EE1ff", cfg.Contracts["usdc"].Address)
+
+	cfg.Contracts["usdc"] = ContractConfig{Address: "0x1234", ABI: "abis/erc20.json", Events: []string{"Transfer"}}
+	require.EqualError(t, cfg.Validate(), `contract usdc: invalid address "0x1234"`)
+}
+
+func TestValidateReportsAllErrors(t *testing.T) {
+	cfg := &Config{
+		Network: "linea-mainnet",
+		Contracts: map[string]ContractConfig{
+			"usdc": {ABI: "abis/erc20.json", Events: []string{"Transfer"}},
+			"dai":  {Address: "0x5678"},
+		},
+	}
+
+	err := cfg.Validate()
+	require.Error(t, err)
+
+	var errs ConfigErrors
+	require.ErrorAs(t, err, &errs)
+	require.Equal(t, ConfigErrors{
+		{Field: "name", Message: "name is required"},
+		{Field: "database", Message: "database connection string is required (set DATABASE_URL env var or database in config)"},
+		{Field: "address", ContractName: "dai", Message: `invalid address "0x5678"`},
+		{Field: "abi", ContractName: "dai", Message: "abi path is required"},
+		{Field: "events", ContractName: "dai", Message: "at least one event must be specified (or set index_all_events: true)"},
+		{Field: "address", ContractName: "usdc", Message: "address is required"},
+	}, errs)
+
+	// Individual problems are reachable via errors.As
+	var first *ConfigError
+	require.ErrorAs(t, err, &first)
+	require.Equal(t, "name", first.Field)
+
+	require.Equal(t, `contract dai: invalid address "0x5678"`, errs[2].Error())
+	require.Contains(t, err.Error(), "name is required; database connection string is required")
+}
+
+func TestValidateSingleErrorMessage(t *testing.T) {
+	cfg := &Config{
+		Name:     "test",
+		Network:  "linea-mainnet",
+		Database: "postgres://localhost/test",
+		Contracts: map[string]ContractConfig{
+			"usdc": {ABI: "abis/erc20.json", Events: []string{"Transfer"}},
+		},
+	}
+
+	// A single problem keeps the historical message exactly
+	require.EqualError(t, cfg.Validate(), "contract usdc: address is required")
+}
+
+func TestLoadMergesFiles(t *testing.T) {
+	viper.Reset()
+	t.Setenv("DATABASE_URL", "postgres://env/db")
+
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.yaml")
+	overlay := filepath.Join(dir, "prod.yaml")
+
+	require.NoError(t, os.WriteFile(base, []byte(`
+name: base-indexer
+network: linea-mainnet
+database: postgres://base/db
+sync:
+  batch_size: 500
+  max_retries: 5
+contracts:
+  usdc:
+    address: "0x176211869cA2b568f2A7D4EE941E073a821EE1ff"
+    abi: ./abis/erc20.json
+    events: [Transfer, Approval]
+`), 0o600))
+	require.NoError(t, os.WriteFile(overlay, []byte(`
+name: prod-indexer
+sync:
+  batch_size: 2000
+contracts:
+  usdc:
+    events: [Transfer]
+`), 0o600))
+
+	cfg, err := Load(base, overlay)
+	require.NoError(t, err)
+
+	// Later files override earlier keys; untouched keys are kept
+	require.Equal(t, "prod-indexer", cfg.Name)
+	require.Equal(t, uint64(2000), cfg.Sync.BatchSize)
+	require.Equal(t, 5, cfg.Sync.MaxRetries)
+	require.Equal(t, "./abis/erc20.json", cfg.Contracts["usdc"].ABI)
+	require.Equal(t, []string{"Transfer"}, cfg.Contracts["usdc"].Events)
+
+	// Environment overrides apply last
+	require.Equal(t, "postgres://env/db", cfg.Database)
+}
+
+func TestLoadEthereumMainnet(t *testing.T) {
+	viper.Reset()
+	t.Setenv("LINEA_RPC_URL", "")
+
+	path := filepath.Join(t.TempDir(), "rafale.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+name: l1-indexer
+network: ethereum-mainnet
+database: postgres://localhost/db
+contracts:
+  usdc:
+    address: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+    abi: ./abis/erc20.json
+    events: [Transfer]
+`), 0o600))
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), cfg.ChainID)
+	require.Equal(t, 12*time.Second, cfg.PollInterval)
+	require.Equal(t, "https://ethereum-rpc.publicnode.com", cfg.RPCURL)
+}
+
+func TestLoadCustomNetwork(t *testing.T) {
+	tests := []struct {
+		name      string
+		network   string
+		networks  string
+		wantChain uint64
+		wantPoll  time.Duration
+		wantRPC   string
+		wantErr   string
+	}{
+		{
+			name:    "custom network",
+			network: "anvil",
+			networks: `
+networks:
+  anvil:
+    chain_id: 31337
+    block_time: 2s
+    default_rpc: http://localhost:8545
+`,
+			wantChain: 31337,
+			wantPoll:  2 * time.Second,
+			wantRPC:   "http://localhost:8545",
+		},
+		{
+			name:    "overrides built-in preset",
+			network: "linea-mainnet",
+			networks: `
+networks:
+  linea-mainnet:
+    chain_id: 59144
+    poll_interval: 500ms
+    default_rpc: http://linea-node:8545
+`,
+			wantChain: 59144,
+			wantPoll:  500 * time.Millisecond,
+			wantRPC:   "http://linea-node:8545",
+		},
+		{
+			name:    "unknown network lists custom names",
+			network: "devnet",
+			networks: `
+networks:
+  anvil:
+    chain_id: 31337
+    block_time: 2s
+`,
+			wantErr: "anvil",
+		},
+		{
+			name:    "missing chain id",
+			network: "anvil",
+			networks: `
+networks:
+  anvil:
+    block_time: 2s
+`,
+			wantErr: "chain_id is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			t.Setenv("LINEA_RPC_URL", "")
+
+			path := filepath.Join(t.TempDir(), "rafale.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(`
+name: devnet-indexer
+network: `+tt.network+`
+database: postgres://localhost/db
+contracts:
+  token:
+    address: "0x5FbDB2315678afecb367f032d93F642f64180aa3"
+    abi: ./abis/erc20.json
+    events: [Transfer]
+`+tt.networks), 0o600))
+
+			cfg, err := Load(path)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantChain, cfg.ChainID)
+			require.Equal(t, tt.wantPoll, cfg.PollInterval)
+			require.Equal(t, tt.wantRPC, cfg.RPCURL)
+		})
+	}
+}
+
+func TestReadFilesDirectory(t *testing.T) {
+	viper.Reset()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "10-base.yaml"), []byte("name: base\nnetwork: linea-sepolia\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "20-local.yml"), []byte("name: local\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("ignored"), 0o600))
+
+	require.NoError(t, ReadFiles(dir))
+	require.Equal(t, "local", viper.GetString("name"))
+	require.Equal(t, "linea-sepolia", viper.GetString("network"))
+}
+
+func TestReadFilesErrors(t *testing.T) {
+	viper.Reset()
+
+	err := ReadFiles(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.Error(t, err)
+
+	err = ReadFiles(t.TempDir())
+	require.ErrorContains(t, err, "no config files found")
+}
+
+func TestLoadExpectedChainID(t *testing.T) {
+	t.Setenv("LINEA_RPC_URL", "")
+
+	write := func(t *testing.T, body string) string {
+		path := filepath.Join(t.TempDir(), "rafale.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`
+name: test
+network: linea-mainnet
+database: postgres://localhost/test
+contracts:
+  usdc:
+    address: "0x176211869cA2b568f2A7D4EE941E073a821EE1ff"
+    abi: ./abis/erc20.json
+    events: [Transfer]
+`+body), 0o600))
+		return path
+	}
+
+	tests := []struct {
+		name         string
+		body         string
+		wantExpected uint64
+		wantChainID  uint64
+	}{
+		{"preset RPC enforces preset chain", "", 59144, 59144},
+		{"custom RPC detects chain", "rpc_url: https://rpc.example.com\n", 0, 59144},
+		{"custom RPC with asserted chain", "rpc_url: https://rpc.example.com\nchain_id: 8453\n", 8453, 8453},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			viper.Reset()
+			cfg, err := Load(write(t, tc.body))
+			require.NoError(t, err)
+			require.Equal(t, tc.wantExpected, cfg.ExpectedChainID)
+			require.Equal(t, tc.wantChainID, cfg.ChainID)
+		})
+	}
+}
+
+func TestValidateRules(t *testing.T) {
+	base := func(rules ...RuleConfig) *Config {
+		return &Config{
+			Name:     "test",
+			Network:  "linea-mainnet",
+			Database: "postgres://localhost/test",
+			Contracts: map[string]ContractConfig{
+				"usdc": {
+					Address:      "0x1234567890123456789012345678901234567890",
+					ABI:          "abis/erc20.json",
+					Events:       []string{"Transfer", "Approval"},
+					EventAliases: map[string]string{"approval": "USDCApproval"},
+				},
+			},
+			Rules: rules,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		rule    RuleConfig
+		wantErr string
+	}{
+		{
+			name: "valid table rule",
+			rule: RuleConfig{Name: "big", Event: "usdc:Transfer", Table: "big_transfers", Columns: map[string]string{"sender": "from"}, Where: []PredicateConfig{{Field: "value", Op: OpGt, Value: "100"}}},
+		},
+		{
+			name: "valid broadcast rule on alias",
+			rule: RuleConfig{Name: "approvals", Event: "usdc:USDCApproval", Broadcast: true},
+		},
+		{
+			name:    "unknown event",
+			rule:    RuleConfig{Name: "r", Event: "dai:Transfer", Broadcast: true},
+			wantErr: `rule r: unknown event "dai:Transfer"`,
+		},
+		{
+			name:    "no action",
+			rule:    RuleConfig{Name: "r", Event: "usdc:Transfer"},
+			wantErr: "rule r: needs a table or broadcast",
+		},
+		{
+			name:    "invalid table",
+			rule:    RuleConfig{Name: "r", Event: "usdc:Transfer", Table: "big-transfers", Columns: map[string]string{"sender": "from"}},
+			wantErr: `rule r: invalid table name "big-transfers"`,
+		},
+		{
+			name:    "reserved table",
+			rule:    RuleConfig{Name: "r", Event: "usdc:Transfer", Table: "transfers", Columns: map[string]string{"sender": "from"}},
+			wantErr: `rule r: table "transfers" is reserved`,
+		},
+		{
+			name:    "reserved column",
+			rule:    RuleConfig{Name: "r", Event: "usdc:Transfer", Table: "t", Columns: map[string]string{"tx_hash": "from"}},
+			wantErr: `rule r: invalid column name "tx_hash"`,
+		},
+		{
+			name:    "unknown op",
+			rule:    RuleConfig{Name: "r", Event: "usdc:Transfer", Broadcast: true, Where: []PredicateConfig{{Field: "value", Op: "between"}}},
+			wantErr: `rule r: unknown op "between" on value`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := base(tc.rule).Validate()
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+
+	err := base(RuleConfig{Name: "r", Event: "usdc:Transfer", Broadcast: true}, RuleConfig{Name: "r", Event: "usdc:Transfer", Broadcast: true}).Validate()
+	require.ErrorContains(t, err, "rule r: duplicate name")
+
+	cfg := base(RuleConfig{Name: "r", Event: "usdc:Transfer", Table: "usdc_transfers", Columns: map[string]string{"sender": "from"}})
+	usdc := cfg.Contracts["usdc"]
+	usdc.Table = "usdc_transfers"
+	cfg.Contracts["usdc"] = usdc
+	require.ErrorContains(t, cfg.Validate(), `rule r: table "usdc_transfers" is the table of contract usdc`)
+
+	// Rules may share a table only with the same columns
+	err = base(
+		RuleConfig{Name: "out", Event: "usdc:Transfer", Table: "moves", Columns: map[string]string{"account": "from"}},
+		RuleConfig{Name: "in", Event: "usdc:Transfer", Table: "moves", Columns: map[string]string{"account": "to"}},
+	).Validate()
+	require.NoError(t, err)
+	err = base(
+		RuleConfig{Name: "out", Event: "usdc:Transfer", Table: "moves", Columns: map[string]string{"account": "from"}},
+		RuleConfig{Name: "in", Event: "usdc:Transfer", Table: "moves", Columns: map[string]string{"receiver": "to"}},
+	).Validate()
+	require.ErrorContains(t, err, `rule in: table "moves" is shared with rule out but has different columns`)
+}
+
+func TestValidateAllowlist(t *testing.T) {
+	base := func() *Config {
+		return &Config{
+			Name:     "test",
+			Network:  "linea-mainnet",
+			Database: "postgres://localhost/test",
+			RPCURL:   "https://linea-mainnet.infura.io/v3/key",
+			Contracts: map[string]ContractConfig{
+				"usdc": {Address: "0x1234567890123456789012345678901234567890", ABI: "abis/erc20.json", Events: []string{"Transfer"}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		networks   []string
+		pattern    string
+		wantErrMsg string
+	}{
+		{name: "no allowlist"},
+		{name: "allowed network", networks: []string{"linea-sepolia", "linea-mainnet"}},
+		{name: "disallowed network", networks: []string{"linea-sepolia"}, wantErrMsg: "network linea-mainnet is not allowed (allowed: linea-sepolia)"},
+		{name: "matching rpc host", pattern: "*.infura.io"},
+		{name: "pattern is case-insensitive", pattern: "*.INFURA.io"},
+		{name: "disallowed rpc host", pattern: "*.example.com", wantErrMsg: "rpc host linea-mainnet.infura.io is not allowed (must match *.example.com)"},
+		{name: "invalid pattern", pattern: "[", wantErrMsg: "invalid allowed_rpc_pattern"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := base()
+			cfg.AllowedNetworks = tt.networks
+			cfg.AllowedRPCPattern = tt.pattern
+
+			err := cfg.Validate()
+			if tt.wantErrMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.wantErrMsg)
+		})
+	}
+}
+
+func TestLoadAllowlistEnv(t *testing.T) {
+	t.Setenv("LINEA_RPC_URL", "")
+	t.Setenv("RAFALE_ALLOWED_NETWORKS", "linea-sepolia, linea-mainnet")
+	t.Setenv("RAFALE_ALLOWED_RPC_PATTERN", "*.example.com")
+
+	path := filepath.Join(t.TempDir(), "rafale.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+name: test
+network: linea-mainnet
+database: postgres://localhost/test
+rpc_url: https://rpc.other.org
+allowed_rpc_pattern: "*"
+contracts:
+  usdc:
+    address: "0x176211869cA2b568f2A7D4EE941E073a821EE1ff"
+    abi: ./abis/erc20.json
+    events: [Transfer]
+`), 0o600))
+
+	viper.Reset()
+	_, err := Load(path)
+	require.ErrorContains(t, err, "rpc host rpc.other.org is not allowed")
+}
+
+func TestHasEventIndexAllEvents(t *testing.T) {
+	cfg := &Config{Contracts: map[string]ContractConfig{
+		"usdc": {IndexAllEvents: true},
+		"dai":  {Events: []string{"Transfer"}},
+	}}
+
+	require.True(t, cfg.hasEvent("usdc:Approval"))
+	require.False(t, cfg.hasEvent("usdc:"))
+	require.True(t, cfg.hasEvent("dai:Transfer"))
+	require.False(t, cfg.hasEvent("dai:Approval"))
+}
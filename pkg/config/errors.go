@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConfigError describes a single configuration problem.
+type ConfigError struct {
+	// Field is the config key at fault (e.g. "name", "sync.max_data_bytes").
+	// For contract fields it is relative to the contract (e.g. "address").
+	Field string
+
+	// ContractName is the contract the problem belongs to, if any.
+	ContractName string
+
+	// Message describes the problem.
+	Message string
+}
+
+// Error returns the problem in the same form Validate has always reported.
+func (e *ConfigError) Error() string {
+	if e.ContractName != "" {
+		return fmt.Sprintf("contract %s: %s", e.ContractName, e.Message)
+	}
+	return e.Message
+}
+
+// ConfigErrors is the set of problems found by Validate.
+type ConfigErrors []*ConfigError
+
+// Error joins all problems with "; ".
+func (errs ConfigErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap exposes the individual problems to errors.Is and errors.As.
+func (errs ConfigErrors) Unwrap() []error {
+	out := make([]error, len(errs))
+	for i, err := range errs {
+		out[i] = err
+	}
+	return out
+}
+
+// add records a top-level problem.
+func (errs *ConfigErrors) add(field, format string, args ...any) {
+	*errs = append(*errs, &ConfigError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// addContract records a problem with a contract.
+func (errs *ConfigErrors) addContract(name, field, format string, args ...any) {
+	*errs = append(*errs, &ConfigError{Field: field, ContractName: name, Message: fmt.Sprintf(format, args...)})
+}
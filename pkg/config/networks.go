@@ -1,23 +1,45 @@
 package config
 
-import "time"
+import (
+	"slices"
+	"sort"
+	"time"
+)
 
-// NetworkPreset contains network-specific default values.
+// NetworkPreset contains network-specific default values. Custom networks
+// are declared with the same fields in the config's networks section.
 type NetworkPreset struct {
 	// ChainID is the network chain ID.
-	ChainID uint64
+	ChainID uint64 `mapstructure:"chain_id"`
 
-	// PollInterval is the block polling interval.
-	PollInterval time.Duration
+	// PollInterval is the block polling interval (defaults to BlockTime
+	// for custom networks).
+	PollInterval time.Duration `mapstructure:"poll_interval"`
 
 	// DefaultRPC is the default public RPC endpoint.
-	DefaultRPC string
+	DefaultRPC string `mapstructure:"default_rpc"`
 
 	// BlockTime is the expected block time.
-	BlockTime time.Duration
+	BlockTime time.Duration `mapstructure:"block_time"`
 
 	// L1ChainID is the L1 chain ID (Ethereum mainnet or Sepolia).
-	L1ChainID uint64
+	L1ChainID uint64 `mapstructure:"l1_chain_id"`
+
+	// MinHeadBlock is a conservative lower bound for a synced node's head.
+	// A lower reported head means the node is still syncing.
+	MinHeadBlock uint64 `mapstructure:"min_head_block"`
+}
+
+// EffectivePollInterval returns the network's poll interval, defaulting to
+// its block time.
+//
+// Returns:
+//   - time.Duration: global poll interval on the network
+func (p NetworkPreset) EffectivePollInterval() time.Duration {
+	if p.PollInterval == 0 {
+		return p.BlockTime
+	}
+	return p.PollInterval
 }
 
 // NetworkPresets contains all supported network configurations.
@@ -28,6 +50,7 @@ var NetworkPresets = map[string]NetworkPreset{
 		DefaultRPC:   "https://rpc.linea.build",
 		BlockTime:    2 * time.Second,
 		L1ChainID:    1, // Ethereum mainnet
+		MinHeadBlock: 1_000_000,
 	},
 	"linea-sepolia": {
 		ChainID:      59141,
@@ -35,6 +58,15 @@ var NetworkPresets = map[string]NetworkPreset{
 		DefaultRPC:   "https://rpc.sepolia.linea.build",
 		BlockTime:    2 * time.Second,
 		L1ChainID:    11155111, // Sepolia
+		MinHeadBlock: 1_000_000,
+	},
+	"ethereum-mainnet": {
+		ChainID:      1,
+		PollInterval: 12 * time.Second,
+		DefaultRPC:   "https://ethereum-rpc.publicnode.com",
+		BlockTime:    12 * time.Second,
+		L1ChainID:    1, // itself
+		MinHeadBlock: 20_000_000,
 	},
 }
 
@@ -51,14 +83,41 @@ func GetNetworkPreset(network string) (NetworkPreset, bool) {
 	return preset, ok
 }
 
+// NetworkPreset returns the preset of the configured network: a custom
+// network from the networks section, which overrides a built-in preset of
+// the same name, or a built-in preset.
+//
+// Returns:
+//   - NetworkPreset: the network preset
+//   - bool: true if found, false otherwise
+func (c *Config) NetworkPreset() (NetworkPreset, bool) {
+	if preset, ok := c.Networks[c.Network]; ok {
+		return preset, true
+	}
+	return GetNetworkPreset(c.Network)
+}
+
+// networkNames returns the built-in and custom network names, sorted.
+func (c *Config) networkNames() []string {
+	names := SupportedNetworks()
+	for name := range c.Networks {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
 // SupportedNetworks returns a list of supported network names.
 //
 // Returns:
-//   - []string: list of supported network names
+//   - []string: list of supported network names, sorted
 func SupportedNetworks() []string {
 	networks := make([]string, 0, len(NetworkPresets))
 	for name := range NetworkPresets {
 		networks = append(networks, name)
 	}
+	sort.Strings(networks)
 	return networks
 }
@@ -32,9 +32,17 @@ func TestNetworkPresets(t *testing.T) {
 			wantL1Chain:  11155111,
 			wantExists:   true,
 		},
+		{
+			name:         "ethereum-mainnet",
+			network:      "ethereum-mainnet",
+			wantChainID:  1,
+			wantPoll:     12 * time.Second,
+			wantL1Chain:  1,
+			wantExists:   true,
+		},
 		{
 			name:        "unknown network",
-			network:     "ethereum-mainnet",
+			network:     "polygon",
 			wantExists:  false,
 		},
 		{
@@ -59,6 +67,7 @@ func TestNetworkPresets(t *testing.T) {
 			require.Equal(t, tc.wantL1Chain, preset.L1ChainID)
 			require.NotEmpty(t, preset.DefaultRPC)
 			require.NotZero(t, preset.BlockTime)
+			require.NotZero(t, preset.MinHeadBlock)
 		})
 	}
 }
@@ -82,6 +91,12 @@ func TestGetNetworkPreset(t *testing.T) {
 			wantOK:    true,
 			wantChain: 59141,
 		},
+		{
+			name:      "valid ethereum",
+			network:   "ethereum-mainnet",
+			wantOK:    true,
+			wantChain: 1,
+		},
 		{
 			name:    "invalid network",
 			network: "polygon",
@@ -105,9 +120,7 @@ func TestGetNetworkPreset(t *testing.T) {
 func TestSupportedNetworks(t *testing.T) {
 	networks := SupportedNetworks()
 
-	require.Len(t, networks, 2)
-	require.Contains(t, networks, "linea-mainnet")
-	require.Contains(t, networks, "linea-sepolia")
+	require.Equal(t, []string{"ethereum-mainnet", "linea-mainnet", "linea-sepolia"}, networks)
 }
 
 func TestNetworkPresetFields(t *testing.T) {
@@ -126,6 +139,13 @@ func TestNetworkPresetFields(t *testing.T) {
 	require.Equal(t, "https://rpc.sepolia.linea.build", sepolia.DefaultRPC)
 	require.Equal(t, 2*time.Second, sepolia.BlockTime)
 	require.Equal(t, uint64(11155111), sepolia.L1ChainID)
+
+	ethereum := NetworkPresets["ethereum-mainnet"]
+
+	require.Equal(t, uint64(1), ethereum.ChainID)
+	require.Equal(t, 12*time.Second, ethereum.PollInterval)
+	require.Equal(t, 12*time.Second, ethereum.BlockTime)
+	require.Equal(t, uint64(1), ethereum.L1ChainID)
 }
 
 func TestNetworkPresetStruct(t *testing.T) {
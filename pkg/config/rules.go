@@ -0,0 +1,171 @@
+package config
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// RuleConfig is a declarative handler for one event: it stores matching
+// events as rows of a table and/or broadcasts them, without Go code.
+type RuleConfig struct {
+	// Name identifies the rule; broadcasts use it as the event name.
+	Name string `mapstructure:"name"`
+
+	// Event is the event ID the rule applies to ("contract:EventName").
+	Event string `mapstructure:"event"`
+
+	// Table is the table rows are inserted into (created if missing).
+	// Empty to only broadcast.
+	Table string `mapstructure:"table"`
+
+	// Columns maps table columns to decoded event fields.
+	Columns map[string]string `mapstructure:"columns"`
+
+	// Where lists predicates that must all hold for the rule to apply.
+	Where []PredicateConfig `mapstructure:"where"`
+
+	// Broadcast publishes matching events to subscribers under Name.
+	Broadcast bool `mapstructure:"broadcast"`
+}
+
+// PredicateConfig compares a decoded event field with a constant.
+type PredicateConfig struct {
+	// Field is the decoded event field.
+	Field string `mapstructure:"field"`
+
+	// Op is one of eq, ne, gt, gte, lt, lte (ordering ops need a numeric field).
+	Op string `mapstructure:"op"`
+
+	// Value is the constant, parsed according to the field's ABI type.
+	Value string `mapstructure:"value"`
+}
+
+// Predicate operators for PredicateConfig.Op.
+const (
+	OpEq  = "eq"
+	OpNe  = "ne"
+	OpGt  = "gt"
+	OpGte = "gte"
+	OpLt  = "lt"
+	OpLte = "lte"
+)
+
+// RuleReservedColumns are the columns every rule table has.
+var RuleReservedColumns = []string{"id", "block_number", "tx_hash", "log_index", "timestamp"}
+
+// sqlIdentifier matches table and column names usable without quoting issues.
+var sqlIdentifier = regexp.MustCompile(`^[a-z_][a-z0-9_]{0,62}$`)
+
+// validateRules checks rule definitions against the configured contracts.
+// Field names and predicate values are checked against the ABI when the
+// engine builds the rules.
+func (c *Config) validateRules(errs *ConfigErrors) {
+	seen := make(map[string]bool, len(c.Rules))
+	contractTables := make(map[string]string, len(c.Contracts))
+	for name, contract := range c.Contracts {
+		if contract.Table != "" {
+			contractTables[contract.Table] = name
+		}
+	}
+	// First rule storing into each table, to check later ones share its columns
+	tableOwners := make(map[string]RuleConfig)
+	for i, rule := range c.Rules {
+		field := "rules." + rule.Name
+		if rule.Name == "" {
+			errs.add("rules", "rules[%d]: name is required", i)
+			continue
+		}
+		if seen[rule.Name] {
+			errs.add(field, "rule %s: duplicate name", rule.Name)
+		}
+		seen[rule.Name] = true
+
+		if !c.hasEvent(rule.Event) {
+			errs.add(field, "rule %s: unknown event %q (want contract:EventName of a configured contract)", rule.Name, rule.Event)
+		}
+		if rule.Table == "" && !rule.Broadcast {
+			errs.add(field, "rule %s: needs a table or broadcast", rule.Name)
+		}
+		if rule.Table != "" {
+			switch {
+			case !sqlIdentifier.MatchString(rule.Table):
+				errs.add(field, "rule %s: invalid table name %q", rule.Name, rule.Table)
+			case slices.Contains(ReservedTables, rule.Table):
+				errs.add(field, "rule %s: table %q is reserved", rule.Name, rule.Table)
+			case contractTables[rule.Table] != "":
+				errs.add(field, "rule %s: table %q is the table of contract %s", rule.Name, rule.Table, contractTables[rule.Table])
+			}
+			if owner, ok := tableOwners[rule.Table]; !ok {
+				tableOwners[rule.Table] = rule
+			} else if !sameColumns(owner.Columns, rule.Columns) {
+				errs.add(field, "rule %s: table %q is shared with rule %s but has different columns", rule.Name, rule.Table, owner.Name)
+			}
+			if len(rule.Columns) == 0 {
+				errs.add(field, "rule %s: columns are required with a table", rule.Name)
+			}
+		}
+
+		columns := make([]string, 0, len(rule.Columns))
+		for column := range rule.Columns {
+			columns = append(columns, column)
+		}
+		sort.Strings(columns)
+		for _, column := range columns {
+			if !sqlIdentifier.MatchString(column) || slices.Contains(RuleReservedColumns, column) {
+				errs.add(field, "rule %s: invalid column name %q", rule.Name, column)
+			}
+		}
+
+		for _, p := range rule.Where {
+			switch p.Op {
+			case OpEq, OpNe, OpGt, OpGte, OpLt, OpLte:
+			default:
+				errs.add(field, "rule %s: unknown op %q on %s", rule.Name, p.Op, p.Field)
+			}
+		}
+	}
+}
+
+// sameColumns reports whether two rule column mappings define the same
+// table columns. The fields they map from may differ.
+func sameColumns(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for column := range a {
+		if _, ok := b[column]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// hasEvent reports whether eventID names a configured contract event,
+// by ABI name or alias. Any event of a contract indexing all its ABI events
+// is accepted here and checked against the ABI at startup.
+func (c *Config) hasEvent(eventID string) bool {
+	contractName, eventName, ok := strings.Cut(eventID, ":")
+	if !ok {
+		return false
+	}
+	contract, ok := c.Contracts[contractName]
+	if !ok {
+		return false
+	}
+	if contract.IndexAllEvents && len(contract.Events) == 0 {
+		return eventName != ""
+	}
+	for _, ev := range contract.Events {
+		if ev == eventName {
+			return true
+		}
+	}
+	for _, alias := range contract.EventAliases {
+		if alias == eventName {
+			return true
+		}
+	}
+	return false
+}
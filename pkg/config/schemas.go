@@ -0,0 +1,75 @@
+package config
+
+import (
+	"slices"
+	"sort"
+)
+
+// EventSchemaConfig is the expected shape of an event's decoded data.
+// Listed fields must be present with the given type; others are allowed.
+type EventSchemaConfig struct {
+	// Event is the event ID the schema applies to ("contract:EventName").
+	Event string `mapstructure:"event"`
+
+	// Fields maps decoded field names (matched case-insensitively) to
+	// their expected type.
+	Fields map[string]string `mapstructure:"fields"`
+}
+
+// Field types for EventSchemaConfig.Fields.
+const (
+	SchemaTypeAddress = "address"
+	SchemaTypeUint    = "uint"
+	SchemaTypeInt     = "int"
+	SchemaTypeBool    = "bool"
+	SchemaTypeString  = "string"
+	SchemaTypeBytes   = "bytes"
+	SchemaTypeArray   = "array"
+)
+
+// SchemaTypes lists the valid field types.
+var SchemaTypes = []string{
+	SchemaTypeAddress, SchemaTypeUint, SchemaTypeInt, SchemaTypeBool,
+	SchemaTypeString, SchemaTypeBytes, SchemaTypeArray,
+}
+
+// Schema violation policies for SyncConfig.SchemaViolationPolicy.
+const (
+	// SchemaPolicySkip drops the event and counts the violation.
+	SchemaPolicySkip = "skip"
+
+	// SchemaPolicyHalt fails the batch, stopping the sync until fixed.
+	SchemaPolicyHalt = "halt"
+)
+
+// validateEventSchemas checks schema definitions against the configured
+// contracts and the known field types.
+func (c *Config) validateEventSchemas(errs *ConfigErrors) {
+	seen := make(map[string]bool, len(c.EventSchemas))
+	for i, schema := range c.EventSchemas {
+		if !c.hasEvent(schema.Event) {
+			errs.add("event_schemas", "event_schemas[%d]: unknown event %q (want contract:EventName of a configured contract)", i, schema.Event)
+			continue
+		}
+		field := "event_schemas." + schema.Event
+		if seen[schema.Event] {
+			errs.add(field, "event schema %s: duplicate event", schema.Event)
+		}
+		seen[schema.Event] = true
+
+		if len(schema.Fields) == 0 {
+			errs.add(field, "event schema %s: fields are required", schema.Event)
+		}
+
+		names := make([]string, 0, len(schema.Fields))
+		for name := range schema.Fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if typ := schema.Fields[name]; !slices.Contains(SchemaTypes, typ) {
+				errs.add(field, "event schema %s: field %s has unknown type %q", schema.Event, name, typ)
+			}
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package decoder
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// DecodedCall represents decoded transaction input data.
+type DecodedCall struct {
+	// ContractName is the user-defined contract name.
+	ContractName string
+
+	// MethodName is the Solidity method name.
+	MethodName string
+
+	// Signature is the canonical method signature, e.g. "transfer(address,uint256)".
+	Signature string
+
+	// Selector is the 4-byte method selector.
+	Selector [4]byte
+
+	// Args contains the decoded arguments by name.
+	Args map[string]interface{}
+}
+
+// DecodeCalldata decodes the input data of a call to a registered contract:
+// the 4-byte selector is resolved against the contract ABI and the
+// remaining bytes are unpacked into named arguments. Every method of the
+// ABI is decoded, not only those of registered events.
+//
+// Parameters:
+//   - contractAddr (common.Address): called contract
+//   - input ([]byte): transaction input data
+//
+// Returns:
+//   - *DecodedCall: decoded call
+//   - error: nil on success, ErrUnknownContract, ErrUnknownMethod or ErrUnpack on failure
+func (d *Decoder) DecodeCalldata(contractAddr common.Address, input []byte) (*DecodedCall, error) {
+	parsed, ok := d.abis[contractAddr]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownContract, contractAddr.Hex())
+	}
+	if len(input) < 4 {
+		return nil, fmt.Errorf("%w: calldata too short (%d bytes)", ErrUnknownMethod, len(input))
+	}
+
+	method, err := parsed.MethodById(input[:4])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownMethod, hexutil.Encode(input[:4]))
+	}
+
+	args := make(map[string]interface{})
+	if err := method.Inputs.UnpackIntoMap(args, input[4:]); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnpack, err)
+	}
+	convertTuples(method.Inputs, args)
+
+	name, _ := d.ContractName(contractAddr)
+
+	return &DecodedCall{
+		ContractName: name,
+		MethodName:   method.Name,
+		Signature:    method.Sig,
+		Selector:     [4]byte(input[:4]),
+		Args:         args,
+	}, nil
+}
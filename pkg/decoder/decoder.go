@@ -2,7 +2,10 @@
 package decoder
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -10,11 +13,70 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
+// Decode errors, distinguishable via errors.Is.
+var (
+	// ErrNoTopics is returned for logs without a signature topic (anonymous events).
+	ErrNoTopics = errors.New("log has no topics")
+
+	// ErrUnknownEvent is returned when no registered event matches the log.
+	ErrUnknownEvent = errors.New("unknown event signature")
+
+	// ErrUnpack is returned when the log data does not match the event ABI.
+	ErrUnpack = errors.New("unpacking event data")
+
+	// ErrUnknownContract is returned when decoding calldata for an address
+	// without a registered ABI.
+	ErrUnknownContract = errors.New("unknown contract")
+
+	// ErrUnknownMethod is returned when no method of the contract ABI
+	// matches the calldata selector.
+	ErrUnknownMethod = errors.New("unknown method selector")
+)
+
 // Decoder decodes Ethereum event logs using contract ABIs.
+//
+// Logs are resolved by (address, signature) first so that contracts sharing
+// an event signature (e.g. ERC20 Transfer) decode under their own name. The
+// signature-only registry is kept as a fallback for logs from addresses that
+// were not registered explicitly.
 type Decoder struct {
 	abis    map[common.Address]*abi.ABI
 	events  map[common.Hash]*EventInfo
 	sigToID map[common.Hash]string // eventSig -> "ContractName:EventName"
+	byAddr  map[common.Address]map[common.Hash]*EventInfo
+	names   map[string]common.Address // contractName -> address
+}
+
+// NameConflictWarning is returned by RegisterContract when a contract name is
+// already registered to a different address. The new registration is still
+// applied; callers may treat it as a warning via errors.As.
+type NameConflictWarning struct {
+	// Name is the conflicting contract name.
+	Name string
+
+	// Existing is the address the name was previously registered to.
+	Existing common.Address
+
+	// Address is the newly registered address.
+	Address common.Address
+}
+
+// Error implements the error interface.
+func (w *NameConflictWarning) Error() string {
+	return fmt.Sprintf("contract name %q already registered to %s, now also registered to %s",
+		w.Name, w.Existing.Hex(), w.Address.Hex())
+}
+
+// Collision describes an event signature registered by more than one contract.
+type Collision struct {
+	// Signature is the event topic0 hash.
+	Signature common.Hash
+
+	// EventName is the Solidity event name.
+	EventName string
+
+	// Contracts lists the "ContractName@Address" registrations sharing the signature, sorted.
+	Contracts []string
 }
 
 // EventInfo holds metadata about a registered event.
@@ -22,7 +84,8 @@ type EventInfo struct {
 	// ContractName is the user-defined contract name.
 	ContractName string
 
-	// EventName is the Solidity event name.
+	// EventName is the stored event name: the Solidity event name, or its
+	// alias when registered with RegisterContractWithAliases.
 	EventName string
 
 	// ABI is the parsed contract ABI.
@@ -40,7 +103,7 @@ type DecodedEvent struct {
 	// ContractName is the user-defined contract name.
 	ContractName string
 
-	// EventName is the Solidity event name.
+	// EventName is the stored event name (the Solidity name unless aliased).
 	EventName string
 
 	// EventID is the unique identifier "ContractName:EventName".
@@ -62,6 +125,8 @@ func New() *Decoder {
 		abis:    make(map[common.Address]*abi.ABI),
 		events:  make(map[common.Hash]*EventInfo),
 		sigToID: make(map[common.Hash]string),
+		byAddr:  make(map[common.Address]map[common.Hash]*EventInfo),
+		names:   make(map[string]common.Address),
 	}
 }
 
@@ -74,14 +139,46 @@ func New() *Decoder {
 //   - eventNames ([]string): event names to register (empty for all)
 //
 // Returns:
-//   - error: nil on success, parse error on failure
+//   - error: nil on success, parse error on failure, *NameConflictWarning if
+//     the name was already registered to a different address (registration
+//     still applied)
 func (d *Decoder) RegisterContract(name string, address common.Address, abiJSON string, eventNames []string) error {
+	return d.RegisterContractWithAliases(name, address, abiJSON, eventNames, nil)
+}
+
+// RegisterContractWithAliases registers a contract ABI for decoding, storing
+// selected events under a custom name. Aliased events get the alias as
+// EventName and in EventID ("ContractName:Alias"), so handlers must be
+// registered under the alias.
+//
+// Parameters:
+//   - name (string): user-defined contract name
+//   - address (common.Address): contract address
+//   - abiJSON (string): ABI JSON string
+//   - eventNames ([]string): event names to register (empty for all)
+//   - aliases (map[string]string): ABI event name -> stored name; keys match
+//     case-insensitively (config keys are lowercased)
+//
+// Returns:
+//   - error: nil on success, parse error on failure, *NameConflictWarning if
+//     the name was already registered to a different address (registration
+//     still applied)
+func (d *Decoder) RegisterContractWithAliases(name string, address common.Address, abiJSON string, eventNames []string, aliases map[string]string) error {
 	parsed, err := abi.JSON(strings.NewReader(abiJSON))
 	if err != nil {
 		return fmt.Errorf("parsing ABI for %s: %w", name, err)
 	}
 
+	var warning error
+	if existing, ok := d.names[name]; ok && existing != address {
+		warning = &NameConflictWarning{Name: name, Existing: existing, Address: address}
+	}
+	d.names[name] = address
+
 	d.abis[address] = &parsed
+	if d.byAddr[address] == nil {
+		d.byAddr[address] = make(map[common.Hash]*EventInfo)
+	}
 
 	// Create event name set for filtering
 	eventSet := make(map[string]bool)
@@ -89,13 +186,23 @@ func (d *Decoder) RegisterContract(name string, address common.Address, abiJSON
 		eventSet[en] = true
 	}
 
+	aliasOf := make(map[string]string, len(aliases))
+	for abiName, alias := range aliases {
+		aliasOf[strings.ToLower(abiName)] = alias
+	}
+
 	// Register events
-	for eventName, event := range parsed.Events {
+	for abiName, event := range parsed.Events {
 		// Skip if event names specified and this isn't one of them
-		if len(eventNames) > 0 && !eventSet[eventName] {
+		if len(eventNames) > 0 && !eventSet[abiName] {
 			continue
 		}
 
+		eventName := abiName
+		if alias, ok := aliasOf[strings.ToLower(abiName)]; ok && alias != "" {
+			eventName = alias
+		}
+
 		info := &EventInfo{
 			ContractName: name,
 			EventName:    eventName,
@@ -104,11 +211,61 @@ func (d *Decoder) RegisterContract(name string, address common.Address, abiJSON
 			Address:      address,
 		}
 
+		d.byAddr[address][event.ID] = info
 		d.events[event.ID] = info
 		d.sigToID[event.ID] = fmt.Sprintf("%s:%s", name, eventName)
 	}
 
-	return nil
+	return warning
+}
+
+// Collisions returns the event signatures registered by more than one
+// contract, sorted by signature. Shared signatures across contracts are
+// expected (e.g. ERC20 Transfer) and decode correctly by address; this is
+// intended for diagnostics.
+//
+// Returns:
+//   - []Collision: signatures with multiple registrations
+func (d *Decoder) Collisions() []Collision {
+	bySig := make(map[common.Hash]*Collision)
+	for addr, events := range d.byAddr {
+		for sig, info := range events {
+			c, ok := bySig[sig]
+			if !ok {
+				c = &Collision{Signature: sig, EventName: info.Event.Name}
+				bySig[sig] = c
+			}
+			c.Contracts = append(c.Contracts, fmt.Sprintf("%s@%s", info.ContractName, addr.Hex()))
+		}
+	}
+
+	collisions := make([]Collision, 0)
+	for _, c := range bySig {
+		if len(c.Contracts) < 2 {
+			continue
+		}
+		sort.Strings(c.Contracts)
+		collisions = append(collisions, *c)
+	}
+
+	sort.Slice(collisions, func(i, j int) bool {
+		return bytes.Compare(collisions[i].Signature[:], collisions[j].Signature[:]) < 0
+	})
+
+	return collisions
+}
+
+// lookup resolves the registered event for a log, preferring the emitting
+// contract's own registration over the signature-only fallback.
+func (d *Decoder) lookup(log types.Log) (*EventInfo, bool) {
+	if len(log.Topics) == 0 {
+		return nil, false
+	}
+	if info, ok := d.byAddr[log.Address][log.Topics[0]]; ok {
+		return info, true
+	}
+	info, ok := d.events[log.Topics[0]]
+	return info, ok
 }
 
 // GetEventSignatures returns all registered event signatures.
@@ -145,22 +302,23 @@ func (d *Decoder) GetAddresses() []common.Address {
 //   - error: nil on success, decode error on failure or if event not registered
 func (d *Decoder) Decode(log types.Log) (*DecodedEvent, error) {
 	if len(log.Topics) == 0 {
-		return nil, fmt.Errorf("log has no topics")
+		return nil, ErrNoTopics
 	}
 
 	eventSig := log.Topics[0]
-	info, ok := d.events[eventSig]
+	info, ok := d.lookup(log)
 	if !ok {
-		return nil, fmt.Errorf("unknown event signature: %s", eventSig.Hex())
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEvent, eventSig.Hex())
 	}
 
 	// Decode non-indexed data
 	data := make(map[string]interface{})
 
 	if len(log.Data) > 0 {
-		if err := info.ABI.UnpackIntoMap(data, info.EventName, log.Data); err != nil {
-			return nil, fmt.Errorf("unpacking event data: %w", err)
+		if err := info.ABI.UnpackIntoMap(data, info.Event.Name, log.Data); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrUnpack, err)
 		}
+		convertTuples(info.Event.Inputs.NonIndexed(), data)
 	}
 
 	// Decode indexed topics
@@ -194,7 +352,7 @@ func (d *Decoder) Decode(log types.Log) (*DecodedEvent, error) {
 	return &DecodedEvent{
 		ContractName: info.ContractName,
 		EventName:    info.EventName,
-		EventID:      d.sigToID[eventSig],
+		EventID:      fmt.Sprintf("%s:%s", info.ContractName, info.EventName),
 		Log:          log,
 		Data:         data,
 	}, nil
@@ -208,10 +366,7 @@ func (d *Decoder) Decode(log types.Log) (*DecodedEvent, error) {
 // Returns:
 //   - bool: true if the event is registered
 func (d *Decoder) CanDecode(log types.Log) bool {
-	if len(log.Topics) == 0 {
-		return false
-	}
-	_, ok := d.events[log.Topics[0]]
+	_, ok := d.lookup(log)
 	return ok
 }
 
@@ -224,11 +379,134 @@ func (d *Decoder) CanDecode(log types.Log) bool {
 //   - string: event ID in format "ContractName:EventName"
 //   - bool: true if found
 func (d *Decoder) GetEventID(log types.Log) (string, bool) {
-	if len(log.Topics) == 0 {
+	info, ok := d.lookup(log)
+	if !ok {
 		return "", false
 	}
-	id, ok := d.sigToID[log.Topics[0]]
-	return id, ok
+	return fmt.Sprintf("%s:%s", info.ContractName, info.EventName), true
+}
+
+// EventInputs returns the ABI arguments of a registered event.
+//
+// Parameters:
+//   - eventID (string): event ID in format "ContractName:EventName"
+//
+// Returns:
+//   - abi.Arguments: the event's inputs
+//   - bool: true if found
+func (d *Decoder) EventInputs(eventID string) (abi.Arguments, bool) {
+	contractName, eventName, _ := strings.Cut(eventID, ":")
+	addr, ok := d.names[contractName]
+	if !ok {
+		return nil, false
+	}
+	for _, info := range d.byAddr[addr] {
+		if info.ContractName == contractName && info.EventName == eventName {
+			return info.Event.Inputs, true
+		}
+	}
+	return nil, false
+}
+
+// ContractEvents returns the stored names of the events registered for a
+// contract, sorted.
+//
+// Parameters:
+//   - name (string): user-defined contract name
+//
+// Returns:
+//   - []string: event names (nil if the contract isn't registered)
+func (d *Decoder) ContractEvents(name string) []string {
+	addr, ok := d.names[name]
+	if !ok {
+		return nil
+	}
+	var events []string
+	for _, info := range d.byAddr[addr] {
+		if info.ContractName == name {
+			events = append(events, info.EventName)
+		}
+	}
+	sort.Strings(events)
+	return events
+}
+
+// EventIDs returns the IDs ("ContractName:EventName") of every registered
+// event, i.e. the IDs decoded events are dispatched under, sorted.
+//
+// Returns:
+//   - []string: event IDs
+func (d *Decoder) EventIDs() []string {
+	seen := make(map[string]bool)
+	ids := make([]string, 0)
+	for _, events := range d.byAddr {
+		for _, info := range events {
+			id := fmt.Sprintf("%s:%s", info.ContractName, info.EventName)
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// RemoveContract unregisters all events and the ABI registered under a contract name.
+//
+// Parameters:
+//   - name (string): user-defined contract name
+//
+// Returns:
+//   - bool: true if the contract was registered
+func (d *Decoder) RemoveContract(name string) bool {
+	_, found := d.names[name]
+	delete(d.names, name)
+
+	for _, events := range d.byAddr {
+		for sig, info := range events {
+			if info.ContractName == name {
+				delete(events, sig)
+				found = true
+			}
+		}
+	}
+
+	// Drop ABIs no longer referenced by any contract
+	inUse := make(map[common.Address]bool, len(d.names))
+	for _, addr := range d.names {
+		inUse[addr] = true
+	}
+	for addr, events := range d.byAddr {
+		if len(events) > 0 {
+			inUse[addr] = true
+		}
+	}
+	for addr := range d.abis {
+		if !inUse[addr] {
+			delete(d.abis, addr)
+			delete(d.byAddr, addr)
+		}
+	}
+
+	// Repoint signature fallbacks owned by the removed contract to a
+	// remaining registration, or drop them.
+	for sig, info := range d.events {
+		if info.ContractName != name {
+			continue
+		}
+		delete(d.events, sig)
+		delete(d.sigToID, sig)
+		for _, events := range d.byAddr {
+			if other, ok := events[sig]; ok {
+				d.events[sig] = other
+				d.sigToID[sig] = fmt.Sprintf("%s:%s", other.ContractName, other.EventName)
+				break
+			}
+		}
+	}
+
+	return found
 }
 
 // Clear removes all registered contracts and events.
@@ -237,4 +515,6 @@ func (d *Decoder) Clear() {
 	d.abis = make(map[common.Address]*abi.ABI)
 	d.events = make(map[common.Hash]*EventInfo)
 	d.sigToID = make(map[common.Hash]string)
+	d.byAddr = make(map[common.Address]map[common.Hash]*EventInfo)
+	d.names = make(map[string]common.Address)
 }
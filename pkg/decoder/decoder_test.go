@@ -1,11 +1,22 @@
 package decoder
 
 import (
+	"context"
+	"encoding/json"
 	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/require"
 )
 
@@ -181,6 +192,7 @@ func TestDecode(t *testing.T) {
 		log        types.Log
 		wantErr    bool
 		wantErrMsg string
+		wantErrIs  error
 		checkEvent func(t *testing.T, event *DecodedEvent)
 	}{
 		{
@@ -213,6 +225,7 @@ func TestDecode(t *testing.T) {
 			},
 			wantErr:    true,
 			wantErrMsg: "no topics",
+			wantErrIs:  ErrNoTopics,
 		},
 		{
 			name: "unknown event signature",
@@ -225,6 +238,7 @@ func TestDecode(t *testing.T) {
 			},
 			wantErr:    true,
 			wantErrMsg: "unknown event signature",
+			wantErrIs:  ErrUnknownEvent,
 		},
 		{
 			name: "empty data field is valid",
@@ -254,6 +268,9 @@ func TestDecode(t *testing.T) {
 			if tc.wantErr {
 				require.Error(t, err)
 				require.Contains(t, err.Error(), tc.wantErrMsg)
+				if tc.wantErrIs != nil {
+					require.ErrorIs(t, err, tc.wantErrIs)
+				}
 				return
 			}
 
@@ -371,6 +388,24 @@ func TestClear(t *testing.T) {
 	require.Empty(t, d.sigToID)
 }
 
+func TestRemoveContract(t *testing.T) {
+	d := New()
+	err := d.RegisterContract("USDC", testContractAddr, erc20ABI, []string{"Transfer"})
+	require.NoError(t, err)
+
+	// Unknown contract is a no-op
+	require.False(t, d.RemoveContract("DAI"))
+	require.Len(t, d.GetEventSignatures(), 1)
+
+	require.True(t, d.RemoveContract("USDC"))
+	require.Empty(t, d.events)
+	require.Empty(t, d.abis)
+	require.Empty(t, d.sigToID)
+
+	// Removing twice reports not found
+	require.False(t, d.RemoveContract("USDC"))
+}
+
 func TestDecodeMultipleContracts(t *testing.T) {
 	d := New()
 
@@ -388,9 +423,108 @@ func TestDecodeMultipleContracts(t *testing.T) {
 	require.Len(t, addrs, 2)
 
 	// Both use same Transfer signature, but different contract names
-	// The last registration wins for same event signature
 	sigs := d.GetEventSignatures()
 	require.Len(t, sigs, 2) // Transfer + Approval (from DAI)
+
+	// Logs decode under the emitting contract's name
+	value := common.LeftPadBytes(big.NewInt(1).Bytes(), 32)
+	for addr, want := range map[common.Address]string{addr1: "USDC", addr2: "DAI"} {
+		event, err := d.Decode(types.Log{
+			Address: addr,
+			Topics:  []common.Hash{transferEventSig, common.BytesToHash(testFromAddr.Bytes()), common.BytesToHash(testToAddr.Bytes())},
+			Data:    value,
+		})
+		require.NoError(t, err)
+		require.Equal(t, want, event.ContractName)
+		require.Equal(t, want+":Transfer", event.EventID)
+	}
+
+	// Removing one contract keeps the other decodable
+	require.True(t, d.RemoveContract("DAI"))
+	id, ok := d.GetEventID(types.Log{Address: addr1, Topics: []common.Hash{transferEventSig}})
+	require.True(t, ok)
+	require.Equal(t, "USDC:Transfer", id)
+	require.Len(t, d.GetAddresses(), 1)
+}
+
+func TestRegisterContractNameConflict(t *testing.T) {
+	d := New()
+
+	addr1 := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	addr2 := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	err := d.RegisterContract("USDC", addr1, erc20ABI, []string{"Transfer"})
+	require.NoError(t, err)
+
+	// Re-registering the same address is not a conflict
+	err = d.RegisterContract("USDC", addr1, erc20ABI, []string{"Transfer"})
+	require.NoError(t, err)
+
+	// Same name, different address warns but still registers
+	err = d.RegisterContract("USDC", addr2, erc20ABI, []string{"Transfer"})
+	var conflict *NameConflictWarning
+	require.ErrorAs(t, err, &conflict)
+	require.Equal(t, "USDC", conflict.Name)
+	require.Equal(t, addr1, conflict.Existing)
+	require.Equal(t, addr2, conflict.Address)
+	require.Len(t, d.GetAddresses(), 2)
+}
+
+func TestRegisterContractWithAliases(t *testing.T) {
+	d := New()
+
+	usdc := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	weth := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	// Keys match case-insensitively, as viper lowercases map keys
+	err := d.RegisterContractWithAliases("USDC", usdc, erc20ABI, nil, map[string]string{"transfer": "USDCTransfer"})
+	require.NoError(t, err)
+	err = d.RegisterContractWithAliases("WETH", weth, erc20ABI, nil, map[string]string{"Transfer": "WETHTransfer"})
+	require.NoError(t, err)
+
+	value := common.LeftPadBytes(big.NewInt(5).Bytes(), 32)
+	for addr, wantName := range map[common.Address]string{usdc: "USDCTransfer", weth: "WETHTransfer"} {
+		event, err := d.Decode(types.Log{
+			Address: addr,
+			Topics:  []common.Hash{transferEventSig, common.BytesToHash(testFromAddr.Bytes()), common.BytesToHash(testToAddr.Bytes())},
+			Data:    value,
+		})
+		require.NoError(t, err)
+		require.Equal(t, wantName, event.EventName)
+		require.Equal(t, event.ContractName+":"+wantName, event.EventID)
+		require.Equal(t, big.NewInt(5), event.Data["value"])
+	}
+
+	// Unaliased events keep their ABI name
+	approval, ok := d.GetEventID(types.Log{Address: usdc, Topics: []common.Hash{crypto.Keccak256Hash([]byte("Approval(address,address,uint256)"))}})
+	require.True(t, ok)
+	require.Equal(t, "USDC:Approval", approval)
+
+	// Collisions report the Solidity name
+	collisions := d.Collisions()
+	require.Len(t, collisions, 2)
+	require.Equal(t, "Transfer", collisions[1].EventName)
+}
+
+func TestCollisions(t *testing.T) {
+	d := New()
+
+	addr1 := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	addr2 := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	err := d.RegisterContract("USDC", addr1, erc20ABI, []string{"Transfer"})
+	require.NoError(t, err)
+	require.Empty(t, d.Collisions())
+
+	err = d.RegisterContract("DAI", addr2, erc20ABI, nil)
+	require.NoError(t, err)
+
+	// Only Transfer is shared; Approval is registered by DAI alone
+	collisions := d.Collisions()
+	require.Len(t, collisions, 1)
+	require.Equal(t, transferEventSig, collisions[0].Signature)
+	require.Equal(t, "Transfer", collisions[0].EventName)
+	require.Equal(t, []string{"DAI@" + addr2.Hex(), "USDC@" + addr1.Hex()}, collisions[0].Contracts)
 }
 
 func TestDecodeBoolIndexedField(t *testing.T) {
@@ -431,3 +565,407 @@ func TestDecodeBoolIndexedField(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, false, event.Data["success"])
 }
+
+func TestAddressTopic(t *testing.T) {
+	addr := common.HexToAddress("0x176211869cA2b568f2A7D4EE941E073a821EE1ff")
+
+	topic := AddressTopic(addr)
+	require.Equal(t, "0x000000000000000000000000176211869ca2b568f2a7d4ee941e073a821ee1ff", topic.Hex())
+
+	// Round-trips through the decoder as an indexed Transfer argument
+	dec := New()
+	require.NoError(t, dec.RegisterContract("usdc", addr, erc20ABI, []string{"Transfer"}))
+
+	event, err := dec.Decode(types.Log{
+		Address: addr,
+		Topics: []common.Hash{
+			common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"),
+			AddressTopic(common.HexToAddress("0x1111111111111111111111111111111111111111")),
+			topic,
+		},
+		Data: common.LeftPadBytes(big.NewInt(1).Bytes(), 32),
+	})
+	require.NoError(t, err)
+	require.Equal(t, addr, event.Data["to"])
+}
+
+func TestTopicForIndexedArg(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		argType string
+		want    string
+		wantErr bool
+	}{
+		{"address", common.HexToAddress("0x176211869cA2b568f2A7D4EE941E073a821EE1ff"), "address", "0x000000000000000000000000176211869ca2b568f2a7d4ee941e073a821ee1ff", false},
+		{"address string", "0x176211869cA2b568f2A7D4EE941E073a821EE1ff", "address", "0x000000000000000000000000176211869ca2b568f2a7d4ee941e073a821ee1ff", false},
+		{"bool true", true, "bool", "0x0000000000000000000000000000000000000000000000000000000000000001", false},
+		{"bool false", false, "bool", "0x0000000000000000000000000000000000000000000000000000000000000000", false},
+		{"uint256 int", 1000, "uint256", "0x00000000000000000000000000000000000000000000000000000000000003e8", false},
+		{"uint big.Int", big.NewInt(255), "uint", "0x00000000000000000000000000000000000000000000000000000000000000ff", false},
+		{"uint8 max", uint8(255), "uint8", "0x00000000000000000000000000000000000000000000000000000000000000ff", false},
+		{"uint256 hex string", "0xff", "uint256", "0x00000000000000000000000000000000000000000000000000000000000000ff", false},
+		{"int256 negative", -1, "int256", "0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", false},
+		{"int24 negative", int32(-2), "int24", "0xfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffe", false},
+		{"bytes32 hash", common.HexToHash("0xabcd"), "bytes32", "0x000000000000000000000000000000000000000000000000000000000000abcd", false},
+		{"bytes4 right-padded", "0xa9059cbb", "bytes4", "0xa9059cbb00000000000000000000000000000000000000000000000000000000", false},
+		{"string keccak", "hello", "string", "0x1c8aff950685c2ed4bc3174f3472287b56d9517b9c948127319a09a7a36deac8", false},
+		{"bytes keccak", []byte{}, "bytes", "0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470", false},
+		{"invalid address", "0x1234", "address", "", true},
+		{"uint8 overflow", 256, "uint8", "", true},
+		{"uint negative", -1, "uint256", "", true},
+		{"int8 underflow", -129, "int8", "", true},
+		{"bytes4 too long", "0xa9059cbb00", "bytes4", "", true},
+		{"bytes4 hash too long", common.HexToHash("0xabcd"), "bytes4", "", true},
+		{"invalid hex", "0xzz", "bytes32", "", true},
+		{"wrong value type", "yes", "bool", "", true},
+		{"unsupported width", 1, "uint7", "", true},
+		{"unsupported type", 1, "tuple", "", true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := TopicForIndexedArg(tc.value, tc.argType)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got.Hex())
+		})
+	}
+}
+
+func TestContractEvents(t *testing.T) {
+	d := New()
+	addr := common.HexToAddress("0x176211869cA2b568f2A7D4EE941E073a821EE1ff")
+
+	require.NoError(t, d.RegisterContract("usdc", addr, erc20ABI, nil))
+	require.Equal(t, []string{"Approval", "Transfer"}, d.ContractEvents("usdc"))
+	require.Nil(t, d.ContractEvents("dai"))
+}
+
+func TestMergeABIs(t *testing.T) {
+	// A facet redefining Transfer with other argument names, plus its own event
+	facetABI := `[
+	  {"anonymous": false, "inputs": [
+	    {"indexed": true, "name": "src", "type": "address"},
+	    {"indexed": true, "name": "dst", "type": "address"},
+	    {"indexed": false, "name": "wad", "type": "uint256"}
+	  ], "name": "Transfer", "type": "event"},
+	  {"anonymous": false, "inputs": [
+	    {"indexed": true, "name": "account", "type": "address"}
+	  ], "name": "Paused", "type": "event"},
+	  {"inputs": [], "name": "pause", "outputs": [], "stateMutability": "nonpayable", "type": "function"}
+	]`
+
+	single, err := MergeABIs([]string{erc20ABI})
+	require.NoError(t, err)
+	require.Equal(t, erc20ABI, single)
+
+	_, err = MergeABIs(nil)
+	require.Error(t, err)
+	_, err = MergeABIs([]string{erc20ABI, "not json"})
+	require.Error(t, err)
+
+	d := New()
+	require.NoError(t, d.RegisterContractABIs("diamond", testContractAddr, []string{erc20ABI, facetABI}, nil, nil))
+	require.Equal(t, []string{"Approval", "Paused", "Transfer"}, d.ContractEvents("diamond"))
+
+	// The first ABI defining Transfer wins
+	value := common.LeftPadBytes(big.NewInt(7).Bytes(), 32)
+	decoded, err := d.Decode(types.Log{
+		Address: testContractAddr,
+		Topics: []common.Hash{
+			crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)")),
+			common.BytesToHash(testFromAddr.Bytes()),
+			common.BytesToHash(testToAddr.Bytes()),
+		},
+		Data: value,
+	})
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(7), decoded.Data["value"])
+	require.Equal(t, testFromAddr, decoded.Data["from"])
+
+	// Facet-only events decode too
+	decoded, err = d.Decode(types.Log{
+		Address: testContractAddr,
+		Topics: []common.Hash{
+			crypto.Keccak256Hash([]byte("Paused(address)")),
+			common.BytesToHash(testFromAddr.Bytes()),
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "diamond:Paused", decoded.EventID)
+}
+
+func TestDecodeRevert(t *testing.T) {
+	const tokenABI = `[
+		{"type": "error", "name": "InsufficientBalance", "inputs": [
+			{"name": "available", "type": "uint256"},
+			{"name": "required", "type": "uint256"}
+		]},
+		{"type": "event", "name": "Transfer", "anonymous": false, "inputs": [
+			{"indexed": true, "name": "from", "type": "address"},
+			{"indexed": true, "name": "to", "type": "address"},
+			{"indexed": false, "name": "value", "type": "uint256"}
+		]}
+	]`
+
+	d := New()
+	require.NoError(t, d.RegisterContract("token", testContractAddr, tokenABI, nil))
+
+	name, ok := d.ContractName(testContractAddr)
+	require.True(t, ok)
+	require.Equal(t, "token", name)
+	_, ok = d.ContractName(testFromAddr)
+	require.False(t, ok)
+
+	word := func(n int64) []byte { return common.LeftPadBytes(big.NewInt(n).Bytes(), 32) }
+	selector := func(sig string) []byte { return crypto.Keccak256([]byte(sig))[:4] }
+
+	// Error(string) encoding of "not enough"
+	reasonData := append(selector("Error(string)"), word(32)...)
+	reasonData = append(reasonData, word(10)...)
+	reasonData = append(reasonData, common.RightPadBytes([]byte("not enough"), 32)...)
+	customData := append(append(selector("InsufficientBalance(uint256,uint256)"), word(5)...), word(7)...)
+
+	tests := []struct {
+		name    string
+		address common.Address
+		data    []byte
+		want    string
+	}{
+		{"no data", testContractAddr, nil, ""},
+		{"error string", testContractAddr, reasonData, "not enough"},
+		{"panic", testContractAddr, append(selector("Panic(uint256)"), word(0x11)...), "arithmetic underflow or overflow"},
+		{"custom error", testContractAddr, customData, "InsufficientBalance(5, 7)"},
+		{"custom error of another contract", testFromAddr, customData, hexutil.Encode(customData)},
+		{"unknown selector", testContractAddr, []byte{0xde, 0xad, 0xbe, 0xef}, "0xdeadbeef"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, d.DecodeRevert(tt.address, tt.data))
+		})
+	}
+}
+
+func TestDecodeCalldata(t *testing.T) {
+	const tokenABI = `[
+		{"type": "function", "name": "transfer", "stateMutability": "nonpayable", "inputs": [
+			{"name": "to", "type": "address"},
+			{"name": "amount", "type": "uint256"}
+		], "outputs": [{"name": "", "type": "bool"}]}
+	]`
+
+	d := New()
+	require.NoError(t, d.RegisterContract("token", testContractAddr, tokenABI, nil))
+
+	selector := crypto.Keccak256([]byte("transfer(address,uint256)"))[:4]
+	input := append(append([]byte{}, selector...), common.LeftPadBytes(testToAddr.Bytes(), 32)...)
+	input = append(input, common.LeftPadBytes(big.NewInt(42).Bytes(), 32)...)
+
+	call, err := d.DecodeCalldata(testContractAddr, input)
+	require.NoError(t, err)
+	require.Equal(t, "token", call.ContractName)
+	require.Equal(t, "transfer", call.MethodName)
+	require.Equal(t, "transfer(address,uint256)", call.Signature)
+	require.Equal(t, [4]byte(selector), call.Selector)
+	require.Equal(t, testToAddr, call.Args["to"])
+	require.Equal(t, big.NewInt(42), call.Args["amount"])
+
+	tests := []struct {
+		name    string
+		address common.Address
+		input   []byte
+		wantErr error
+	}{
+		{"unregistered contract", testFromAddr, input, ErrUnknownContract},
+		{"unknown selector", testContractAddr, []byte{0xde, 0xad, 0xbe, 0xef}, ErrUnknownMethod},
+		{"short input", testContractAddr, []byte{0xa9}, ErrUnknownMethod},
+		{"truncated arguments", testContractAddr, input[:20], ErrUnpack},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := d.DecodeCalldata(tt.address, tt.input)
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestDecodeTupleEvent(t *testing.T) {
+	const orderABI = `[
+		{"type": "event", "name": "OrderPlaced", "anonymous": false, "inputs": [
+			{"indexed": true, "name": "trader", "type": "address"},
+			{"indexed": false, "name": "order", "type": "tuple", "components": [
+				{"name": "price", "type": "uint256"},
+				{"name": "maker", "type": "address"}
+			]},
+			{"indexed": false, "name": "fills", "type": "tuple[]", "components": [
+				{"name": "amount", "type": "uint256"},
+				{"name": "meta", "type": "tuple", "components": [
+					{"name": "taker", "type": "address"},
+					{"name": "final", "type": "bool"}
+				]}
+			]}
+		]}
+	]`
+
+	parsed, err := abi.JSON(strings.NewReader(orderABI))
+	require.NoError(t, err)
+	event := parsed.Events["OrderPlaced"]
+
+	type order struct {
+		Price *big.Int
+		Maker common.Address
+	}
+	type meta struct {
+		Taker common.Address
+		Final bool
+	}
+	type fill struct {
+		Amount *big.Int
+		Meta   meta
+	}
+
+	data, err := event.Inputs.NonIndexed().Pack(
+		order{Price: big.NewInt(100), Maker: testFromAddr},
+		[]fill{
+			{Amount: big.NewInt(1), Meta: meta{Taker: testToAddr, Final: false}},
+			{Amount: big.NewInt(2), Meta: meta{Taker: testToAddr, Final: true}},
+		},
+	)
+	require.NoError(t, err)
+
+	d := New()
+	require.NoError(t, d.RegisterContract("book", testContractAddr, orderABI, nil))
+
+	decoded, err := d.Decode(types.Log{
+		Address: testContractAddr,
+		Topics:  []common.Hash{event.ID, common.BytesToHash(testFromAddr.Bytes())},
+		Data:    data,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, testFromAddr, decoded.Data["trader"])
+	require.Equal(t, map[string]interface{}{
+		"price": big.NewInt(100),
+		"maker": testFromAddr,
+	}, decoded.Data["order"])
+	require.Equal(t, []interface{}{
+		map[string]interface{}{
+			"amount": big.NewInt(1),
+			"meta":   map[string]interface{}{"taker": testToAddr, "final": false},
+		},
+		map[string]interface{}{
+			"amount": big.NewInt(2),
+			"meta":   map[string]interface{}{"taker": testToAddr, "final": true},
+		},
+	}, decoded.Data["fills"])
+}
+
+func TestFetchABI(t *testing.T) {
+	explorerBackoff = time.Millisecond
+	t.Cleanup(func() { explorerBackoff = time.Second })
+
+	abiJSON := `[{"type":"event","name":"Transfer","anonymous":false,"inputs":[]}]`
+	okBody, err := json.Marshal(map[string]string{"status": "1", "message": "OK", "result": abiJSON})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		responses []string
+		status    int
+		want      string
+		wantErr   error
+		wantCalls int
+	}{
+		{
+			name:      "verified contract",
+			responses: []string{string(okBody)},
+			want:      abiJSON,
+			wantCalls: 1,
+		},
+		{
+			name:      "unverified contract",
+			responses: []string{`{"status":"0","message":"NOTOK","result":"Contract source code not verified"}`},
+			wantErr:   ErrContractNotVerified,
+			wantCalls: 1,
+		},
+		{
+			name:      "blockscout unverified contract",
+			responses: []string{`{"status":"0","message":"Contract source code not verified","result":null}`},
+			wantErr:   ErrContractNotVerified,
+			wantCalls: 1,
+		},
+		{
+			name: "rate limited then ok",
+			responses: []string{
+				`{"status":"0","message":"NOTOK","result":"Max rate limit reached"}`,
+				string(okBody),
+			},
+			want:      abiJSON,
+			wantCalls: 2,
+		},
+		{
+			name:      "rate limited over HTTP",
+			status:    http.StatusTooManyRequests,
+			wantErr:   ErrExplorerRateLimited,
+			wantCalls: explorerAttempts,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			var query url.Values
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				query = r.URL.Query()
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+					return
+				}
+				_, _ = w.Write([]byte(tt.responses[min(calls, len(tt.responses))-1]))
+			}))
+			defer srv.Close()
+
+			got, err := FetchABI(context.Background(), srv.URL+"/api?chainid=59144", "key", testContractAddr)
+			require.Equal(t, tt.wantCalls, calls)
+			require.Equal(t, "getabi", query.Get("action"))
+			require.Equal(t, testContractAddr.Hex(), query.Get("address"))
+			require.Equal(t, "key", query.Get("apikey"))
+			require.Equal(t, "59144", query.Get("chainid"))
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFetchABICached(t *testing.T) {
+	abiJSON := `[{"type":"event","name":"Transfer","anonymous":false,"inputs":[]}]`
+	body, err := json.Marshal(map[string]string{"status": "1", "message": "OK", "result": abiJSON})
+	require.NoError(t, err)
+
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	cacheDir := filepath.Join(t.TempDir(), "abis")
+	for range 2 {
+		got, err := FetchABICached(context.Background(), cacheDir, srv.URL, "", testContractAddr)
+		require.NoError(t, err)
+		require.Equal(t, abiJSON, got)
+	}
+	require.Equal(t, 1, calls)
+	require.FileExists(t, filepath.Join(cacheDir, strings.ToLower(testContractAddr.Hex())+".json"))
+}
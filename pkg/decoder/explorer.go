@@ -0,0 +1,198 @@
+package decoder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Explorer errors, distinguishable via errors.Is.
+var (
+	// ErrContractNotVerified is returned when the explorer has no verified
+	// source, hence no ABI, for the address.
+	ErrContractNotVerified = errors.New("contract source code not verified")
+
+	// ErrExplorerRateLimited is returned when the explorer still rejects
+	// the request for its rate limit after retrying.
+	ErrExplorerRateLimited = errors.New("explorer rate limit reached")
+)
+
+// explorerAttempts is the number of requests made while rate limited.
+const explorerAttempts = 3
+
+// explorerBackoff is the delay before the first retry, doubled for each
+// further one. A variable so tests can shorten it.
+var explorerBackoff = time.Second
+
+// explorerClient is the HTTP client used for explorer requests.
+var explorerClient = &http.Client{Timeout: 30 * time.Second}
+
+// explorerResponse is the envelope of Etherscan-compatible API responses.
+// Result holds the ABI JSON on success and a reason on failure (null for
+// some Blockscout errors).
+type explorerResponse struct {
+	Status  string  `json:"status"`
+	Message string  `json:"message"`
+	Result  *string `json:"result"`
+}
+
+// FetchABI fetches a verified contract's ABI from an Etherscan-compatible
+// API (module=contract&action=getabi), such as Lineascan, Etherscan V2 or
+// Blockscout. Query parameters already in apiURL (e.g. Etherscan V2's
+// chainid) are kept. Rate-limited requests are retried with backoff.
+//
+// Parameters:
+//   - ctx (context.Context): context for cancellation
+//   - apiURL (string): API endpoint, e.g. "https://api.lineascan.build/api"
+//   - apiKey (string): API key ("" = none, for explorers that allow it)
+//   - addr (common.Address): contract address
+//
+// Returns:
+//   - string: the ABI JSON, ready for RegisterContract
+//   - error: nil on success, ErrContractNotVerified, ErrExplorerRateLimited or request error on failure
+func FetchABI(ctx context.Context, apiURL, apiKey string, addr common.Address) (string, error) {
+	u, err := url.Parse(apiURL)
+	if err != nil {
+		return "", fmt.Errorf("parsing explorer URL: %w", err)
+	}
+	query := u.Query()
+	query.Set("module", "contract")
+	query.Set("action", "getabi")
+	query.Set("address", addr.Hex())
+	if apiKey != "" {
+		query.Set("apikey", apiKey)
+	}
+	u.RawQuery = query.Encode()
+
+	delay := explorerBackoff
+	for attempt := 1; ; attempt++ {
+		abiJSON, err := fetchABIOnce(ctx, u.String())
+		if err == nil {
+			return abiJSON, nil
+		}
+		if !errors.Is(err, ErrExplorerRateLimited) || attempt == explorerAttempts {
+			return "", fmt.Errorf("fetching ABI for %s: %w", addr.Hex(), err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("fetching ABI for %s: %w", addr.Hex(), ctx.Err())
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// fetchABIOnce makes a single getabi request.
+//
+// Parameters:
+//   - ctx (context.Context): context for cancellation
+//   - requestURL (string): full request URL, including the API key
+//
+// Returns:
+//   - string: the ABI JSON
+//   - error: nil on success, classified explorer error on failure
+func fetchABIOnce(ctx context.Context, requestURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := explorerClient.Do(req)
+	if err != nil {
+		// The URL carries the API key; keep it out of the error
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return "", fmt.Errorf("requesting explorer: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return "", ErrExplorerRateLimited
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("explorer returned HTTP %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 16<<20))
+	if err != nil {
+		return "", fmt.Errorf("reading explorer response: %w", err)
+	}
+
+	var parsed explorerResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return "", fmt.Errorf("decoding explorer response: %w", err)
+	}
+
+	result := ""
+	if parsed.Result != nil {
+		result = *parsed.Result
+	}
+	if parsed.Status != "1" {
+		reason := strings.ToLower(parsed.Message + " " + result)
+		switch {
+		case strings.Contains(reason, "rate limit"):
+			return "", ErrExplorerRateLimited
+		case strings.Contains(reason, "not verified"):
+			return "", ErrContractNotVerified
+		}
+		return "", fmt.Errorf("explorer error: %s: %s", parsed.Message, result)
+	}
+
+	if _, err := abi.JSON(strings.NewReader(result)); err != nil {
+		return "", fmt.Errorf("parsing fetched ABI: %w", err)
+	}
+	return result, nil
+}
+
+// FetchABICached returns a contract's ABI from cacheDir, fetching it with
+// FetchABI and caching it as <address>.json on a miss. Verified ABIs don't
+// change, so cached files are never refreshed; delete one to refetch. An
+// empty cacheDir disables caching.
+//
+// Parameters:
+//   - ctx (context.Context): context for cancellation
+//   - cacheDir (string): directory holding cached ABIs ("" = no cache)
+//   - apiURL (string): API endpoint
+//   - apiKey (string): API key ("" = none)
+//   - addr (common.Address): contract address
+//
+// Returns:
+//   - string: the ABI JSON
+//   - error: nil on success, fetch or cache write error on failure
+func FetchABICached(ctx context.Context, cacheDir, apiURL, apiKey string, addr common.Address) (string, error) {
+	if cacheDir == "" {
+		return FetchABI(ctx, apiURL, apiKey, addr)
+	}
+
+	path := filepath.Join(cacheDir, strings.ToLower(addr.Hex())+".json")
+	if cached, err := os.ReadFile(path); err == nil { //nolint:gosec // G304: path built from an address
+		return string(cached), nil
+	}
+
+	abiJSON, err := FetchABI(ctx, apiURL, apiKey, addr)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(cacheDir, 0o750); err != nil {
+		return "", fmt.Errorf("creating ABI cache: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(abiJSON), 0o600); err != nil {
+		return "", fmt.Errorf("caching ABI: %w", err)
+	}
+	return abiJSON, nil
+}
@@ -0,0 +1,93 @@
+package decoder
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// MergeABIs combines several ABI JSON documents into one, e.g. the facets
+// of an EIP-2535 diamond or a proxy plus its implementation. Events whose
+// signature is already defined by an earlier document are dropped, so the
+// first registration wins; other entries are kept as is. A single document
+// is returned unchanged.
+//
+// Parameters:
+//   - abiJSONs ([]string): ABI JSON documents, in order of precedence
+//
+// Returns:
+//   - string: merged ABI JSON
+//   - error: nil on success, parse error on failure
+func MergeABIs(abiJSONs []string) (string, error) {
+	if len(abiJSONs) == 0 {
+		return "", fmt.Errorf("merging ABIs: no ABI given")
+	}
+	if len(abiJSONs) == 1 {
+		return abiJSONs[0], nil
+	}
+
+	seen := make(map[common.Hash]bool)
+	var merged []json.RawMessage
+	for i, doc := range abiJSONs {
+		var entries []json.RawMessage
+		if err := json.Unmarshal([]byte(doc), &entries); err != nil {
+			return "", fmt.Errorf("parsing ABI %d: %w", i+1, err)
+		}
+
+		for _, entry := range entries {
+			var head struct {
+				Type string `json:"type"`
+			}
+			if err := json.Unmarshal(entry, &head); err != nil {
+				return "", fmt.Errorf("parsing ABI %d: %w", i+1, err)
+			}
+			if head.Type == "event" {
+				// Parse the event alone to get its signature
+				parsed, err := abi.JSON(strings.NewReader("[" + string(entry) + "]"))
+				if err != nil {
+					return "", fmt.Errorf("parsing ABI %d: %w", i+1, err)
+				}
+				dup := false
+				for _, event := range parsed.Events {
+					dup = seen[event.ID]
+					seen[event.ID] = true
+				}
+				if dup {
+					continue
+				}
+			}
+			merged = append(merged, entry)
+		}
+	}
+
+	out, err := json.Marshal(merged)
+	if err != nil {
+		return "", fmt.Errorf("encoding merged ABI: %w", err)
+	}
+	return string(out), nil
+}
+
+// RegisterContractABIs registers a contract whose events are spread over
+// several ABIs, merged with MergeABIs, like RegisterContractWithAliases.
+//
+// Parameters:
+//   - name (string): user-defined contract name
+//   - address (common.Address): contract address
+//   - abiJSONs ([]string): ABI JSON documents, in order of precedence
+//   - eventNames ([]string): event names to register (empty for all)
+//   - aliases (map[string]string): ABI event name -> stored name
+//
+// Returns:
+//   - error: nil on success, parse error on failure, *NameConflictWarning if
+//     the name was already registered to a different address (registration
+//     still applied)
+func (d *Decoder) RegisterContractABIs(name string, address common.Address, abiJSONs []string, eventNames []string, aliases map[string]string) error {
+	merged, err := MergeABIs(abiJSONs)
+	if err != nil {
+		return fmt.Errorf("merging ABIs for %s: %w", name, err)
+	}
+	return d.RegisterContractWithAliases(name, address, merged, eventNames, aliases)
+}
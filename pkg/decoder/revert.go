@@ -0,0 +1,65 @@
+package decoder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ContractName returns the name a contract address is registered under.
+//
+// Parameters:
+//   - address (common.Address): contract address
+//
+// Returns:
+//   - string: the contract name
+//   - bool: false if the address isn't registered
+func (d *Decoder) ContractName(address common.Address) (string, bool) {
+	for name, addr := range d.names {
+		if addr == address {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// DecodeRevert decodes the revert data of a failed call to a contract:
+// Error(string) reasons, Panic(uint256) codes, then custom errors of the
+// contract's ABI, rendered as Name(arg, ...). Unknown data is returned as hex.
+//
+// Parameters:
+//   - address (common.Address): called contract
+//   - data ([]byte): revert data
+//
+// Returns:
+//   - string: the revert reason ("" for no data)
+func (d *Decoder) DecodeRevert(address common.Address, data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+	if reason, err := abi.UnpackRevert(data); err == nil {
+		return reason
+	}
+
+	if parsed, ok := d.abis[address]; ok && len(data) >= 4 {
+		if abiErr, err := parsed.ErrorByID([4]byte(data[:4])); err == nil {
+			if values, err := abiErr.Unpack(data); err == nil {
+				return formatCustomError(abiErr.Name, values)
+			}
+		}
+	}
+	return hexutil.Encode(data)
+}
+
+// formatCustomError renders a decoded custom error as Name(arg, ...).
+func formatCustomError(name string, values any) string {
+	args, _ := values.([]any)
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		parts[i] = fmt.Sprint(arg)
+	}
+	return name + "(" + strings.Join(parts, ", ") + ")"
+}
@@ -0,0 +1,237 @@
+package decoder
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// tt256 is 2^256, used to encode negative integers as two's complement.
+var tt256 = new(big.Int).Lsh(big.NewInt(1), 256)
+
+// AddressTopic encodes an address as an indexed-argument topic
+// (left-padded to 32 bytes).
+//
+// Parameters:
+//   - addr (common.Address): the address
+//
+// Returns:
+//   - common.Hash: the topic value
+func AddressTopic(addr common.Address) common.Hash {
+	return common.BytesToHash(addr.Bytes())
+}
+
+// TopicForIndexedArg encodes a value as the topic an indexed event argument
+// of the given ABI type produces, for use in eth_getLogs topic filters.
+//
+// Supported types and values:
+//   - address: common.Address, hex string
+//   - bool: bool
+//   - uintN/intN: *big.Int, Go integer types, decimal or 0x-prefixed string
+//   - bytesN (N <= 32): common.Hash, []byte, hex string (right-padded)
+//   - string, bytes: string, []byte (keccak256 of the value, as the EVM stores them)
+//
+// Parameters:
+//   - value (any): argument value
+//   - argType (string): Solidity ABI type (e.g. "address", "uint256", "bytes32")
+//
+// Returns:
+//   - common.Hash: the topic value
+//   - error: nil on success, error if the value does not fit the type
+func TopicForIndexedArg(value any, argType string) (common.Hash, error) {
+	switch {
+	case argType == "address":
+		return addressTopicValue(value)
+	case argType == "bool":
+		b, ok := value.(bool)
+		if !ok {
+			return common.Hash{}, fmt.Errorf("bool topic: unsupported value type %T", value)
+		}
+		if b {
+			return common.BigToHash(big.NewInt(1)), nil
+		}
+		return common.Hash{}, nil
+	case argType == "string":
+		s, ok := value.(string)
+		if !ok {
+			return common.Hash{}, fmt.Errorf("string topic: unsupported value type %T", value)
+		}
+		return crypto.Keccak256Hash([]byte(s)), nil
+	case argType == "bytes":
+		b, err := bytesValue(value)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("bytes topic: %w", err)
+		}
+		return crypto.Keccak256Hash(b), nil
+	case strings.HasPrefix(argType, "bytes"):
+		return fixedBytesTopicValue(value, argType)
+	case strings.HasPrefix(argType, "uint"):
+		return intTopicValue(value, argType, false)
+	case strings.HasPrefix(argType, "int"):
+		return intTopicValue(value, argType, true)
+	default:
+		return common.Hash{}, fmt.Errorf("unsupported topic type %q", argType)
+	}
+}
+
+// addressTopicValue encodes an address value.
+func addressTopicValue(value any) (common.Hash, error) {
+	switch v := value.(type) {
+	case common.Address:
+		return AddressTopic(v), nil
+	case string:
+		if !common.IsHexAddress(v) {
+			return common.Hash{}, fmt.Errorf("address topic: invalid address %q", v)
+		}
+		return AddressTopic(common.HexToAddress(v)), nil
+	default:
+		return common.Hash{}, fmt.Errorf("address topic: unsupported value type %T", value)
+	}
+}
+
+// fixedBytesTopicValue encodes a bytesN value, right-padded to 32 bytes.
+func fixedBytesTopicValue(value any, argType string) (common.Hash, error) {
+	size, err := strconv.Atoi(strings.TrimPrefix(argType, "bytes"))
+	if err != nil || size < 1 || size > 32 {
+		return common.Hash{}, fmt.Errorf("unsupported topic type %q", argType)
+	}
+
+	var b []byte
+	if h, ok := value.(common.Hash); ok {
+		b = h.Bytes()
+		// A Hash is always 32 bytes; only the leading size bytes may be set
+		for _, c := range b[size:] {
+			if c != 0 {
+				return common.Hash{}, fmt.Errorf("%s topic: value exceeds %d bytes", argType, size)
+			}
+		}
+		b = b[:size]
+	} else {
+		b, err = bytesValue(value)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("%s topic: %w", argType, err)
+		}
+	}
+	if len(b) > size {
+		return common.Hash{}, fmt.Errorf("%s topic: value is %d bytes, max %d", argType, len(b), size)
+	}
+
+	var topic common.Hash
+	copy(topic[:], b)
+	return topic, nil
+}
+
+// bytesValue extracts raw bytes from a []byte or hex string.
+func bytesValue(value any) ([]byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		if !strings.HasPrefix(v, "0x") && !strings.HasPrefix(v, "0X") {
+			return nil, fmt.Errorf("hex string %q must be 0x-prefixed", v)
+		}
+		b, err := hexBytes(v[2:])
+		if err != nil {
+			return nil, err
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", value)
+	}
+}
+
+// hexBytes decodes a hex string without prefix, rejecting invalid input.
+func hexBytes(s string) ([]byte, error) {
+	if len(s)%2 != 0 {
+		return nil, fmt.Errorf("hex string has odd length")
+	}
+	b := common.FromHex(s)
+	if len(b) != len(s)/2 {
+		return nil, fmt.Errorf("invalid hex string %q", s)
+	}
+	return b, nil
+}
+
+// intTopicValue encodes a uintN/intN value as a 32-byte word
+// (two's complement for negative signed values).
+func intTopicValue(value any, argType string, signed bool) (common.Hash, error) {
+	bitsStr := strings.TrimPrefix(strings.TrimPrefix(argType, "u"), "int")
+	bits := 256
+	if bitsStr != "" {
+		var err error
+		bits, err = strconv.Atoi(bitsStr)
+		if err != nil || bits < 8 || bits > 256 || bits%8 != 0 {
+			return common.Hash{}, fmt.Errorf("unsupported topic type %q", argType)
+		}
+	}
+
+	n, err := bigIntValue(value)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("%s topic: %w", argType, err)
+	}
+
+	// Range check against the declared width
+	var lo, hi *big.Int
+	if signed {
+		hi = new(big.Int).Lsh(big.NewInt(1), uint(bits-1))
+		lo = new(big.Int).Neg(hi)
+		hi.Sub(hi, big.NewInt(1))
+	} else {
+		lo = new(big.Int)
+		hi = new(big.Int).Lsh(big.NewInt(1), uint(bits))
+		hi.Sub(hi, big.NewInt(1))
+	}
+	if n.Cmp(lo) < 0 || n.Cmp(hi) > 0 {
+		return common.Hash{}, fmt.Errorf("%s topic: value %s out of range", argType, n)
+	}
+
+	if n.Sign() < 0 {
+		n = new(big.Int).Add(n, tt256)
+	}
+	return common.BigToHash(n), nil
+}
+
+// bigIntValue converts supported integer representations to *big.Int.
+func bigIntValue(value any) (*big.Int, error) {
+	switch v := value.(type) {
+	case *big.Int:
+		if v == nil {
+			return nil, fmt.Errorf("nil *big.Int")
+		}
+		return new(big.Int).Set(v), nil
+	case big.Int:
+		return new(big.Int).Set(&v), nil
+	case int:
+		return big.NewInt(int64(v)), nil
+	case int8:
+		return big.NewInt(int64(v)), nil
+	case int16:
+		return big.NewInt(int64(v)), nil
+	case int32:
+		return big.NewInt(int64(v)), nil
+	case int64:
+		return big.NewInt(v), nil
+	case uint:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint8:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint16:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint32:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint64:
+		return new(big.Int).SetUint64(v), nil
+	case string:
+		n, ok := new(big.Int).SetString(v, 0)
+		if !ok {
+			return nil, fmt.Errorf("invalid integer %q", v)
+		}
+		return n, nil
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", value)
+	}
+}
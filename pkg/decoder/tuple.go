@@ -0,0 +1,82 @@
+package decoder
+
+import (
+	"reflect"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// tupleValues converts tuple values, which go-ethereum unpacks into
+// anonymous structs, into map[string]interface{} keyed by the ABI
+// component names. Nested tuples and arrays of tuples are converted
+// recursively; other values are returned unchanged.
+//
+// Parameters:
+//   - t (abi.Type): ABI type of the value
+//   - v (interface{}): unpacked value
+//
+// Returns:
+//   - interface{}: the value with tuples as maps
+func tupleValues(t abi.Type, v interface{}) interface{} {
+	switch t.T {
+	case abi.TupleTy:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Pointer {
+			rv = rv.Elem()
+		}
+		if rv.Kind() != reflect.Struct || rv.NumField() != len(t.TupleElems) {
+			return v
+		}
+
+		fields := make(map[string]interface{}, len(t.TupleElems))
+		for i, elem := range t.TupleElems {
+			name := t.TupleRawNames[i]
+			if name == "" {
+				name = strconv.Itoa(i)
+			}
+			fields[name] = tupleValues(*elem, rv.Field(i).Interface())
+		}
+		return fields
+
+	case abi.SliceTy, abi.ArrayTy:
+		if !hasTuple(t) {
+			return v
+		}
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return v
+		}
+
+		items := make([]interface{}, rv.Len())
+		for i := range items {
+			items[i] = tupleValues(*t.Elem, rv.Index(i).Interface())
+		}
+		return items
+	}
+	return v
+}
+
+// hasTuple reports whether a type is or contains a tuple.
+func hasTuple(t abi.Type) bool {
+	switch t.T {
+	case abi.TupleTy:
+		return true
+	case abi.SliceTy, abi.ArrayTy:
+		return t.Elem != nil && hasTuple(*t.Elem)
+	}
+	return false
+}
+
+// convertTuples replaces the tuple arguments of unpacked data with maps.
+//
+// Parameters:
+//   - args (abi.Arguments): arguments the data was unpacked from
+//   - data (map[string]interface{}): unpacked values by name, updated in place
+func convertTuples(args abi.Arguments, data map[string]interface{}) {
+	for _, arg := range args {
+		if v, ok := data[arg.Name]; ok && hasTuple(arg.Type) {
+			data[arg.Name] = tupleValues(arg.Type, v)
+		}
+	}
+}
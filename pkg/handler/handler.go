@@ -2,14 +2,16 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
-	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
 
 	"github.com/0xredeth/Rafale/pkg/decoder"
@@ -56,6 +58,61 @@ type Context struct {
 
 	// Event is the decoded event data.
 	Event *decoder.DecodedEvent
+
+	// ChecksumAddresses stores addresses EIP-55 checksummed instead of
+	// lowercased (sync.store_address_case).
+	ChecksumAddresses bool
+
+	// Contracts registers contracts discovered while indexing. Nil when
+	// the caller does not support runtime registration.
+	Contracts ContractRegistry
+}
+
+// ContractRegistry registers contracts at runtime, e.g. pairs deployed by
+// a factory. The engine implements it.
+type ContractRegistry interface {
+	// AddContractFrom registers a contract and indexes it from fromBlock.
+	AddContractFrom(name, address, abiPath string, events []string, fromBlock uint64) error
+}
+
+// ErrNoContractRegistry is returned by Context.AddContract when the
+// context has no contract registry.
+var ErrNoContractRegistry = errors.New("runtime contract registration not available")
+
+// FormatAddress formats an address for storage in a DB column. Addresses
+// are lowercased by default so indexes serve lowercased lookups.
+//
+// Parameters:
+//   - addr (common.Address): the address
+//
+// Returns:
+//   - string: 0x-prefixed address in the configured case
+func (c *Context) FormatAddress(addr common.Address) string {
+	if c.ChecksumAddresses {
+		return addr.Hex()
+	}
+	return strings.ToLower(addr.Hex())
+}
+
+// AddContract registers a contract discovered by this event, typically a
+// pair or pool announced by a factory's creation event. It is indexed from
+// the current block, so its logs in the rest of the current batch are
+// backfilled by the next one. Adding the same name and address again is a
+// no-op.
+//
+// Parameters:
+//   - name (string): contract name, unique per address (e.g. "Pair_0xabc...")
+//   - address (string): contract address
+//   - abiPath (string): path to the ABI JSON file
+//   - events ([]string): event names to index
+//
+// Returns:
+//   - error: nil on success, ErrNoContractRegistry without a registry, registration error on failure
+func (c *Context) AddContract(name, address, abiPath string, events []string) error {
+	if c.Contracts == nil {
+		return ErrNoContractRegistry
+	}
+	return c.Contracts.AddContractFrom(name, address, abiPath, events, c.Block.Number)
 }
 
 // BlockInfo contains block metadata.
@@ -73,14 +130,19 @@ type BlockInfo struct {
 	ParentHash string
 }
 
+// Wildcard registers a handler for every event ("*"), or, after a contract
+// name ("USDC:*"), for every event of that contract.
+const Wildcard = "*"
+
 // Func is the signature for event handlers.
 // The event parameter contains decoded event data.
 type Func func(ctx *Context) error
 
 // Registry manages event handlers.
 type Registry struct {
-	mu       sync.RWMutex
-	handlers map[string]Func // eventID -> handler
+	mu         sync.RWMutex
+	handlers   map[string]Func // eventID -> handler
+	middleware []Middleware    // applied around every handler, outermost first
 }
 
 // globalRegistry is the default handler registry.
@@ -97,7 +159,8 @@ func NewRegistry() *Registry {
 }
 
 // Register adds a handler for an event to the global registry.
-// The eventID should be in format "ContractName:EventName".
+// The eventID should be in format "ContractName:EventName", or a wildcard
+// (see Registry.Register).
 //
 // Parameters:
 //   - eventID (string): event identifier (e.g., "USDC:Transfer")
@@ -106,10 +169,13 @@ func Register(eventID string, handler Func) {
 	globalRegistry.Register(eventID, handler)
 }
 
-// Register adds a handler for an event.
+// Register adds a handler for an event. The eventID may also be a
+// wildcard: "ContractName:*" handles every event of a contract and "*"
+// every event. Handle runs the most specific match only: an exact event ID
+// first, then the contract wildcard, then "*".
 //
 // Parameters:
-//   - eventID (string): event identifier (e.g., "USDC:Transfer")
+//   - eventID (string): event identifier (e.g., "USDC:Transfer", "USDC:*" or "*")
 //   - handler (Func): the handler function
 func (r *Registry) Register(eventID string, handler Func) {
 	r.mu.Lock()
@@ -123,6 +189,18 @@ func (r *Registry) Register(eventID string, handler Func) {
 	log.Debug().Str("eventID", eventID).Msg("registered handler")
 }
 
+// Unregister removes the handler registered under exactly eventID.
+//
+// Parameters:
+//   - eventID (string): event identifier
+func (r *Registry) Unregister(eventID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.handlers, eventID)
+	log.Debug().Str("eventID", eventID).Msg("unregistered handler")
+}
+
 // Get retrieves a handler for an event from the global registry.
 //
 // Parameters:
@@ -135,7 +213,8 @@ func Get(eventID string) (Func, bool) {
 	return globalRegistry.Get(eventID)
 }
 
-// Get retrieves a handler for an event.
+// Get retrieves the handler registered under exactly eventID, without
+// wildcard fallback (see Match).
 //
 // Parameters:
 //   - eventID (string): event identifier
@@ -151,7 +230,9 @@ func (r *Registry) Get(eventID string) (Func, bool) {
 	return handler, ok
 }
 
-// Handle executes the handler for a decoded event.
+// Handle executes the handler for a decoded event, wrapped in the
+// registry's middleware (see Use). Middleware doesn't run for nil events
+// or events without a handler.
 //
 // Parameters:
 //   - ctx (*Context): handler context
@@ -163,7 +244,7 @@ func (r *Registry) Handle(ctx *Context) error {
 		return fmt.Errorf("event is nil")
 	}
 
-	handler, ok := r.Get(ctx.Event.EventID)
+	handler, ok := r.resolve(ctx.Event.EventID)
 	if !ok {
 		// No handler registered - skip silently
 		log.Debug().
@@ -174,7 +255,7 @@ func (r *Registry) Handle(ctx *Context) error {
 
 	start := time.Now()
 
-	err := handler(ctx)
+	err := r.wrap(handler)(ctx)
 
 	duration := time.Since(start)
 	handlerDuration.WithLabelValues(ctx.Event.ContractName, ctx.Event.EventName).Observe(duration.Seconds())
@@ -195,7 +276,7 @@ func (r *Registry) Handle(ctx *Context) error {
 	return nil
 }
 
-// HasHandler checks if a handler is registered for an event.
+// HasHandler checks if a handler, exact or wildcard, handles an event.
 //
 // Parameters:
 //   - eventID (string): event identifier
@@ -203,14 +284,61 @@ func (r *Registry) Handle(ctx *Context) error {
 // Returns:
 //   - bool: true if handler exists
 func (r *Registry) HasHandler(eventID string) bool {
+	_, ok := r.Match(eventID)
+	return ok
+}
+
+// Match returns the ID the handler of an event is registered under: the
+// event ID itself, its contract wildcard or "*", in that order.
+//
+// Parameters:
+//   - eventID (string): event identifier
+//
+// Returns:
+//   - string: matching handler ID
+//   - bool: true if a handler matches
+func (r *Registry) Match(eventID string) (string, bool) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	_, ok := r.handlers[eventID]
-	return ok
+	for _, id := range handlerCandidates(eventID) {
+		if _, ok := r.handlers[id]; ok {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// resolve returns the handler matching an event (see Match).
+func (r *Registry) resolve(eventID string) (Func, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, id := range handlerCandidates(eventID) {
+		if handler, ok := r.handlers[id]; ok {
+			return handler, true
+		}
+	}
+	return nil, false
+}
+
+// handlerCandidates returns the handler IDs that may handle an event, most
+// specific first.
+//
+// Parameters:
+//   - eventID (string): event identifier ("ContractName:EventName")
+//
+// Returns:
+//   - []string: the event ID, its contract wildcard and "*"
+func handlerCandidates(eventID string) []string {
+	contract, _, ok := strings.Cut(eventID, ":")
+	if !ok {
+		return []string{eventID, Wildcard}
+	}
+	return []string{eventID, contract + ":" + Wildcard, Wildcard}
 }
 
-// ListHandlers returns all registered event IDs.
+// ListHandlers returns all registered event IDs, wildcards included.
 //
 // Returns:
 //   - []string: list of registered event IDs
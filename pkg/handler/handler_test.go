@@ -1,16 +1,25 @@
 package handler
 
 import (
+	"bytes"
 	"errors"
+	"math/big"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rs/zerolog"
+	zlog "github.com/rs/zerolog/log"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 
+	"github.com/0xredeth/Rafale/pkg/config"
 	"github.com/0xredeth/Rafale/pkg/decoder"
 )
 
@@ -64,6 +73,21 @@ func TestRegisterOverwrite(t *testing.T) {
 	require.Equal(t, 2, callCount)
 }
 
+func TestSetLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf).Level(zerolog.WarnLevel)
+	SetLogger(&logger)
+	defer SetLogger(&zlog.Logger)
+
+	r := NewRegistry()
+	r.Register("USDC:Transfer", func(ctx *Context) error { return nil })
+	r.Register("USDC:Transfer", func(ctx *Context) error { return nil })
+
+	out := buf.String()
+	require.Contains(t, out, "overwriting existing handler")
+	require.NotContains(t, out, "registered handler", "debug entries are filtered by the injected logger")
+}
+
 func TestGet(t *testing.T) {
 	r := NewRegistry()
 	handler := func(ctx *Context) error { return nil }
@@ -187,6 +211,12 @@ func TestHandle(t *testing.T) {
 	}
 }
 
+func TestContextAddContractWithoutRegistry(t *testing.T) {
+	ctx := &Context{Block: BlockInfo{Number: 100}}
+	err := ctx.AddContract("pair", "0x1111111111111111111111111111111111111111", "pair.json", []string{"Swap"})
+	require.ErrorIs(t, err, ErrNoContractRegistry)
+}
+
 func TestHasHandler(t *testing.T) {
 	r := NewRegistry()
 	r.Register("USDC:Transfer", func(ctx *Context) error { return nil })
@@ -305,3 +335,205 @@ func TestBlockInfoFields(t *testing.T) {
 	require.Equal(t, now, info.Time)
 	require.Equal(t, "0xparent", info.ParentHash)
 }
+
+func TestContextFormatAddress(t *testing.T) {
+	addr := common.HexToAddress("0x176211869cA2b568f2A7D4EE941E073a821EE1ff")
+
+	// Lowercase by default
+	ctx := &Context{}
+	require.Equal(t, "0x176211869ca2b568f2a7d4ee941e073a821ee1ff", ctx.FormatAddress(addr))
+
+	ctx.ChecksumAddresses = true
+	require.Equal(t, "0x176211869cA2b568f2A7D4EE941E073a821EE1ff", ctx.FormatAddress(addr))
+}
+
+// transferInputs builds the ABI inputs of Transfer(address indexed from, address indexed to, uint256 value).
+func transferInputs(t *testing.T) abi.Arguments {
+	addrTy, err := abi.NewType("address", "", nil)
+	require.NoError(t, err)
+	uintTy, err := abi.NewType("uint256", "", nil)
+	require.NoError(t, err)
+	return abi.Arguments{
+		{Name: "from", Type: addrTy, Indexed: true},
+		{Name: "to", Type: addrTy, Indexed: true},
+		{Name: "value", Type: uintTy},
+	}
+}
+
+func TestChain(t *testing.T) {
+	var calls []string
+	first := func(*Context) error { calls = append(calls, "first"); return nil }
+	failing := func(*Context) error { calls = append(calls, "failing"); return errors.New("boom") }
+	last := func(*Context) error { calls = append(calls, "last"); return nil }
+
+	err := Chain(first, failing, last)(&Context{})
+	require.ErrorContains(t, err, "boom")
+	require.Equal(t, []string{"first", "failing"}, calls)
+}
+
+func TestRuleTableDDL(t *testing.T) {
+	rule := config.RuleConfig{
+		Name:    "big_transfers",
+		Table:   "big_transfers",
+		Columns: map[string]string{"sender": "from", "amount": "value"},
+	}
+
+	stmts, err := RuleTableDDL(rule, transferInputs(t))
+	require.NoError(t, err)
+	require.Len(t, stmts, 3)
+	require.Contains(t, stmts[0], `CREATE TABLE IF NOT EXISTS "big_transfers"`)
+	require.Contains(t, stmts[0], `"amount" NUMERIC(78), "sender" VARCHAR(42)`)
+	require.Contains(t, stmts[1], "(tx_hash, log_index)")
+
+	rule.Columns["memo"] = "memo"
+	_, err = RuleTableDDL(rule, transferInputs(t))
+	require.ErrorContains(t, err, `event has no field "memo"`)
+}
+
+func TestNewRuleHandler(t *testing.T) {
+	from := common.HexToAddress("0x176211869cA2b568f2A7D4EE941E073a821EE1ff")
+	rule := config.RuleConfig{
+		Name:      "whale_transfers",
+		Columns:   map[string]string{"sender": "from", "amount": "value"},
+		Broadcast: true,
+		Where: []config.PredicateConfig{
+			{Field: "value", Op: config.OpGte, Value: "1000"},
+			{Field: "from", Op: config.OpEq, Value: from.Hex()},
+		},
+	}
+
+	var got []map[string]any
+	h, err := NewRuleHandler(rule, transferInputs(t), func(_ *Context, name string, data map[string]any) {
+		require.Equal(t, "whale_transfers", name)
+		got = append(got, data)
+	})
+	require.NoError(t, err)
+
+	for _, value := range []int64{999, 1000, 5000} {
+		ctx := &Context{Event: &decoder.DecodedEvent{Data: map[string]any{"from": from, "value": big.NewInt(value)}}}
+		require.NoError(t, h(ctx))
+	}
+
+	require.Equal(t, []map[string]any{
+		{"sender": "0x176211869ca2b568f2a7d4ee941e073a821ee1ff", "amount": "1000"},
+		{"sender": "0x176211869ca2b568f2a7d4ee941e073a821ee1ff", "amount": "5000"},
+	}, got)
+}
+
+func TestNewRuleHandlerInvalidPredicates(t *testing.T) {
+	tests := []struct {
+		name      string
+		predicate config.PredicateConfig
+		wantErr   string
+	}{
+		{"unknown field", config.PredicateConfig{Field: "memo", Op: config.OpEq, Value: "x"}, `no field "memo"`},
+		{"non-integer value", config.PredicateConfig{Field: "value", Op: config.OpGt, Value: "lots"}, "not an integer"},
+		{"ordered address", config.PredicateConfig{Field: "from", Op: config.OpGt, Value: "0x1111111111111111111111111111111111111111"}, "want eq/ne"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rule := config.RuleConfig{Name: "r", Broadcast: true, Where: []config.PredicateConfig{tc.predicate}}
+			_, err := NewRuleHandler(rule, transferInputs(t), nil)
+			require.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	r := NewRegistry()
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next Func) Func {
+			return func(ctx *Context) error {
+				calls = append(calls, name+" before")
+				err := next(ctx)
+				calls = append(calls, name+" after")
+				return err
+			}
+		}
+	}
+	r.Use(trace("first"))
+	r.Use(trace("second"))
+	r.Register("USDC:Transfer", func(*Context) error {
+		calls = append(calls, "handler")
+		return nil
+	})
+
+	// Applied in registration order, first outermost
+	event := &decoder.DecodedEvent{EventID: "USDC:Transfer", ContractName: "USDC", EventName: "Transfer"}
+	require.NoError(t, r.Handle(&Context{Event: event}))
+	require.Equal(t, []string{"first before", "second before", "handler", "second after", "first after"}, calls)
+
+	// Nil events and events without a handler never reach middleware
+	calls = nil
+	require.ErrorContains(t, r.Handle(&Context{}), "event is nil")
+	require.NoError(t, r.Handle(&Context{Event: &decoder.DecodedEvent{EventID: "DAI:Transfer"}}))
+	require.Empty(t, calls)
+}
+
+func TestRecover(t *testing.T) {
+	r := NewRegistry()
+	r.Use(Recover())
+	r.Register("USDC:Transfer", func(*Context) error { panic("boom") })
+
+	event := &decoder.DecodedEvent{EventID: "USDC:Transfer", ContractName: "USDC", EventName: "Transfer"}
+	err := r.Handle(&Context{Event: event})
+	require.ErrorIs(t, err, ErrHandlerPanic)
+	require.ErrorContains(t, err, "boom")
+}
+
+func TestTiming(t *testing.T) {
+	histogram := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "test_handler_seconds"}, []string{"contract", "event"})
+	r := NewRegistry()
+	r.Use(Timing(histogram))
+	r.Register("USDC:Transfer", func(*Context) error { return errors.New("failed") })
+
+	// Failed handlers are timed too
+	event := &decoder.DecodedEvent{EventID: "USDC:Transfer", ContractName: "USDC", EventName: "Transfer"}
+	require.Error(t, r.Handle(&Context{Event: event}))
+	require.Equal(t, 1, testutil.CollectAndCount(histogram))
+}
+
+func TestWildcardHandlers(t *testing.T) {
+	r := NewRegistry()
+	var ran string
+	register := func(id string) {
+		r.Register(id, func(*Context) error { ran = id; return nil })
+	}
+	register("USDC:Transfer")
+	register("USDC:*")
+	register("*")
+
+	tests := []struct {
+		eventID string
+		want    string
+	}{
+		{eventID: "USDC:Transfer", want: "USDC:Transfer"},
+		{eventID: "USDC:Approval", want: "USDC:*"},
+		{eventID: "DAI:Transfer", want: "*"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.eventID, func(t *testing.T) {
+			require.True(t, r.HasHandler(tt.eventID))
+			id, ok := r.Match(tt.eventID)
+			require.True(t, ok)
+			require.Equal(t, tt.want, id)
+
+			contract, event, _ := strings.Cut(tt.eventID, ":")
+			require.NoError(t, r.Handle(&Context{Event: &decoder.DecodedEvent{EventID: tt.eventID, ContractName: contract, EventName: event}}))
+			require.Equal(t, tt.want, ran)
+		})
+	}
+
+	// Get stays exact
+	_, ok := r.Get("USDC:Approval")
+	require.False(t, ok)
+	require.ElementsMatch(t, []string{"USDC:Transfer", "USDC:*", "*"}, r.ListHandlers())
+
+	// Without "*", unmatched events have no handler
+	r = NewRegistry()
+	r.Register("USDC:*", func(*Context) error { return nil })
+	require.False(t, r.HasHandler("DAI:Transfer"))
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// Logger receives the package's log entries. *zerolog.Logger implements
+// it, as do loggers filtering entries at a configurable level.
+type Logger interface {
+	Debug() *zerolog.Event
+	Info() *zerolog.Event
+	Warn() *zerolog.Event
+	Error() *zerolog.Event
+}
+
+// loggerBox holds a Logger in an atomic.Value, which needs one concrete type.
+type loggerBox struct{ Logger }
+
+var logger atomic.Value // loggerBox
+
+// SetLogger sets the logger of handler registration, dispatch and panic
+// recovery messages. Defaults to zerolog's global logger.
+//
+// Parameters:
+//   - l (Logger): logger to use
+func SetLogger(l Logger) {
+	logger.Store(loggerBox{l})
+}
+
+// log forwards to the logger set by SetLogger.
+var log delegate
+
+type delegate struct{}
+
+func (delegate) current() Logger {
+	if box, ok := logger.Load().(loggerBox); ok {
+		return box.Logger
+	}
+	return &zlog.Logger
+}
+
+func (d delegate) Debug() *zerolog.Event { return d.current().Debug() }
+func (d delegate) Info() *zerolog.Event  { return d.current().Info() }
+func (d delegate) Warn() *zerolog.Event  { return d.current().Warn() }
+func (d delegate) Error() *zerolog.Event { return d.current().Error() }
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ErrHandlerPanic is returned by handlers wrapped in Recover that panicked.
+var ErrHandlerPanic = errors.New("handler panicked")
+
+// Middleware wraps a handler with cross-cutting behavior (logging, timing,
+// panic recovery). It returns a handler that usually calls next.
+type Middleware func(next Func) Func
+
+// Use adds a middleware to the global registry.
+//
+// Parameters:
+//   - mw (Middleware): middleware wrapping every handler
+func Use(mw Middleware) {
+	globalRegistry.Use(mw)
+}
+
+// Use adds a middleware wrapping every handler run by Handle. Middlewares
+// apply in registration order: the first one added is the outermost and
+// sees the event first.
+//
+// Parameters:
+//   - mw (Middleware): middleware wrapping every handler
+func (r *Registry) Use(mw Middleware) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.middleware = append(r.middleware, mw)
+}
+
+// wrap applies the registry's middleware to a handler.
+//
+// Parameters:
+//   - handler (Func): the registered handler
+//
+// Returns:
+//   - Func: handler wrapped in every middleware
+func (r *Registry) wrap(handler Func) Func {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for i := len(r.middleware) - 1; i >= 0; i-- {
+		handler = r.middleware[i](handler)
+	}
+	return handler
+}
+
+// Recover returns a middleware turning a handler panic into an
+// ErrHandlerPanic error, so the batch is rolled back and retried instead
+// of the panic crashing the engine. The stack trace is logged.
+//
+// Returns:
+//   - Middleware: the panic recovery middleware
+func Recover() Middleware {
+	return func(next Func) Func {
+		return func(ctx *Context) (err error) {
+			defer func() {
+				if p := recover(); p != nil {
+					log.Error().
+						Str("eventID", ctx.Event.EventID).
+						Uint64("block", ctx.Block.Number).
+						Str("txHash", ctx.Log.TxHash.Hex()).
+						Str("stack", string(debug.Stack())).
+						Msgf("handler panicked: %v", p)
+					err = fmt.Errorf("%w: %v", ErrHandlerPanic, p)
+				}
+			}()
+			return next(ctx)
+		}
+	}
+}
+
+// Timing returns a middleware observing the duration of the handler it
+// wraps, including middlewares added after it, in a histogram labelled by
+// contract and event.
+//
+// Parameters:
+//   - histogram (prometheus.ObserverVec): histogram with "contract" and "event" labels
+//
+// Returns:
+//   - Middleware: the timing middleware
+func Timing(histogram prometheus.ObserverVec) Middleware {
+	return func(next Func) Func {
+		return func(ctx *Context) error {
+			start := time.Now()
+			err := next(ctx)
+			histogram.WithLabelValues(ctx.Event.ContractName, ctx.Event.EventName).Observe(time.Since(start).Seconds())
+			return err
+		}
+	}
+}
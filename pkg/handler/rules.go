@@ -0,0 +1,300 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"gorm.io/gorm/clause"
+
+	"github.com/0xredeth/Rafale/pkg/config"
+)
+
+// RuleBroadcast publishes an event matched by a broadcasting rule. Data
+// holds the mapped columns, or the full decoded data if the rule maps none.
+type RuleBroadcast func(ctx *Context, rule string, data map[string]any)
+
+// Chain returns a handler running each handler in order, stopping at the
+// first error. Used to combine a Go handler with config rules for the same event.
+//
+// Parameters:
+//   - handlers (...Func): handlers to run
+//
+// Returns:
+//   - Func: the combined handler
+func Chain(handlers ...Func) Func {
+	return func(ctx *Context) error {
+		for _, h := range handlers {
+			if err := h(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// RuleTableDDL returns the statements creating a rule's table if missing.
+// Columns are typed from the event ABI; a unique (tx_hash, log_index) index
+// makes replayed batches insert each event once.
+//
+// Parameters:
+//   - rule (config.RuleConfig): the rule (Table must be set)
+//   - inputs (abi.Arguments): the event's ABI inputs
+//
+// Returns:
+//   - []string: SQL statements to execute in order
+//   - error: nil on success, error if a mapped field is not an event input
+func RuleTableDDL(rule config.RuleConfig, inputs abi.Arguments) ([]string, error) {
+	columns := sortedColumns(rule.Columns)
+	defs := []string{
+		"id BIGSERIAL PRIMARY KEY",
+		"block_number BIGINT NOT NULL",
+		"tx_hash VARCHAR(66) NOT NULL",
+		"log_index INTEGER NOT NULL",
+		"timestamp TIMESTAMPTZ NOT NULL",
+	}
+	for _, column := range columns {
+		arg, ok := findInput(inputs, rule.Columns[column])
+		if !ok {
+			return nil, fmt.Errorf("rule %s: column %s: event has no field %q", rule.Name, column, rule.Columns[column])
+		}
+		defs = append(defs, fmt.Sprintf("%q %s", column, columnType(arg.Type)))
+	}
+
+	return []string{
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %q (%s)", rule.Table, strings.Join(defs, ", ")),
+		fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS %q ON %q (tx_hash, log_index)", rule.Table+"_log_key", rule.Table),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %q ON %q (block_number)", rule.Table+"_block_number", rule.Table),
+	}, nil
+}
+
+// NewRuleHandler builds a handler that applies a rule: events passing all
+// predicates are inserted into the rule's table and/or broadcast.
+//
+// Parameters:
+//   - rule (config.RuleConfig): the rule
+//   - inputs (abi.Arguments): the event's ABI inputs
+//   - broadcast (RuleBroadcast): publisher for broadcasting rules (may be nil)
+//
+// Returns:
+//   - Func: the rule handler
+//   - error: nil on success, error if a field or predicate value doesn't fit the ABI
+func NewRuleHandler(rule config.RuleConfig, inputs abi.Arguments, broadcast RuleBroadcast) (Func, error) {
+	for column, field := range rule.Columns {
+		if _, ok := findInput(inputs, field); !ok {
+			return nil, fmt.Errorf("rule %s: column %s: event has no field %q", rule.Name, column, field)
+		}
+	}
+
+	predicates := make([]func(map[string]any) bool, 0, len(rule.Where))
+	for _, p := range rule.Where {
+		pred, err := compilePredicate(p, inputs)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", rule.Name, err)
+		}
+		predicates = append(predicates, pred)
+	}
+
+	columns := sortedColumns(rule.Columns)
+
+	return func(ctx *Context) error {
+		for _, pred := range predicates {
+			if !pred(ctx.Event.Data) {
+				return nil
+			}
+		}
+
+		mapped := make(map[string]any, len(columns))
+		for _, column := range columns {
+			mapped[column] = columnValue(ctx, ctx.Event.Data[rule.Columns[column]])
+		}
+
+		if rule.Table != "" {
+			row := map[string]any{
+				"block_number": ctx.Block.Number,
+				"tx_hash":      ctx.Log.TxHash.Hex(),
+				"log_index":    ctx.Log.Index,
+				"timestamp":    ctx.Block.Time,
+			}
+			for column, v := range mapped {
+				row[column] = v
+			}
+			if err := ctx.DB.Table(rule.Table).
+				Clauses(clause.OnConflict{DoNothing: true}).
+				Create(row).Error; err != nil {
+				return fmt.Errorf("rule %s: inserting into %s: %w", rule.Name, rule.Table, err)
+			}
+		}
+
+		if rule.Broadcast && broadcast != nil {
+			if len(columns) == 0 {
+				mapped = ctx.Event.Data
+			}
+			broadcast(ctx, rule.Name, mapped)
+		}
+		return nil
+	}, nil
+}
+
+// compilePredicate parses a predicate's constant according to the field's
+// ABI type and returns a matcher over decoded event data.
+func compilePredicate(p config.PredicateConfig, inputs abi.Arguments) (func(map[string]any) bool, error) {
+	arg, ok := findInput(inputs, p.Field)
+	if !ok {
+		return nil, fmt.Errorf("where: event has no field %q", p.Field)
+	}
+
+	ordered := p.Op != config.OpEq && p.Op != config.OpNe
+	switch arg.Type.T {
+	case abi.IntTy, abi.UintTy:
+		want, ok := new(big.Int).SetString(p.Value, 0)
+		if !ok {
+			return nil, fmt.Errorf("where %s: %q is not an integer", p.Field, p.Value)
+		}
+		return func(data map[string]any) bool {
+			got, ok := toBigInt(data[p.Field])
+			return ok && compareResult(got.Cmp(want), p.Op)
+		}, nil
+
+	case abi.AddressTy:
+		if ordered || !common.IsHexAddress(p.Value) {
+			return nil, fmt.Errorf("where %s: want eq/ne with an address", p.Field)
+		}
+		want := common.HexToAddress(p.Value)
+		return func(data map[string]any) bool {
+			got, ok := data[p.Field].(common.Address)
+			return ok && (got == want) == (p.Op == config.OpEq)
+		}, nil
+
+	case abi.BoolTy:
+		want, err := strconv.ParseBool(p.Value)
+		if ordered || err != nil {
+			return nil, fmt.Errorf("where %s: want eq/ne with true or false", p.Field)
+		}
+		return func(data map[string]any) bool {
+			got, ok := data[p.Field].(bool)
+			return ok && (got == want) == (p.Op == config.OpEq)
+		}, nil
+
+	case abi.StringTy:
+		if ordered {
+			return nil, fmt.Errorf("where %s: strings support only eq/ne", p.Field)
+		}
+		return func(data map[string]any) bool {
+			got, ok := data[p.Field].(string)
+			return ok && (got == p.Value) == (p.Op == config.OpEq)
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("where %s: unsupported type %s", p.Field, arg.Type)
+	}
+}
+
+// compareResult applies an operator to a Cmp result.
+func compareResult(cmp int, op string) bool {
+	switch op {
+	case config.OpEq:
+		return cmp == 0
+	case config.OpNe:
+		return cmp != 0
+	case config.OpGt:
+		return cmp > 0
+	case config.OpGte:
+		return cmp >= 0
+	case config.OpLt:
+		return cmp < 0
+	case config.OpLte:
+		return cmp <= 0
+	}
+	return false
+}
+
+// toBigInt converts a decoded integer (big.Int or a sized Go integer) to *big.Int.
+func toBigInt(v any) (*big.Int, bool) {
+	if b, ok := v.(*big.Int); ok {
+		return b, b != nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Int).SetUint64(rv.Uint()), true
+	}
+	return nil, false
+}
+
+// columnValue converts a decoded value for insertion.
+func columnValue(ctx *Context, v any) any {
+	switch val := v.(type) {
+	case nil:
+		return nil
+	case common.Address:
+		return ctx.FormatAddress(val)
+	case bool, string:
+		return val
+	case []byte:
+		return hexutil.Encode(val)
+	}
+	if b, ok := toBigInt(v); ok {
+		return b.String()
+	}
+
+	// Fixed-size byte arrays
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Array && rv.Type().Elem().Kind() == reflect.Uint8 {
+		buf := make([]byte, rv.Len())
+		reflect.Copy(reflect.ValueOf(buf), rv)
+		return hexutil.Encode(buf)
+	}
+
+	// Arrays and tuples go to jsonb
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// columnType maps an ABI type to a Postgres column type.
+func columnType(t abi.Type) string {
+	switch t.T {
+	case abi.AddressTy:
+		return "VARCHAR(42)"
+	case abi.IntTy, abi.UintTy:
+		return "NUMERIC(78)"
+	case abi.BoolTy:
+		return "BOOLEAN"
+	case abi.StringTy, abi.BytesTy, abi.FixedBytesTy:
+		return "TEXT"
+	default:
+		return "JSONB"
+	}
+}
+
+// findInput returns the event input named field.
+func findInput(inputs abi.Arguments, field string) (abi.Argument, bool) {
+	for _, arg := range inputs {
+		if arg.Name == field {
+			return arg, true
+		}
+	}
+	return abi.Argument{}, false
+}
+
+// sortedColumns returns the mapped column names in stable order.
+func sortedColumns(columns map[string]string) []string {
+	names := make([]string, 0, len(columns))
+	for name := range columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -5,7 +5,6 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/rs/zerolog/log"
 
 	"github.com/0xredeth/Rafale/internal/store"
 )
@@ -60,14 +59,16 @@ func handleUSDCTransfer(ctx *Context) error {
 			LogIndex:    ctx.Log.Index,
 			Timestamp:   ctx.Block.Time,
 		},
-		From:  from.Hex(),
-		To:    to.Hex(),
-		Value: value.String(),
+		ContractAddr: ctx.FormatAddress(ctx.Log.Address),
+		From:         ctx.FormatAddress(from),
+		To:           ctx.FormatAddress(to),
+		Value:        value.String(),
 	}
 
-	// Insert into database
-	if err := ctx.DB.Create(&transfer).Error; err != nil {
-		return fmt.Errorf("inserting transfer: %w", err)
+	// Upsert into the contract's transfers table, so a re-processed block
+	// doesn't duplicate it
+	if err := store.UpsertTransfersTx(ctx.DB, store.TransferTable(ctx.Event.ContractName), []store.Transfer{transfer}); err != nil {
+		return err
 	}
 
 	log.Debug().